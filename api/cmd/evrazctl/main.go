@@ -0,0 +1,243 @@
+// cmd/evrazctl/main.go
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"evraz_api/internal/dto"
+)
+
+// Exit codes: a failed gate is told apart from a failure to evaluate it or to export, and
+// from a project that has not been analyzed yet
+const (
+	exitPassed      = 0
+	exitFailed      = 1
+	exitError       = 2
+	exitNotAnalyzed = 3
+)
+
+const usage = `Usage: evrazctl <command> [flags]
+
+Commands:
+  gate    Check the latest analysis run of a project against quality gate policies
+          (exit code 0: passed, 1: failed, 2: error, 3: the project has no completed run)
+  export  Write the findings of a project as SARIF, Code Climate JSON or JUnit XML
+
+Run "evrazctl <command> -h" for the flags of a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitError)
+	}
+
+	switch os.Args[1] {
+	case "gate":
+		os.Exit(runGate(os.Args[2:]))
+	case "export":
+		os.Exit(runExport(os.Args[2:]))
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitError)
+	}
+}
+
+// client calls the API of an evraz server
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newClient(server string) *client {
+	return &client{
+		baseURL:    strings.TrimRight(server, "/") + "/api",
+		httpClient: &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// apiError is a response of the server with a status other than 200 OK
+type apiError struct {
+	StatusCode int
+	Status     string
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("server returned %s: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("server returned %s", e.Status)
+}
+
+// get requests a path of the API and returns the body of a successful response; other
+// responses are returned as an *apiError
+func (c *client) get(path string, query url.Values) ([]byte, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	resp, err := c.httpClient.Get(target)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var errorBody struct {
+			Error string `json:"error"`
+		}
+		_ = json.Unmarshal(body, &errorBody)
+		return nil, &apiError{StatusCode: resp.StatusCode, Status: resp.Status, Message: errorBody.Error}
+	}
+	return body, nil
+}
+
+// commonFlags registers the flags every command takes
+func commonFlags(flags *flag.FlagSet) (server *string, projectID *uint) {
+	defaultServer := os.Getenv("EVRAZ_API_URL")
+	if defaultServer == "" {
+		defaultServer = "http://localhost:8080"
+	}
+	server = flags.String("server", defaultServer, "Base URL of the evraz server (default from EVRAZ_API_URL)")
+	projectID = flags.Uint("project", 0, "ID of the project (version)")
+	return server, projectID
+}
+
+func runGate(args []string) int {
+	flags := flag.NewFlagSet("gate", flag.ContinueOnError)
+	server, projectID := commonFlags(flags)
+	policy := flags.String("policy", "", "Comma-separated gate policies (default: the default policy)")
+	printJSON := flags.Bool("json", false, "Print the gate result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *projectID == 0 {
+		fmt.Fprintln(os.Stderr, "The -project flag is required")
+		return exitError
+	}
+
+	query := url.Values{}
+	if *policy != "" {
+		query.Set("policy", *policy)
+	}
+	body, err := newClient(*server).get(fmt.Sprintf("/projects/%d/gate", *projectID), query)
+	var responseError *apiError
+	if errors.As(err, &responseError) && responseError.StatusCode == http.StatusConflict {
+		fmt.Fprintf(os.Stderr, "Project %d has not been analyzed yet: %s\n", *projectID, responseError.Message)
+		return exitNotAnalyzed
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to evaluate the quality gate: %v\n", err)
+		return exitError
+	}
+	var result dto.GateResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid gate response: %v\n", err)
+		return exitError
+	}
+
+	if *printJSON {
+		os.Stdout.Write(body)
+		fmt.Println()
+	} else {
+		printGate(result)
+	}
+	if !result.Passed {
+		return exitFailed
+	}
+	return exitPassed
+}
+
+func printGate(result dto.GateResponse) {
+	verdict := "PASSED"
+	if !result.Passed {
+		verdict = "FAILED"
+	}
+	fmt.Printf("Quality gate %s: %s (run %d", strings.Join(result.Policies, ", "), verdict, result.RunID)
+	if result.PreviousRunID != nil {
+		fmt.Printf(", compared with run %d", *result.PreviousRunID)
+	}
+	if result.Score != nil {
+		fmt.Printf(", score %.1f, grade %s", *result.Score, result.Grade)
+	}
+	fmt.Println(")")
+
+	for _, violation := range result.Violations {
+		fmt.Printf("  [%s] %s: %s\n", violation.Policy, violation.Condition, violation.Message)
+		for _, finding := range violation.Findings {
+			location := finding.Path
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", finding.Path, finding.Line)
+			}
+			if location != "" {
+				location += " "
+			}
+			fmt.Printf("      %s%s: %s\n", location, finding.RuleName, finding.Message)
+		}
+	}
+}
+
+// exportFormats lists the formats of the export command with their API endpoints
+var exportFormats = map[string]string{
+	"sarif":       "sarif",
+	"codeclimate": "codeclimate",
+	"gitlab":      "codeclimate", // GitLab reads code quality reports in the Code Climate format
+	"junit":       "junit",
+}
+
+func runExport(args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	server, projectID := commonFlags(flags)
+	format := flags.String("format", "sarif", "Report format: sarif, codeclimate (or gitlab) or junit")
+	output := flags.String("o", "", "Write the report to this file instead of standard output")
+	language := flags.String("lang", "", "Language of the rule titles of a SARIF report")
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+	if *projectID == 0 {
+		fmt.Fprintln(os.Stderr, "The -project flag is required")
+		return exitError
+	}
+	endpoint, ok := exportFormats[strings.ToLower(*format)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, expected sarif, codeclimate or junit\n", *format)
+		return exitError
+	}
+
+	query := url.Values{}
+	if *language != "" {
+		query.Set("lang", *language)
+	}
+	body, err := newClient(*server).get(fmt.Sprintf("/projects/%d/export/%s", *projectID, endpoint), query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to export the project: %v\n", err)
+		return exitError
+	}
+
+	if *output == "" {
+		os.Stdout.Write(body)
+		return exitPassed
+	}
+	if err := os.WriteFile(*output, body, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write the report: %v\n", err)
+		return exitError
+	}
+	return exitPassed
+}
@@ -0,0 +1,22 @@
+"""Order application services."""
+import logging
+
+from app.application.errors import OrderNotFound
+from app.application.interfaces import OrdersRepo
+
+logger = logging.getLogger(__name__)
+
+
+class OrderService:
+    """Manages the order lifecycle."""
+
+    def __init__(self, orders_repo: OrdersRepo):
+        self.orders_repo = orders_repo
+
+    def close(self, order_id: int) -> None:
+        """Close an existing order."""
+        order = self.orders_repo.get(order_id)
+        if order is None:
+            raise OrderNotFound(order_id=order_id)
+        order.close()
+        logger.info('Order %s closed', order_id)
@@ -0,0 +1,14 @@
+import logging
+
+logger = logging.getLogger(__name__)
+
+
+def charge(order_id, amount):
+    """Charge the customer for the given order."""
+    try:
+        result = gateway.charge(order_id, amount)
+    except Exception:
+        logger.error(f"Charge failed for {order_id}")
+        return None
+    logger.info(f"Charged {amount} for {order_id}")
+    return result
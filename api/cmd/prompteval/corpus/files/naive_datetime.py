@@ -0,0 +1,9 @@
+from datetime import datetime
+
+
+def report_name():
+    return 'report_' + datetime.now().strftime('%Y%m%d') + '.csv'
+
+
+def is_expired(expires_at):
+    return expires_at < datetime.now()
@@ -0,0 +1,11 @@
+from sqlalchemy.orm import Session
+
+
+class UserService:
+    def __init__(self, session: Session):
+        self.session = session
+
+    def rename(self, user_id, name):
+        user = self.session.query(User).get(user_id)
+        user.name = name
+        self.session.commit()
@@ -0,0 +1,8 @@
+"""HTTP adapter."""
+from app.application.services import Greeter
+
+greeter = Greeter()
+
+
+def on_get(req, resp):
+    resp.media = {'greeting': greeter.greet(req.params['name'])}
@@ -0,0 +1,9 @@
+"""Application services."""
+
+
+class Greeter:
+    """Greets people."""
+
+    def greet(self, name: str) -> str:
+        """Return a greeting."""
+        return 'Hello, %s' % name
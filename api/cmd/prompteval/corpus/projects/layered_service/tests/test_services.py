@@ -0,0 +1,5 @@
+from app.application.services import Greeter
+
+
+def test_greet():
+    assert Greeter().greet('Bob') == 'Hello, Bob'
@@ -0,0 +1,82 @@
+// cmd/prompteval/main.go
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+
+	"evraz_api/internal/prompteval"
+)
+
+func main() {
+	corpusPath := flag.String("corpus", "cmd/prompteval/corpus/corpus.json", "Path to the labelled corpus manifest")
+	versionA := flag.String("a", "", "Prompt version file to evaluate (empty: compiled-in prompts)")
+	versionB := flag.String("b", "", "Optional second prompt version file to compare against -a")
+	repliesPath := flag.String("replies", "", "Replay recorded replies from this file instead of calling the LLM")
+	recordPath := flag.String("record", "", "Record live replies to this file")
+	language := flag.String("language", "Russian (русский)", "Response language: a code such as ru or en, or the language name passed to the prompts")
+	pricePer1K := flag.Float64("price", 0, "Price per 1000 tokens used for the cost column")
+	jsonPath := flag.String("json", "", "Write the machine-readable report to this file")
+	flag.Parse()
+
+	corpus, err := prompteval.LoadCorpus(*corpusPath)
+	if err != nil {
+		log.Fatalf("Failed to load corpus: %v", err)
+	}
+
+	var llm prompteval.LLM
+	var liveLLM *prompteval.LiveLLM
+	if *repliesPath != "" {
+		llm, err = prompteval.LoadReplayLLM(*repliesPath)
+		if err != nil {
+			log.Fatalf("Failed to load recorded replies: %v", err)
+		}
+	} else {
+		liveLLM = prompteval.NewLiveLLM()
+		llm = liveLLM
+	}
+
+	runner := prompteval.NewRunner(corpus, llm, *language)
+
+	var reports []*prompteval.VersionReport
+	versionPaths := []string{*versionA}
+	if *versionB != "" {
+		versionPaths = append(versionPaths, *versionB)
+	}
+	for _, path := range versionPaths {
+		version, err := prompteval.LoadVersion(path)
+		if err != nil {
+			log.Fatalf("Failed to load prompt version: %v", err)
+		}
+		report, err := runner.Run(version)
+		if err != nil {
+			log.Fatalf("Failed to evaluate version %s: %v", version.Name, err)
+		}
+		reports = append(reports, report)
+	}
+
+	if liveLLM != nil && *recordPath != "" {
+		if err := liveLLM.SaveRecording(*recordPath); err != nil {
+			log.Fatalf("Failed to save recorded replies: %v", err)
+		}
+	}
+
+	if len(reports) == 2 {
+		prompteval.WriteComparison(os.Stdout, reports[0], reports[1], *pricePer1K)
+	} else {
+		prompteval.WriteReport(os.Stdout, reports[0], *pricePer1K)
+	}
+
+	if *jsonPath != "" {
+		raw, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode report: %v", err)
+		}
+		if err := os.WriteFile(*jsonPath, raw, 0644); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	}
+}
@@ -33,6 +33,26 @@ func main() {
 		&model.ProjectFile{},
 		&model.ProjectAnalysisResult{},
 		&model.FileAnalysisResult{},
+		&model.AnalysisTranslation{},
+		&model.Finding{},
+		&model.FindingComment{},
+		&model.FindingEvent{},
+		&model.SuppressionRule{},
+		&model.Baseline{},
+		&model.BaselineFingerprint{},
+		&model.FileCoverage{},
+		&model.AnalysisRun{},
+		&model.AnalysisRunPromptStat{},
+		&model.AnalysisRunFinding{},
+		&model.AnalysisRunCompliance{},
+		&model.AnalysisRunCategoryScore{},
+		&model.AgentToolCall{},
+		&model.PackageSummary{},
+		&model.CodeChunk{},
+		&model.QAMessage{},
+		&model.PatchReview{},
+		&model.PatchReviewFile{},
+		&model.PatchFinding{},
 	); err != nil {
 		log.Fatalf("Failed to automigrate: %v", err)
 	}
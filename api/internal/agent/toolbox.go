@@ -0,0 +1,224 @@
+// internal/agent/toolbox.go
+
+package agent
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+// Tool names the agent can call
+const (
+	ToolListDir  = "list_dir"
+	ToolReadFile = "read_file"
+	ToolGrep     = "grep"
+)
+
+const (
+	maxReadLines   = 200  // Lines returned by a single read_file call
+	maxGrepMatches = 50   // Matches returned by a single grep call
+	maxResultSize  = 8000 // Bytes of any tool result, cut on a rune boundary
+)
+
+// Toolbox answers the agent's tool calls from the stored files of a project
+type Toolbox struct {
+	files  map[string]*model.ProjectFile // Normalized path -> file
+	sorted []string                      // Normalized paths in order
+}
+
+// NewToolbox indexes the files of a project
+func NewToolbox(files []model.ProjectFile) *Toolbox {
+	toolbox := &Toolbox{files: make(map[string]*model.ProjectFile, len(files))}
+	for i := range files {
+		filePath := normalizePath(files[i].Path)
+		toolbox.files[filePath] = &files[i]
+		toolbox.sorted = append(toolbox.sorted, filePath)
+	}
+	sort.Strings(toolbox.sorted)
+	return toolbox
+}
+
+// Definitions describes the tools for the chat API
+func (t *Toolbox) Definitions() []service.ToolDefinition {
+	return []service.ToolDefinition{
+		{
+			Type: "function",
+			Function: service.ToolFunction{
+				Name:        ToolListDir,
+				Description: "List the files and subdirectories of a project directory. Use an empty path for the project root.",
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path": map[string]any{"type": "string", "description": "Directory path relative to the project root"},
+					},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: service.ToolFunction{
+				Name:        ToolReadFile,
+				Description: fmt.Sprintf("Read numbered lines of a project file, at most %d lines per call.", maxReadLines),
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"path":  map[string]any{"type": "string", "description": "File path relative to the project root"},
+						"start": map[string]any{"type": "integer", "description": "First line to read, starting from 1"},
+						"end":   map[string]any{"type": "integer", "description": "Last line to read, inclusive"},
+					},
+					"required": []string{"path"},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: service.ToolFunction{
+				Name:        ToolGrep,
+				Description: fmt.Sprintf("Search all project files for a regular expression and return up to %d matching lines with their paths and line numbers.", maxGrepMatches),
+				Parameters: map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"pattern": map[string]any{"type": "string", "description": "Regular expression in Go syntax"},
+					},
+					"required": []string{"pattern"},
+				},
+			},
+		},
+	}
+}
+
+// Execute runs a tool call with its JSON-encoded arguments
+func (t *Toolbox) Execute(name, arguments string) (string, error) {
+	var args struct {
+		Path    string `json:"path"`
+		Start   int    `json:"start"`
+		End     int    `json:"end"`
+		Pattern string `json:"pattern"`
+	}
+	if strings.TrimSpace(arguments) != "" {
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return "", fmt.Errorf("invalid arguments: %w", err)
+		}
+	}
+
+	var result string
+	var err error
+	switch name {
+	case ToolListDir:
+		result, err = t.listDir(args.Path)
+	case ToolReadFile:
+		result, err = t.readFile(args.Path, args.Start, args.End)
+	case ToolGrep:
+		result, err = t.grep(args.Pattern)
+	default:
+		return "", fmt.Errorf("unknown tool: %s", name)
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(result) > maxResultSize {
+		result = utils.TruncateBytes(result, maxResultSize) + "\n... (truncated)"
+	}
+	return result, nil
+}
+
+func (t *Toolbox) listDir(dir string) (string, error) {
+	dir = normalizePath(dir)
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+
+	seen := make(map[string]bool)
+	var entries []string
+	for _, filePath := range t.sorted {
+		if !strings.HasPrefix(filePath, prefix) {
+			continue
+		}
+		entry := strings.TrimPrefix(filePath, prefix)
+		if i := strings.Index(entry, "/"); i >= 0 {
+			entry = entry[:i+1]
+		}
+		if !seen[entry] {
+			seen[entry] = true
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("directory not found: %s", dir)
+	}
+	return strings.Join(entries, "\n"), nil
+}
+
+func (t *Toolbox) readFile(filePath string, start, end int) (string, error) {
+	file, ok := t.files[normalizePath(filePath)]
+	if !ok {
+		return "", fmt.Errorf("file not found: %s", filePath)
+	}
+
+	lines := strings.Split(file.Content, "\n")
+	if start < 1 {
+		start = 1
+	}
+	if end < start || end > len(lines) {
+		end = len(lines)
+	}
+	if end-start+1 > maxReadLines {
+		end = start + maxReadLines - 1
+	}
+	if start > len(lines) {
+		return "", fmt.Errorf("%s has only %d lines", filePath, len(lines))
+	}
+
+	var sb strings.Builder
+	for i := start; i <= end; i++ {
+		sb.WriteString(fmt.Sprintf("%d: %s\n", i, lines[i-1]))
+	}
+	if end < len(lines) {
+		sb.WriteString(fmt.Sprintf("... (%d of %d lines shown)\n", end-start+1, len(lines)))
+	}
+	return sb.String(), nil
+}
+
+func (t *Toolbox) grep(pattern string) (string, error) {
+	if pattern == "" {
+		return "", fmt.Errorf("pattern is required")
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	var matches []string
+	for _, filePath := range t.sorted {
+		for i, line := range strings.Split(t.files[filePath].Content, "\n") {
+			if !re.MatchString(line) {
+				continue
+			}
+			if len(matches) == maxGrepMatches {
+				return strings.Join(matches, "\n") + "\n... (more matches omitted)", nil
+			}
+			matches = append(matches, fmt.Sprintf("%s:%d: %s", filePath, i+1, strings.TrimSpace(line)))
+		}
+	}
+	if len(matches) == 0 {
+		return "no matches", nil
+	}
+	return strings.Join(matches, "\n"), nil
+}
+
+func normalizePath(filePath string) string {
+	filePath = strings.Trim(path.Clean("/"+strings.TrimSpace(filePath)), "/")
+	if filePath == "." {
+		return ""
+	}
+	return filePath
+}
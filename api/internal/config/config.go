@@ -5,10 +5,20 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 type Config struct {
-	DatabaseURL string
+	DatabaseURL         string
+	SupportedLanguages  []string // Locale codes reports can be produced in, e.g. "ru", "en"
+	DefaultLanguage     string
+	DedupEmbeddings     bool    // Compare findings by embeddings from the LLM provider when deduplicating
+	CriticEnabled       bool    // Verify review findings with a second critic prompt
+	CriticMinConfidence float64 // Findings below this critic confidence are hidden
+	AgentEnabled        bool    // Run project-level checks as an agent that can fetch files
+	AgentMaxSteps       int     // Model turns an agent check may take
+	AgentMaxTokens      int     // Tokens an agent check may spend
 }
 
 func LoadConfig() (*Config, error) {
@@ -22,7 +32,83 @@ func LoadConfig() (*Config, error) {
 	databaseURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		user, password, host, port, dbName, sslMode)
 
+	// Load report language configurations; codes are compared like the i18n catalog does
+	var supportedLanguages []string
+	for _, language := range strings.Split(os.Getenv("SUPPORTED_LANGUAGES"), ",") {
+		if language = normalizeLanguage(language); language != "" {
+			supportedLanguages = append(supportedLanguages, language)
+		}
+	}
+	if len(supportedLanguages) == 0 {
+		supportedLanguages = []string{"ru", "en"}
+	}
+	defaultLanguage := normalizeLanguage(os.Getenv("DEFAULT_LANGUAGE"))
+	if defaultLanguage == "" {
+		defaultLanguage = supportedLanguages[0]
+	}
+	if !containsLanguage(supportedLanguages, defaultLanguage) {
+		return nil, fmt.Errorf("invalid DEFAULT_LANGUAGE %q: must be one of SUPPORTED_LANGUAGES %s", defaultLanguage, strings.Join(supportedLanguages, ","))
+	}
+
+	// Load finding deduplication configurations
+	dedupEmbeddings := os.Getenv("DEDUP_EMBEDDINGS") == "true"
+
+	// Load critic configurations
+	criticEnabled := os.Getenv("CRITIC_ENABLED") == "true"
+	criticMinConfidence := 0.5
+	if value := os.Getenv("CRITIC_MIN_CONFIDENCE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return nil, fmt.Errorf("invalid CRITIC_MIN_CONFIDENCE %q: must be a number from 0 to 1", value)
+		}
+		criticMinConfidence = parsed
+	}
+
+	// Load review agent configurations
+	agentEnabled := os.Getenv("AGENT_ENABLED") == "true"
+	agentMaxSteps, err := positiveIntEnv("AGENT_MAX_STEPS", 8)
+	if err != nil {
+		return nil, err
+	}
+	agentMaxTokens, err := positiveIntEnv("AGENT_MAX_TOKENS", 32000)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
-		DatabaseURL: databaseURL,
+		DatabaseURL:         databaseURL,
+		SupportedLanguages:  supportedLanguages,
+		DefaultLanguage:     defaultLanguage,
+		DedupEmbeddings:     dedupEmbeddings,
+		CriticEnabled:       criticEnabled,
+		CriticMinConfidence: criticMinConfidence,
+		AgentEnabled:        agentEnabled,
+		AgentMaxSteps:       agentMaxSteps,
+		AgentMaxTokens:      agentMaxTokens,
 	}, nil
 }
+
+func positiveIntEnv(name string, fallback int) (int, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be a positive integer", name, value)
+	}
+	return parsed, nil
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+func containsLanguage(languages []string, language string) bool {
+	for _, supported := range languages {
+		if supported == language {
+			return true
+		}
+	}
+	return false
+}
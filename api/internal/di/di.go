@@ -5,6 +5,7 @@ package di
 import (
 	"evraz_api/internal/config"
 	"evraz_api/internal/handler"
+	"evraz_api/internal/i18n"
 	"evraz_api/internal/repository"
 	"evraz_api/internal/service"
 	"evraz_api/internal/usecase"
@@ -30,9 +31,22 @@ func NewDIContainer(cfg *config.Config, db *gorm.DB) *DIContainer {
 	projectAnalysisRepo := repository.NewGormProjectAnalysisRepository(db)
 	fileAnalysisRepo := repository.NewGormFileAnalysisRepository(db)
 	gptCallRepo := repository.NewGormGPTCallRepository(db)
+	translationRepo := repository.NewGormAnalysisTranslationRepository(db)
+	findingRepo := repository.NewGormFindingRepository(db)
+	findingTriageRepo := repository.NewGormFindingTriageRepository(db)
+	suppressionRepo := repository.NewGormSuppressionRepository(db)
+	fileCoverageRepo := repository.NewGormFileCoverageRepository(db)
+	analysisRunRepo := repository.NewGormAnalysisRunRepository(db)
+	agentToolCallRepo := repository.NewGormAgentToolCallRepository(db)
+	packageSummaryRepo := repository.NewGormPackageSummaryRepository(db)
+	codeChunkRepo := repository.NewGormCodeChunkRepository(db)
+	qaMessageRepo := repository.NewGormQAMessageRepository(db)
+	patchReviewRepo := repository.NewGormPatchReviewRepository(db)
+	catalog := i18n.NewCatalog(cfg.SupportedLanguages, cfg.DefaultLanguage)
 
 	projectFileUsecase := usecase.NewProjectFileUsecase(projectFileRepo)
-	projectUsecase := usecase.NewProjectUsecase(projectRepo, projectFileRepo, projectAnalysisRepo, fileManager)
+	projectUsecase := usecase.NewProjectUsecase(projectRepo, projectFileRepo, projectAnalysisRepo, fileCoverageRepo, findingRepo, fileAnalysisRepo, codeChunkRepo, fileManager, catalog)
+	findingUsecase := usecase.NewFindingUsecase(projectRepo, projectFileRepo, findingRepo, findingTriageRepo, suppressionRepo)
 
 	// Initialize services
 	mistralService := service.NewMistralService(gptCallRepo)
@@ -43,7 +57,43 @@ func NewDIContainer(cfg *config.Config, db *gorm.DB) *DIContainer {
 		projectFileRepo,
 		projectAnalysisRepo,
 		fileAnalysisRepo,
+		findingRepo,
+		findingTriageRepo,
+		suppressionRepo,
+		fileCoverageRepo,
+		analysisRunRepo,
+		agentToolCallRepo,
+		packageSummaryRepo,
+		patchReviewRepo,
 		*mistralService,
+		fileManager,
+		catalog,
+		usecase.AnalysisOptions{
+			UseEmbeddings:       cfg.DedupEmbeddings,
+			CriticEnabled:       cfg.CriticEnabled,
+			CriticMinConfidence: cfg.CriticMinConfidence,
+			AgentEnabled:        cfg.AgentEnabled,
+			AgentMaxSteps:       cfg.AgentMaxSteps,
+			AgentMaxTokens:      cfg.AgentMaxTokens,
+		},
+	)
+
+	translationUsecase := usecase.NewTranslationUsecase(
+		projectRepo,
+		projectFileRepo,
+		projectAnalysisRepo,
+		translationRepo,
+		*mistralService,
+		catalog,
+	)
+
+	qaUsecase := usecase.NewQAUsecase(
+		projectRepo,
+		projectFileRepo,
+		codeChunkRepo,
+		qaMessageRepo,
+		*mistralService,
+		catalog,
 	)
 
 	// Initialize handlers
@@ -51,7 +101,11 @@ func NewDIContainer(cfg *config.Config, db *gorm.DB) *DIContainer {
 		projectUsecase,
 		projectFileUsecase,
 		projectAnalysisUsecase,
+		translationUsecase,
+		findingUsecase,
+		qaUsecase,
 		fileAnalysisRepo,
+		catalog,
 	)
 
 	return &DIContainer{
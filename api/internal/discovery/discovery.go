@@ -0,0 +1,269 @@
+// internal/discovery/discovery.go
+
+package discovery
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// Topic identifies what a project-level check needs to look at
+type Topic string
+
+const (
+	TopicSetupFiles   Topic = "setup_files"
+	TopicDependencies Topic = "dependencies"
+	TopicSettings     Topic = "settings"
+	TopicTransactions Topic = "transactions"
+	TopicAsync        Topic = "async"
+	TopicDateTime     Topic = "datetime"
+)
+
+// Candidate is a file ranked as relevant for a topic
+type Candidate struct {
+	File    model.ProjectFile
+	Score   float64
+	Reasons []string
+}
+
+// signal is a content pattern that makes a file relevant for a topic
+type signal struct {
+	pattern *regexp.Regexp
+	weight  float64
+	reason  string
+}
+
+// profile describes how files are scored for a topic
+type profile struct {
+	names       map[string]float64 // Base file name -> score
+	namePattern *regexp.Regexp     // Optional pattern for base names, scored with nameWeight
+	nameWeight  float64
+	extensions  []string // Only files with these extensions are scanned for signals; empty means all
+	signals     []signal
+	rootBonus   bool    // Prefer files close to the project root
+	minScore    float64 // Candidates scoring below are dropped
+}
+
+// maxMatchesPerSignal caps how much a single repeated pattern contributes to a score
+const maxMatchesPerSignal = 5
+
+func re(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(pattern)
+}
+
+var profiles = map[Topic]profile{
+	TopicSetupFiles: {
+		names: map[string]float64{
+			"setup.py": 10, "setup.cfg": 10, "pyproject.toml": 10,
+			"README.md": 8, "README.rst": 8, "README": 6,
+		},
+		extensions: []string{".py", ".cfg", ".toml"},
+		signals: []signal{
+			{re(`\bsetup\(`), 3, "calls setup()"},
+			{re(`(?m)^\[(project|tool\.poetry|build-system)\]`), 4, "declares package build metadata"},
+			{re(`(?m)^\[metadata\]`), 3, "declares package metadata"},
+		},
+		rootBonus: true,
+		minScore:  3,
+	},
+	TopicDependencies: {
+		names: map[string]float64{
+			"requirements.txt": 10, "Pipfile": 8, "pyproject.toml": 3, "setup.py": 2, "setup.cfg": 2,
+		},
+		namePattern: re(`^requirements.*\.(txt|in)$`),
+		nameWeight:  8,
+		extensions:  []string{".txt", ".in", ".toml", ".py", ".cfg", ""},
+		signals: []signal{
+			{re(`(?m)^[A-Za-z0-9_.\-\[\]]+\s*(==|>=|~=|<=)`), 1, "pins package versions"},
+			{re(`\binstall_requires\b`), 4, "declares install_requires"},
+			{re(`(?m)^\[tool\.poetry\.dependencies\]|(?m)^dependencies\s*=\s*\[`), 4, "declares project dependencies"},
+			{re(`(?m)^\[packages\]`), 4, "declares Pipfile packages"},
+		},
+		rootBonus: true,
+		minScore:  3,
+	},
+	TopicSettings: {
+		names: map[string]float64{
+			"settings.py": 6, "config.py": 4, "config.yaml": 3, "config.yml": 3, ".env.example": 3,
+		},
+		extensions: []string{".py", ".yaml", ".yml", ".toml", ".example"},
+		signals: []signal{
+			{re(`\bBaseSettings\b`), 5, "uses pydantic BaseSettings"},
+			{re(`\bos\.environ\b|\bos\.getenv\(|\benviron\.get\(`), 3, "reads environment variables"},
+			{re(`\bpydantic_settings\b`), 3, "imports pydantic_settings"},
+			{re(`\bclass\s+\w*(Settings|Config)\b`), 2, "defines a settings class"},
+		},
+		minScore: 3,
+	},
+	TopicTransactions: {
+		names: map[string]float64{
+			"transaction_manager.py": 6, "unit_of_work.py": 5, "uow.py": 4,
+		},
+		extensions: []string{".py"},
+		signals: []signal{
+			{re(`\.commit\(\)`), 3, "commits a session"},
+			{re(`\.rollback\(\)`), 3, "rolls back a session"},
+			{re(`\b(sessionmaker|scoped_session)\b|\bSession\(`), 3, "creates SQLAlchemy sessions"},
+			{re(`\.begin(_nested)?\(|\bwith\s+\w*session\b`), 2, "opens a transaction"},
+			{re(`\b(UnitOfWork|unit_of_work)\b`), 4, "implements a unit of work"},
+			{re(`(?m)^\s*(from|import)\s+sqlalchemy\b`), 2, "imports SQLAlchemy"},
+			{re(`@\w*transaction\w*|\batomic\(`), 3, "uses a transaction decorator"},
+		},
+		minScore: 4,
+	},
+	TopicAsync: {
+		names: map[string]float64{
+			"async_features.py": 6,
+		},
+		extensions: []string{".py"},
+		signals: []signal{
+			{re(`\basync\s+def\b`), 3, "defines coroutines"},
+			{re(`\bawait\s`), 2, "awaits coroutines"},
+			{re(`\basyncio\b`), 2, "uses asyncio"},
+			{re(`\b(gevent|greenlet)\b`), 4, "uses gevent"},
+			{re(`\b(aiohttp|anyio|trio)\b`), 2, "uses an async library"},
+		},
+		minScore: 4,
+	},
+	TopicDateTime: {
+		names: map[string]float64{
+			"datetime_utils.py": 6, "dates.py": 3, "time_utils.py": 3,
+		},
+		extensions: []string{".py"},
+		signals: []signal{
+			{re(`(?m)^\s*(import\s+datetime|from\s+datetime\s+import)`), 3, "imports datetime"},
+			{re(`\bpytz\b`), 4, "uses pytz"},
+			{re(`\bzoneinfo\b`), 4, "uses zoneinfo"},
+			{re(`\btzinfo\b|\btimezone\.utc\b|\butcnow\(|\.astimezone\(`), 3, "handles timezones"},
+			{re(`\bdateutil\b`), 2, "uses dateutil"},
+			{re(`\bdatetime\.now\(\)`), 2, "creates naive datetimes"},
+		},
+		minScore: 4,
+	},
+}
+
+// Rank scores every file for the topic by name and content and returns the best
+// candidates, highest score first. A limit of 0 returns all candidates.
+func Rank(files []model.ProjectFile, topic Topic, limit int) []Candidate {
+	p, ok := profiles[topic]
+	if !ok {
+		return nil
+	}
+
+	var candidates []Candidate
+	for _, file := range files {
+		score, reasons := p.score(file)
+		if score < p.minScore {
+			continue
+		}
+		candidates = append(candidates, Candidate{File: file, Score: score, Reasons: reasons})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].File.Path < candidates[j].File.Path
+	})
+
+	if limit > 0 && len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
+
+func (p profile) score(file model.ProjectFile) (float64, []string) {
+	var score float64
+	var reasons []string
+
+	base := filepath.Base(file.Path)
+	if weight, ok := p.names[base]; ok {
+		score += weight
+		reasons = append(reasons, "named "+base)
+	} else if p.namePattern != nil && p.namePattern.MatchString(base) {
+		score += p.nameWeight
+		reasons = append(reasons, "named "+base)
+	}
+
+	if p.scansExtension(filepath.Ext(base)) {
+		for _, s := range p.signals {
+			matches := len(s.pattern.FindAllStringIndex(file.Content, maxMatchesPerSignal))
+			if matches == 0 {
+				continue
+			}
+			score += s.weight * float64(matches)
+			reasons = append(reasons, s.reason)
+		}
+	}
+
+	if p.rootBonus && score > 0 {
+		// Files in the root or the backend directory are the project's own configuration
+		depth := strings.Count(strings.TrimPrefix(file.Path, "/"), "/")
+		if depth <= 1 {
+			score += 2
+		} else {
+			score -= float64(depth-1) * 0.5
+		}
+	}
+
+	return score, reasons
+}
+
+func (p profile) scansExtension(extension string) bool {
+	if len(p.extensions) == 0 {
+		return true
+	}
+	for _, e := range p.extensions {
+		if e == extension {
+			return true
+		}
+	}
+	return false
+}
+
+// Contents maps the path of every candidate to its content
+func Contents(candidates []Candidate) map[string]string {
+	contents := make(map[string]string, len(candidates))
+	for _, candidate := range candidates {
+		contents[candidate.File.Path] = candidate.File.Content
+	}
+	return contents
+}
+
+// MissingFiles returns the names no file of the project has, in the given order
+func MissingFiles(files []model.ProjectFile, names []string) []string {
+	present := make(map[string]bool, len(files))
+	for _, file := range files {
+		present[filepath.Base(file.Path)] = true
+	}
+	var missing []string
+	for _, name := range names {
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// Files returns the files of the candidates
+func Files(candidates []Candidate) []model.ProjectFile {
+	files := make([]model.ProjectFile, len(candidates))
+	for i, candidate := range candidates {
+		files[i] = candidate.File
+	}
+	return files
+}
+
+// Concat joins the candidates into a single prompt section with their paths
+func Concat(candidates []Candidate) string {
+	var sb strings.Builder
+	for _, candidate := range candidates {
+		sb.WriteString(fmt.Sprintf("Path: %s\nContent:\n%s\n\n", candidate.File.Path, candidate.File.Content))
+	}
+	return sb.String()
+}
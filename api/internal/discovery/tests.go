@@ -0,0 +1,370 @@
+// internal/discovery/tests.go
+
+package discovery
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"evraz_api/internal/model"
+)
+
+// defaultPythonFiles are pytest's default test file patterns
+var defaultPythonFiles = []string{"test_*.py", "*_test.py"}
+
+// testDirNames are directory names that only group tests and do not mirror the source tree
+var testDirNames = map[string]bool{
+	"test": true, "tests": true, "unit": true, "unit_tests": true,
+	"integration": true, "integration_tests": true, "e2e": true, "functional": true,
+}
+
+// nonModuleNames are Python files that are not expected to have dedicated tests
+var nonModuleNames = map[string]bool{
+	"__init__.py": true, "__main__.py": true, "conftest.py": true,
+	"setup.py": true, "manage.py": true, "wsgi.py": true, "asgi.py": true,
+}
+
+var (
+	testFunctionPattern = regexp.MustCompile(`(?m)^\s*(async\s+)?def\s+test\w*\s*\(`)
+	testCasePattern     = regexp.MustCompile(`\bclass\s+\w+\s*\([^)]*TestCase[^)]*\)`)
+)
+
+// TestFile is a file collected as part of the test suite
+type TestFile struct {
+	File          model.ProjectFile
+	TestFunctions int
+	Framework     string // "pytest" or "unittest"
+}
+
+// TestSuite is the deterministic description of a project's tests
+type TestSuite struct {
+	ConfigFiles []string // Files carrying pytest configuration
+	TestPaths   []string // Configured testpaths, relative to the configuration file
+	Conftests   []string
+	TestFiles   []TestFile
+}
+
+// DiscoverTests finds the test suite using pytest/unittest conventions and configuration
+func DiscoverTests(files []model.ProjectFile) TestSuite {
+	var suite TestSuite
+	pythonFiles := defaultPythonFiles
+
+	for _, file := range files {
+		var testPaths, patterns []string
+		var found bool
+		switch path.Base(file.Path) {
+		case "pyproject.toml":
+			testPaths, patterns, found = pytestOptionsFromPyproject(file.Content)
+		case "pytest.ini", "tox.ini":
+			testPaths, patterns, found = pytestOptionsFromIni(file.Content, "pytest")
+		case "setup.cfg":
+			testPaths, patterns, found = pytestOptionsFromIni(file.Content, "tool:pytest")
+		}
+		if !found {
+			continue
+		}
+
+		suite.ConfigFiles = append(suite.ConfigFiles, file.Path)
+		configDir := path.Dir(strings.TrimPrefix(file.Path, "/"))
+		for _, testPath := range testPaths {
+			suite.TestPaths = append(suite.TestPaths, path.Clean(path.Join(configDir, testPath)))
+		}
+		if len(patterns) > 0 {
+			pythonFiles = patterns
+		}
+	}
+
+	for _, file := range files {
+		filePath := strings.TrimPrefix(file.Path, "/")
+		base := path.Base(filePath)
+		if base == "conftest.py" {
+			suite.Conftests = append(suite.Conftests, file.Path)
+			continue
+		}
+		if path.Ext(base) != ".py" {
+			continue
+		}
+
+		isTestCase := testCasePattern.MatchString(file.Content)
+		if !matchesAny(base, pythonFiles) && !(isTestCase && strings.HasPrefix(base, "test")) {
+			continue
+		}
+		if len(suite.TestPaths) > 0 && !underAny(filePath, suite.TestPaths) {
+			continue
+		}
+
+		framework := "pytest"
+		if isTestCase {
+			framework = "unittest"
+		}
+		suite.TestFiles = append(suite.TestFiles, TestFile{
+			File:          file,
+			TestFunctions: len(testFunctionPattern.FindAllStringIndex(file.Content, -1)),
+			Framework:     framework,
+		})
+	}
+
+	sort.Slice(suite.TestFiles, func(i, j int) bool {
+		return suite.TestFiles[i].File.Path < suite.TestFiles[j].File.Path
+	})
+	return suite
+}
+
+// TotalTestFunctions returns the number of test functions across the suite
+func (s TestSuite) TotalTestFunctions() int {
+	total := 0
+	for _, testFile := range s.TestFiles {
+		total += testFile.TestFunctions
+	}
+	return total
+}
+
+// Summary describes the suite for the testing prompt
+func (s TestSuite) Summary() string {
+	var sb strings.Builder
+	if len(s.ConfigFiles) > 0 {
+		sb.WriteString(fmt.Sprintf("Pytest configuration: %s\n", strings.Join(s.ConfigFiles, ", ")))
+	}
+	if len(s.TestPaths) > 0 {
+		sb.WriteString(fmt.Sprintf("Configured testpaths: %s\n", strings.Join(s.TestPaths, ", ")))
+	}
+	if len(s.Conftests) > 0 {
+		sb.WriteString(fmt.Sprintf("conftest.py files: %s\n", strings.Join(s.Conftests, ", ")))
+	}
+	sb.WriteString(fmt.Sprintf("Test files: %d, test functions: %d\n", len(s.TestFiles), s.TotalTestFunctions()))
+	for _, testFile := range s.TestFiles {
+		sb.WriteString(fmt.Sprintf("- %s (%s, %d tests)\n", testFile.File.Path, testFile.Framework, testFile.TestFunctions))
+	}
+	return sb.String()
+}
+
+// Concat joins the test files and conftests into a single prompt section with their paths
+func (s TestSuite) Concat(files []model.ProjectFile) string {
+	var sb strings.Builder
+	for _, file := range s.Files(files) {
+		sb.WriteString(fmt.Sprintf("Path: %s\nContent:\n%s\n\n", file.Path, file.Content))
+	}
+	return sb.String()
+}
+
+// Files returns the conftest files followed by the test files of the suite
+func (s TestSuite) Files(files []model.ProjectFile) []model.ProjectFile {
+	conftests := make(map[string]bool, len(s.Conftests))
+	for _, conftest := range s.Conftests {
+		conftests[conftest] = true
+	}
+
+	var suiteFiles []model.ProjectFile
+	for _, file := range files {
+		if conftests[file.Path] {
+			suiteFiles = append(suiteFiles, file)
+		}
+	}
+	for _, testFile := range s.TestFiles {
+		suiteFiles = append(suiteFiles, testFile.File)
+	}
+	return suiteFiles
+}
+
+// MirroringGap is a source module whose tests are missing or not placed like the source
+type MirroringGap struct {
+	Module   model.ProjectFile
+	TestPath string // Path of a test found outside the mirrored location; empty when missing
+}
+
+// CheckTestMirroring reports which source modules have no corresponding tests and which
+// tests do not mirror the directory of the module they test
+func CheckTestMirroring(files []model.ProjectFile, suite TestSuite) []MirroringGap {
+	testFiles := make(map[string]bool, len(suite.TestFiles))
+	testsByModule := make(map[string][]string)
+	for _, testFile := range suite.TestFiles {
+		filePath := strings.TrimPrefix(testFile.File.Path, "/")
+		testFiles[filePath] = true
+		module := strings.TrimSuffix(path.Base(filePath), ".py")
+		module = strings.TrimSuffix(strings.TrimPrefix(module, "test_"), "_test")
+		testsByModule[module] = append(testsByModule[module], filePath)
+	}
+
+	var gaps []MirroringGap
+	for _, file := range files {
+		filePath := strings.TrimPrefix(file.Path, "/")
+		base := path.Base(filePath)
+		if path.Ext(base) != ".py" || nonModuleNames[base] || testFiles[filePath] || isInTestDir(filePath) || isMigration(filePath) {
+			continue
+		}
+		if strings.TrimSpace(file.Content) == "" {
+			continue
+		}
+
+		module := strings.TrimSuffix(base, ".py")
+		candidates := testsByModule[module]
+		if len(candidates) == 0 {
+			gaps = append(gaps, MirroringGap{Module: file})
+			continue
+		}
+
+		mirrored := false
+		for _, testPath := range candidates {
+			if mirrors(path.Dir(filePath), path.Dir(testPath)) {
+				mirrored = true
+				break
+			}
+		}
+		if !mirrored {
+			gaps = append(gaps, MirroringGap{Module: file, TestPath: candidates[0]})
+		}
+	}
+	return gaps
+}
+
+// mirrors reports whether a test directory mirrors a source directory. The part of the
+// test directory after its last test grouping directory (tests/unit/...) must match the end
+// of the source directory; a flat tests directory only mirrors the top-level package.
+func mirrors(sourceDir, testDir string) bool {
+	testParts := strings.Split(testDir, "/")
+	first, last := -1, -1
+	for i, part := range testParts {
+		if testDirNames[part] {
+			if first < 0 {
+				first = i
+			}
+			last = i
+		}
+	}
+	root, relative := []string(nil), testParts
+	if first >= 0 {
+		root, relative = testParts[:first], testParts[last+1:]
+	}
+
+	sourceParts := strings.Split(sourceDir, "/")
+	if sourceDir == "." {
+		sourceParts = nil
+	}
+	if len(root) <= len(sourceParts) && strings.Join(sourceParts[:len(root)], "/") == strings.Join(root, "/") {
+		sourceParts = sourceParts[len(root):]
+	}
+
+	if len(relative) == 0 {
+		return len(sourceParts) <= 1
+	}
+	if len(relative) > len(sourceParts) {
+		return false
+	}
+	offset := len(sourceParts) - len(relative)
+	for i, part := range relative {
+		if sourceParts[offset+i] != part {
+			return false
+		}
+	}
+	return true
+}
+
+func isInTestDir(filePath string) bool {
+	for _, part := range strings.Split(path.Dir(filePath), "/") {
+		if part == "test" || part == "tests" {
+			return true
+		}
+	}
+	return false
+}
+
+func isMigration(filePath string) bool {
+	return strings.Contains(filePath, "/migrations/") || strings.Contains(filePath, "/alembic/")
+}
+
+func matchesAny(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func underAny(filePath string, dirs []string) bool {
+	for _, dir := range dirs {
+		if dir == "." || filePath == dir || strings.HasPrefix(filePath, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// pytestOptionsFromPyproject reads testpaths and python_files from [tool.pytest.ini_options]
+func pytestOptionsFromPyproject(content string) ([]string, []string, bool) {
+	var pyproject struct {
+		Tool struct {
+			Pytest struct {
+				IniOptions map[string]interface{} `toml:"ini_options"`
+			} `toml:"pytest"`
+		} `toml:"tool"`
+	}
+	if err := toml.Unmarshal([]byte(content), &pyproject); err != nil {
+		return nil, nil, false
+	}
+	options := pyproject.Tool.Pytest.IniOptions
+	if options == nil {
+		return nil, nil, false
+	}
+	return tomlStrings(options["testpaths"]), tomlStrings(options["python_files"]), true
+}
+
+func tomlStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return strings.Fields(v)
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// pytestOptionsFromIni reads testpaths and python_files from an ini section; values may
+// continue on indented lines
+func pytestOptionsFromIni(content, section string) ([]string, []string, bool) {
+	inSection := false
+	found := false
+	options := make(map[string][]string)
+	currentKey := ""
+
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			inSection = strings.TrimSpace(trimmed[1:len(trimmed)-1]) == section
+			found = found || inSection
+			currentKey = ""
+			continue
+		}
+		if !inSection || trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+
+		if line[0] == ' ' || line[0] == '\t' {
+			if currentKey != "" {
+				options[currentKey] = append(options[currentKey], strings.Fields(trimmed)...)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			currentKey = ""
+			continue
+		}
+		currentKey = strings.TrimSpace(key)
+		options[currentKey] = strings.Fields(value)
+	}
+
+	return options["testpaths"], options["python_files"], found
+}
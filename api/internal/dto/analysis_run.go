@@ -0,0 +1,93 @@
+// internal/dto/analysis_run.go
+
+package dto
+
+import "time"
+
+type AnalysisRunPromptStatDTO struct {
+	PromptName        string  `json:"prompt_name"`
+	Findings          int     `json:"findings"`
+	Hallucinated      int     `json:"hallucinated"`
+	CriticRejected    int     `json:"critic_rejected"`
+	HallucinationRate float64 `json:"hallucination_rate"`
+}
+
+type AnalysisRunDTO struct {
+	ID            uint                       `json:"id"`
+	ProjectID     uint                       `json:"project_id"`                // Version the run analyzed
+	ProjectFileID *uint                      `json:"project_file_id,omitempty"` // Set for single-file runs
+	Scope         string                     `json:"scope"`
+	Status        string                     `json:"status"`
+	Error         string                     `json:"error,omitempty"`
+	Language      string                     `json:"language"`
+	StartedAt     time.Time                  `json:"started_at"`
+	FinishedAt    *time.Time                 `json:"finished_at,omitempty"`
+	PromptStats   []AnalysisRunPromptStatDTO `json:"prompt_stats"`
+	Quality       *QualityDTO                `json:"quality,omitempty"` // Set once the run is scored
+}
+
+type CategoryScoreDTO struct {
+	Category     string  `json:"category"`
+	Score        float64 `json:"score"`
+	Penalty      float64 `json:"penalty"`
+	Findings     int     `json:"findings"`
+	FailedChecks int     `json:"failed_checks"`
+}
+
+// QualityDTO is the quality score of a project as computed by an analysis run
+type QualityDTO struct {
+	RunID      uint               `json:"run_id"`
+	Score      float64            `json:"score"`
+	Grade      string             `json:"grade"`
+	CodeLines  int                `json:"code_lines"`
+	Categories []CategoryScoreDTO `json:"categories"`
+}
+
+type GetRunsResponse struct {
+	Runs []AnalysisRunDTO `json:"runs"`
+}
+
+// RunFindingDTO is a finding as it was visible when a run finished
+type RunFindingDTO struct {
+	FindingID   uint   `json:"finding_id"`
+	Fingerprint string `json:"fingerprint"`
+	Source      string `json:"source"`
+	RuleName    string `json:"rule_name"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Path        string `json:"path,omitempty"`
+	Line        int    `json:"line,omitempty"`
+}
+
+type ComplianceFlipDTO struct {
+	PromptName string `json:"prompt_name"`
+	Path       string `json:"path,omitempty"` // Empty for project-level checks
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+type RunDiffResponse struct {
+	FromRun         AnalysisRunDTO      `json:"from_run"`
+	ToRun           AnalysisRunDTO      `json:"to_run"`
+	New             []RunFindingDTO     `json:"new"`
+	Resolved        []RunFindingDTO     `json:"resolved"`
+	Persisting      []RunFindingDTO     `json:"persisting"`
+	ComplianceFlips []ComplianceFlipDTO `json:"compliance_flips"`
+}
+
+type AgentToolCallDTO struct {
+	ID            uint      `json:"id"`
+	AnalysisRunID *uint     `json:"analysis_run_id,omitempty"`
+	PromptName    string    `json:"prompt_name"`
+	GPTCallID     uint      `json:"gpt_call_id"` // Model turn that requested the call
+	Step          int       `json:"step"`
+	ToolName      string    `json:"tool_name"`
+	Arguments     string    `json:"arguments"`
+	Result        string    `json:"result"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetAgentToolCallsResponse struct {
+	ToolCalls []AgentToolCallDTO `json:"tool_calls"`
+}
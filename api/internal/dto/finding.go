@@ -0,0 +1,91 @@
+// internal/dto/finding.go
+
+package dto
+
+import "time"
+
+type FindingDTO struct {
+	ID            uint   `json:"id"`
+	ProjectFileID *uint  `json:"project_file_id,omitempty"`
+	Source        string `json:"source"`
+	RuleName      string `json:"rule_name"`
+	Tool          string `json:"tool,omitempty"`
+	Severity      string `json:"severity"`
+	Message       string `json:"message"`
+	Path          string `json:"path,omitempty"`
+	Line          int    `json:"line,omitempty"`
+	Column        int    `json:"column,omitempty"`
+	CanonicalID   *uint  `json:"canonical_id,omitempty"` // Set on duplicates of another finding
+	RuleNames     string `json:"rule_names,omitempty"`   // All rules that raised the problem
+	Occurrences   int    `json:"occurrences"`
+
+	// Critic verdict, present when the critic reviewed the finding
+	GPTCallID       *uint    `json:"gpt_call_id,omitempty"`
+	Supported       *bool    `json:"supported,omitempty"`
+	Confidence      *float64 `json:"confidence,omitempty"`
+	CriticReason    string   `json:"critic_reason,omitempty"`
+	CriticGPTCallID *uint    `json:"critic_gpt_call_id,omitempty"`
+	Hidden          bool     `json:"hidden"`
+
+	AnalysisRunID *uint  `json:"analysis_run_id,omitempty"`
+	Hallucinated  bool   `json:"hallucinated"`           // Cites symbols, paths or lines that do not exist
+	GuardReason   string `json:"guard_reason,omitempty"` // What the hallucination guard could not find
+
+	Fingerprint string     `json:"fingerprint,omitempty"`
+	Status      string     `json:"status"`
+	Assignee    string     `json:"assignee,omitempty"`
+	TriagedAt   *time.Time `json:"triaged_at,omitempty"`
+
+	Suppressed        bool   `json:"suppressed"`
+	SuppressionRuleID *uint  `json:"suppression_rule_id,omitempty"` // Empty for the baseline and the configuration file
+	SuppressionReason string `json:"suppression_reason,omitempty"`
+}
+
+type GetFindingsResponse struct {
+	Findings []FindingDTO `json:"findings"`
+}
+
+type ImportLinterReportResponse struct {
+	Message   string `json:"message"`
+	Tool      string `json:"tool"`
+	Imported  int    `json:"imported"`
+	Unmatched int    `json:"unmatched"` // Entries whose path matches no project file
+}
+
+// UpdateFindingTriageRequest changes the status and the assignee of a finding; omitted
+// fields are kept and an empty assignee unassigns the finding
+type UpdateFindingTriageRequest struct {
+	Status   *string `json:"status"`
+	Assignee *string `json:"assignee"`
+	Actor    string  `json:"actor" binding:"required"` // Recorded in the audit trail
+}
+
+type AddFindingCommentRequest struct {
+	Author string `json:"author" binding:"required"`
+	Body   string `json:"body" binding:"required"`
+}
+
+type FindingCommentDTO struct {
+	ID        uint      `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetFindingCommentsResponse struct {
+	Comments []FindingCommentDTO `json:"comments"`
+}
+
+type FindingEventDTO struct {
+	ID        uint      `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	OldValue  string    `json:"old_value,omitempty"`
+	NewValue  string    `json:"new_value,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type GetFindingHistoryResponse struct {
+	Events []FindingEventDTO `json:"events"`
+}
@@ -0,0 +1,23 @@
+// internal/dto/gate.go
+
+package dto
+
+type GateViolationDTO struct {
+	Policy    string          `json:"policy"`
+	Condition string          `json:"condition"`
+	Expected  string          `json:"expected"`
+	Actual    string          `json:"actual"`
+	Message   string          `json:"message"`
+	Findings  []RunFindingDTO `json:"findings,omitempty"` // Findings that break a finding limit
+}
+
+// GateResponse tells whether the latest run of a project passes the quality gate
+type GateResponse struct {
+	Passed        bool               `json:"passed"`
+	Policies      []string           `json:"policies"`
+	RunID         uint               `json:"run_id"`
+	PreviousRunID *uint              `json:"previous_run_id,omitempty"` // Unset when every finding counts as new
+	Score         *float64           `json:"score,omitempty"`
+	Grade         string             `json:"grade,omitempty"`
+	Violations    []GateViolationDTO `json:"violations"`
+}
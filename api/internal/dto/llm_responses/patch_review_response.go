@@ -0,0 +1,15 @@
+// internal/dto/llm_responses/patch_review_response.go
+
+package llm_responses
+
+type PatchReviewResponse struct {
+	Compliance      bool               `json:"compliance"`
+	Issues          []PatchReviewIssue `json:"issues"`
+	Recommendations []string           `json:"recommendations"`
+}
+
+type PatchReviewIssue struct {
+	Line     int    `json:"line"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
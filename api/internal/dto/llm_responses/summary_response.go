@@ -0,0 +1,7 @@
+// internal/dto/llm_responses/summary_response.go
+
+package llm_responses
+
+type SummaryResponse struct {
+	Summary string `json:"summary"`
+}
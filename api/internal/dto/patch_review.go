@@ -0,0 +1,54 @@
+// internal/dto/patch_review.go
+
+package dto
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// ReviewPatchRequest carries either a unified diff, as text or as a file, or the base and
+// head archives of a merge request
+type ReviewPatchRequest struct {
+	Diff     string                `json:"diff" form:"diff"`
+	Patch    *multipart.FileHeader `json:"-"`
+	Base     *multipart.FileHeader `json:"-"`
+	Head     *multipart.FileHeader `json:"-"`
+	Language string                `json:"language" form:"language"` // Overrides the project language for this review
+}
+
+type PatchReviewFileDTO struct {
+	Path       string `json:"path"`
+	OldPath    string `json:"old_path,omitempty"` // Set when the file was renamed
+	Change     string `json:"change"`
+	AddedLines int    `json:"added_lines"`
+	Reviewed   bool   `json:"reviewed"`
+}
+
+type PatchFindingDTO struct {
+	ID        uint   `json:"id"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	RuleName  string `json:"rule_name"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	GPTCallID *uint  `json:"gpt_call_id,omitempty"`
+}
+
+type PatchReviewDTO struct {
+	ID         uint                 `json:"id"`
+	ProjectID  uint                 `json:"project_id"`
+	Source     string               `json:"source"`
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty"`
+	Language   string               `json:"language"`
+	Diff       string               `json:"diff"`
+	CreatedAt  time.Time            `json:"created_at"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+	Files      []PatchReviewFileDTO `json:"files"`
+	Findings   []PatchFindingDTO    `json:"findings"`
+}
+
+type GetPatchReviewsResponse struct {
+	Reviews []PatchReviewDTO `json:"reviews"`
+}
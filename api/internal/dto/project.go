@@ -2,12 +2,22 @@
 
 package dto
 
-import "mime/multipart"
+import (
+	"encoding/json"
+	"mime/multipart"
+	"time"
+)
 
 type UploadProjectRequest struct {
 	UserID string                `json:"user_id"`
 	Name   string                `json:"name"`
 	File   *multipart.FileHeader `json:"file"`
+	// Report language of the project; the configured default is used when empty
+	Language string `json:"language" form:"language"`
+	// Optional coverage report (Cobertura XML or coverage.py JSON) passed next to the archive
+	Coverage *multipart.FileHeader `json:"-"`
+	// Existing project the archive is a new version of; a new project is created when empty
+	ProjectID uint `json:"project_id" form:"project_id"`
 }
 
 type UploadProjectResponse struct {
@@ -23,10 +33,33 @@ type ProjectDTO struct {
 	Path                  string `json:"path"`
 	Tree                  string `json:"tree"`
 	WasAnalyzed           bool   `json:"was_analyzed"`
+	Language              string `json:"language"`
+	Summary               string `json:"summary,omitempty"` // Set once a large project was summarized
+	Version               int    `json:"version"`
+	RootProjectID         *uint  `json:"root_project_id,omitempty"` // First version, unset on it
+	// Configuration read from the configuration file in the project root, if any
+	ConfigPath string          `json:"config_path,omitempty"`
+	Config     json.RawMessage `json:"config,omitempty"`
+	// Quality of the latest scored run
+	Score *float64 `json:"score,omitempty"`
+	Grade string   `json:"grade,omitempty"`
+}
+
+// DTO for a version of a project
+type ProjectVersionDTO struct {
+	ProjectID   uint      `json:"project_id"`
+	Version     int       `json:"version"`
+	WasAnalyzed bool      `json:"was_analyzed"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type GetProjectVersionsResponse struct {
+	Versions []ProjectVersionDTO `json:"versions"`
 }
 
 type AnalyzeProjectRequest struct {
-	ProjectID uint `json:"project_id" binding:"required"`
+	ProjectID uint   `json:"project_id" binding:"required"`
+	Language  string `json:"language"` // Overrides the project language for this analysis
 }
 
 type AnalyzeProjectResponse struct {
@@ -34,13 +67,29 @@ type AnalyzeProjectResponse struct {
 }
 
 type AnalyzeFileRequest struct {
-	FileID uint `json:"file_id" binding:"required"`
+	FileID   uint   `json:"file_id" binding:"required"`
+	Language string `json:"language"` // Overrides the project language for this analysis
 }
 
 type AnalyzeFileResponse struct {
 	Message string `json:"message"`
 }
 
+type UpdateProjectSettingsRequest struct {
+	Language string `json:"language" binding:"required"`
+}
+
+type UpdateProjectSettingsResponse struct {
+	Message string     `json:"message"`
+	Project ProjectDTO `json:"project"`
+}
+
+type TranslateProjectResponse struct {
+	Message    string `json:"message"`
+	Language   string `json:"language"`
+	Translated int    `json:"translated"` // Number of results translated by this request
+}
+
 // New DTOs for the first endpoint
 type GetAllProjectsResponse struct {
 	Projects []ProjectDTO `json:"projects"`
@@ -48,9 +97,29 @@ type GetAllProjectsResponse struct {
 
 // DTO for ProjectFile
 type ProjectFileDTO struct {
-	ID          uint   `json:"id"`
-	Name        string `json:"name"`
-	WasAnalyzed bool   `json:"was_analyzed"`
+	ID          uint             `json:"id"`
+	Name        string           `json:"name"`
+	WasAnalyzed bool             `json:"was_analyzed"`
+	Coverage    *FileCoverageDTO `json:"coverage,omitempty"`
+	Summary     string           `json:"summary,omitempty"`
+}
+
+// DTO for the summary of a project directory
+type PackageSummaryDTO struct {
+	Path    string `json:"path"`
+	Files   int    `json:"files"`
+	Summary string `json:"summary"`
+}
+
+// DTO for the coverage of a file, or of the whole project
+type FileCoverageDTO struct {
+	LineRate        float64 `json:"line_rate"`   // Percent
+	BranchRate      float64 `json:"branch_rate"` // Percent
+	LinesValid      int     `json:"lines_valid"`
+	LinesCovered    int     `json:"lines_covered"`
+	BranchesValid   int     `json:"branches_valid"`
+	BranchesCovered int     `json:"branches_covered"`
+	MissingLines    string  `json:"missing_lines,omitempty"`
 }
 
 // DTO for ProjectAnalysisResult
@@ -60,11 +129,16 @@ type ProjectAnalysisResultDTO struct {
 	Compliance      string `json:"compliance"`
 	Issues          string `json:"issues"`
 	Recommendations string `json:"recommendations"`
+	Status          string `json:"status"`
+	StatusReason    string `json:"status_reason"`
 }
 
 // DTO for the second endpoint
 type GetProjectOverviewResponse struct {
 	Project                ProjectDTO                 `json:"project"`
+	Packages               []PackageSummaryDTO        `json:"packages,omitempty"`
 	Files                  []ProjectFileDTO           `json:"files"`
 	ProjectAnalysisResults []ProjectAnalysisResultDTO `json:"analysis_results"`
+	Coverage               *FileCoverageDTO           `json:"coverage,omitempty"`
+	Quality                *QualityDTO                `json:"quality,omitempty"` // Latest scored run
 }
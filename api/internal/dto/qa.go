@@ -0,0 +1,34 @@
+// internal/dto/qa.go
+
+package dto
+
+import "time"
+
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+	Language string `json:"language"` // Overrides the project language for the answer
+}
+
+type CitationDTO struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+type AskResponse struct {
+	MessageID uint          `json:"message_id"`
+	Answer    string        `json:"answer"`
+	Citations []CitationDTO `json:"citations"`
+}
+
+type QAMessageDTO struct {
+	ID        uint          `json:"id"`
+	Role      string        `json:"role"` // "user" or "assistant"
+	Content   string        `json:"content"`
+	Citations []CitationDTO `json:"citations,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type GetConversationResponse struct {
+	Messages []QAMessageDTO `json:"messages"`
+}
@@ -0,0 +1,48 @@
+// internal/dto/suppression.go
+
+package dto
+
+import "time"
+
+// CreateSuppressionRuleRequest needs a reason and at least one of the matchers; every
+// matcher that is set must match a finding
+type CreateSuppressionRuleRequest struct {
+	RuleName       string     `json:"rule_name"`
+	PathGlob       string     `json:"path_glob"`       // Supports *, ? and **; without a slash it matches file names
+	MessagePattern string     `json:"message_pattern"` // Regular expression
+	Reason         string     `json:"reason" binding:"required"`
+	Author         string     `json:"author"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+}
+
+type SuppressionRuleDTO struct {
+	ID             uint       `json:"id"`
+	ProjectID      uint       `json:"project_id"`
+	RuleName       string     `json:"rule_name,omitempty"`
+	PathGlob       string     `json:"path_glob,omitempty"`
+	MessagePattern string     `json:"message_pattern,omitempty"`
+	Reason         string     `json:"reason"`
+	Author         string     `json:"author,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Expired        bool       `json:"expired"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type CreateBaselineRequest struct {
+	Author string `json:"author"`
+	Reason string `json:"reason"`
+}
+
+type BaselineDTO struct {
+	ID           uint      `json:"id"`
+	ProjectID    uint      `json:"project_id"`
+	Author       string    `json:"author,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Fingerprints int       `json:"fingerprints"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type GetSuppressionsResponse struct {
+	Rules    []SuppressionRuleDTO `json:"rules"`
+	Baseline *BaselineDTO         `json:"baseline,omitempty"`
+}
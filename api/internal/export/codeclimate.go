@@ -0,0 +1,157 @@
+// internal/export/codeclimate.go
+
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/findings"
+	"evraz_api/internal/model"
+	"evraz_api/internal/quality"
+)
+
+// projectPath locates project-level issues, which have no file, in the project root
+const projectPath = "."
+
+// CodeClimateIssue is an issue of the Code Climate format GitLab reads code quality
+// reports in
+type CodeClimateIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    CodeClimateLocation `json:"location"`
+}
+
+type CodeClimateLocation struct {
+	Path  string           `json:"path"`
+	Lines CodeClimateLines `json:"lines"`
+}
+
+type CodeClimateLines struct {
+	Begin int `json:"begin"`
+}
+
+// codeClimateCategories maps score categories to Code Climate categories
+var codeClimateCategories = map[string]string{
+	quality.CategoryStructure:       "Clarity",
+	quality.CategoryDependencies:    "Compatibility",
+	quality.CategoryArchitecture:    "Complexity",
+	quality.CategoryTesting:         "Bug Risk",
+	quality.CategoryCodingStandards: "Style",
+	quality.CategoryErrorHandling:   "Bug Risk",
+	quality.CategoryDateTime:        "Bug Risk",
+}
+
+// CodeClimate builds a code quality report from the findings nothing suppresses and the
+// stored results. A non-compliant result whose issues are not stored as findings, as for
+// files analyzed before findings were recorded, becomes one issue with the result's issues
+// as its description.
+func CodeClimate(projectFindings []model.Finding, projectResults []model.ProjectAnalysisResult, files []model.ProjectFile) []CodeClimateIssue {
+	issues := []CodeClimateIssue{}
+	seen := make(map[string]int)
+	add := func(finding model.Finding) {
+		// GitLab needs unique fingerprints; repeated problems get a numbered one
+		fingerprint := finding.Fingerprint
+		if fingerprint == "" {
+			fingerprint = findings.Fingerprint(finding, "")
+		}
+		if count := seen[fingerprint]; count > 0 {
+			sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", fingerprint, count)))
+			seen[fingerprint]++
+			fingerprint = hex.EncodeToString(sum[:16])
+		} else {
+			seen[fingerprint]++
+		}
+
+		path := strings.TrimPrefix(finding.Path, "./")
+		if path == "" {
+			path = projectPath
+		}
+		line := finding.Line
+		if line <= 0 {
+			line = 1
+		}
+		issues = append(issues, CodeClimateIssue{
+			Type:        "issue",
+			CheckName:   finding.RuleName,
+			Description: finding.Message,
+			Categories:  []string{codeClimateCategories[quality.CategoryOf(finding.RuleName)]},
+			Severity:    codeClimateSeverity(finding.Severity),
+			Fingerprint: fingerprint,
+			Location:    CodeClimateLocation{Path: path, Lines: CodeClimateLines{Begin: line}},
+		})
+	}
+
+	// Project-level checks and files whose review issues are stored as findings, suppressed
+	// or not
+	reviewedChecks := make(map[string]bool)
+	reviewedFiles := make(map[uint]bool)
+	for _, finding := range projectFindings {
+		if finding.Source != model.FindingSourceLLM {
+			continue
+		}
+		if finding.ProjectFileID != nil {
+			reviewedFiles[*finding.ProjectFileID] = true
+		} else {
+			reviewedChecks[finding.RuleName] = true
+		}
+	}
+
+	for _, finding := range projectFindings {
+		if !finding.Suppressed && !finding.Hidden && finding.CanonicalID == nil {
+			add(finding)
+		}
+	}
+	for _, result := range latestProjectResults(projectResults) {
+		if !result.Completed() || result.Compliance != "false" || reviewedChecks[result.PromptName] {
+			continue
+		}
+		add(resultFinding(result.PromptName, "", result.Issues))
+	}
+	for _, file := range files {
+		if reviewedFiles[file.ID] {
+			continue
+		}
+		for _, result := range latestFileResults(file.FileAnalysisResults) {
+			if result.Compliance != "false" {
+				continue
+			}
+			add(resultFinding(result.PromptName, file.Path, result.Issues))
+		}
+	}
+	return issues
+}
+
+// resultFinding turns the issues of a non-compliant result into a finding
+func resultFinding(promptName, path, issues string) model.Finding {
+	if strings.TrimSpace(issues) == "" {
+		issues = promptName
+	}
+	return model.Finding{
+		Source:   model.FindingSourceLLM,
+		RuleName: promptName,
+		Severity: model.SeverityMedium,
+		Message:  issues,
+		Path:     path,
+	}
+}
+
+// codeClimateSeverity maps a normalized severity to a Code Climate severity
+func codeClimateSeverity(severity string) string {
+	switch severity {
+	case model.SeverityHigh:
+		return "critical"
+	case model.SeverityMedium:
+		return "major"
+	case model.SeverityLow:
+		return "minor"
+	default:
+		return "info"
+	}
+}
@@ -0,0 +1,141 @@
+// internal/export/junit.go
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// JUnitTestSuites is a JUnit XML report with a test suite for the project-level checks
+// and one per file
+type JUnitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *JUnitProblem `xml:"failure,omitempty"`
+	Error     *JUnitProblem `xml:"error,omitempty"`
+	Skipped   *JUnitSkipped `xml:"skipped,omitempty"`
+}
+
+type JUnitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type JUnitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// projectSuite names the test suite of the project-level checks
+const projectSuite = "project"
+
+// JUnit builds a JUnit XML report from the latest result of every check: a project-level
+// check or a check of a file is a test case that fails when the result is not compliant.
+// Checks the master agent skipped are skipped test cases; checks whose reply could not be
+// used and checks that did not complete are errors.
+func JUnit(project model.Project, projectResults []model.ProjectAnalysisResult, files []model.ProjectFile) JUnitTestSuites {
+	report := JUnitTestSuites{Name: project.Name}
+
+	projectCases := make([]JUnitTestCase, 0, len(projectResults))
+	for _, result := range latestProjectResults(projectResults) {
+		testCase := JUnitTestCase{Name: result.PromptName, ClassName: projectSuite}
+		switch {
+		case result.Completed():
+			testCase.Failure = junitFailure(result.PromptName, result.Compliance, result.Issues, result.Recommendations)
+		case result.Status == model.AnalysisStatusNotApplicable:
+			testCase.Skipped = &JUnitSkipped{Message: result.StatusReason}
+		case result.Status == model.AnalysisStatusError:
+			testCase.Error = &JUnitProblem{Message: result.StatusReason, Type: result.PromptName}
+		default:
+			// A check without a verdict has not passed
+			testCase.Error = &JUnitProblem{
+				Message: fmt.Sprintf("%s did not complete (status %q)", result.PromptName, result.Status),
+				Type:    result.PromptName,
+			}
+		}
+		projectCases = append(projectCases, testCase)
+	}
+	if len(projectCases) > 0 {
+		report.add(projectSuite, projectCases)
+	}
+
+	for _, file := range files {
+		results := latestFileResults(file.FileAnalysisResults)
+		if len(results) == 0 {
+			continue
+		}
+		path := strings.TrimPrefix(file.Path, "./")
+		fileCases := make([]JUnitTestCase, len(results))
+		for i, result := range results {
+			fileCases[i] = JUnitTestCase{
+				Name:      result.PromptName,
+				ClassName: path,
+				Failure:   junitFailure(result.PromptName, result.Compliance, result.Issues, result.Recommendations),
+			}
+		}
+		report.add(path, fileCases)
+	}
+	return report
+}
+
+// add appends a test suite and counts its test cases
+func (r *JUnitTestSuites) add(name string, testCases []JUnitTestCase) {
+	suite := JUnitTestSuite{Name: name, Tests: len(testCases), TestCases: testCases}
+	for _, testCase := range testCases {
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	r.Suites = append(r.Suites, suite)
+	r.Tests += suite.Tests
+	r.Failures += suite.Failures
+	r.Errors += suite.Errors
+	r.Skipped += suite.Skipped
+}
+
+// junitFailure returns the failure of a check that is not compliant, or nil
+func junitFailure(promptName, compliance, issues, recommendations string) *JUnitProblem {
+	if compliance != "false" {
+		return nil
+	}
+	var text strings.Builder
+	if issues != "" {
+		text.WriteString("Issues: " + issues + "\n")
+	}
+	if recommendations != "" {
+		text.WriteString("Recommendations: " + recommendations + "\n")
+	}
+	return &JUnitProblem{
+		Message: promptName + " is not compliant",
+		Type:    promptName,
+		Text:    text.String(),
+	}
+}
@@ -0,0 +1,43 @@
+// internal/export/results.go
+
+package export
+
+import (
+	"sort"
+
+	"evraz_api/internal/model"
+)
+
+// latestProjectResults keeps the newest result of every project-level check, in the order
+// the checks were first run
+func latestProjectResults(results []model.ProjectAnalysisResult) []model.ProjectAnalysisResult {
+	latest := make(map[string]int)
+	var kept []model.ProjectAnalysisResult
+	for _, result := range results {
+		if index, ok := latest[result.PromptName]; ok {
+			if result.ID > kept[index].ID {
+				kept[index] = result
+			}
+			continue
+		}
+		latest[result.PromptName] = len(kept)
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// latestFileResults keeps the newest result of every check of the file, sorted by check
+func latestFileResults(results []model.FileAnalysisResult) []model.FileAnalysisResult {
+	latest := make(map[string]model.FileAnalysisResult)
+	for _, result := range results {
+		if previous, ok := latest[result.PromptName]; !ok || result.ID > previous.ID {
+			latest[result.PromptName] = result
+		}
+	}
+	kept := make([]model.FileAnalysisResult, 0, len(latest))
+	for _, result := range latest {
+		kept = append(kept, result)
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].PromptName < kept[j].PromptName })
+	return kept
+}
@@ -0,0 +1,256 @@
+// internal/export/sarif.go
+
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/quality"
+)
+
+// SARIF log format, version 2.1.0
+const (
+	SARIFVersion = "2.1.0"
+	SARIFSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// ToolName names the review service as the driver of its SARIF runs
+const ToolName = "evraz"
+
+// fingerprintKey versions the fingerprints, see findings.Fingerprint
+const fingerprintKey = "evraz/v1"
+
+// Rule describes a check or another rule findings are raised by
+type Rule struct {
+	ID        string
+	Title     string
+	Guideline string // What the check verifies; empty for rules the service does not define
+	Scope     string // project or file for review checks
+}
+
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool       SARIFTool         `json:"tool"`
+	Language   string            `json:"language,omitempty"`
+	Results    []SARIFResult     `json:"results"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name  string      `json:"name"`
+	Rules []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name,omitempty"`
+	ShortDescription     *SARIFMessage          `json:"shortDescription,omitempty"`
+	FullDescription      *SARIFMessage          `json:"fullDescription,omitempty"`
+	Help                 *SARIFMessage          `json:"help,omitempty"`
+	DefaultConfiguration *SARIFConfiguration    `json:"defaultConfiguration,omitempty"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFResult struct {
+	RuleID       string                 `json:"ruleId"`
+	RuleIndex    int                    `json:"ruleIndex"`
+	Level        string                 `json:"level"`
+	Message      SARIFMessage           `json:"message"`
+	Locations    []SARIFLocation        `json:"locations,omitempty"`
+	Fingerprints map[string]string      `json:"fingerprints,omitempty"`
+	Suppressions []SARIFSuppression     `json:"suppressions"` // Empty when nothing suppresses the result
+	Properties   map[string]interface{} `json:"properties,omitempty"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type SARIFSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// SARIF builds a log of the findings. The review checks and the rules the service computes
+// make up one run; findings imported from other tools get a run per tool, so that their
+// rule IDs keep their meaning. checks lists the review checks, whose rules are emitted
+// even without findings.
+func SARIF(project model.Project, language string, checks []Rule, findings []model.Finding) SARIFLog {
+	type toolRun struct {
+		run   SARIFRun
+		rules map[string]int // Index of a rule in the driver
+	}
+	runs := make(map[string]*toolRun)
+	var toolNames []string
+	runFor := func(tool string) *toolRun {
+		if run, ok := runs[tool]; ok {
+			return run
+		}
+		run := &toolRun{
+			run: SARIFRun{
+				Tool:     SARIFTool{Driver: SARIFDriver{Name: tool, Rules: []SARIFRule{}}},
+				Language: language,
+				Results:  []SARIFResult{},
+				Properties: map[string]string{
+					"projectName": project.Name,
+				},
+			},
+			rules: make(map[string]int),
+		}
+		runs[tool] = run
+		toolNames = append(toolNames, tool)
+		return run
+	}
+	addRule := func(run *toolRun, rule SARIFRule) int {
+		if index, ok := run.rules[rule.ID]; ok {
+			return index
+		}
+		run.rules[rule.ID] = len(run.run.Tool.Driver.Rules)
+		run.run.Tool.Driver.Rules = append(run.run.Tool.Driver.Rules, rule)
+		return run.rules[rule.ID]
+	}
+
+	own := runFor(ToolName)
+	for _, check := range checks {
+		rule := SARIFRule{
+			ID:               check.ID,
+			Name:             check.ID,
+			ShortDescription: &SARIFMessage{Text: check.Title},
+			Properties: map[string]interface{}{
+				"category": quality.CategoryOf(check.ID),
+				"scope":    check.Scope,
+			},
+		}
+		if check.Guideline != "" {
+			rule.FullDescription = &SARIFMessage{Text: firstLine(check.Guideline)}
+			rule.Help = &SARIFMessage{Text: check.Guideline}
+		}
+		addRule(own, rule)
+	}
+
+	for _, finding := range findings {
+		run := own
+		if finding.Source == model.FindingSourceExternal {
+			tool := finding.Tool
+			if tool == "" {
+				tool = "external"
+			}
+			run = runFor(tool)
+		}
+		ruleIndex := addRule(run, SARIFRule{
+			ID:                   finding.RuleName,
+			Name:                 finding.RuleName,
+			DefaultConfiguration: &SARIFConfiguration{Level: sarifLevel(finding.Severity)},
+			Properties: map[string]interface{}{
+				"category": quality.CategoryOf(finding.RuleName),
+			},
+		})
+		run.run.Results = append(run.run.Results, sarifResult(finding, ruleIndex))
+	}
+
+	// The service's own run comes first, the tools follow by name
+	sort.Strings(toolNames[1:])
+	log := SARIFLog{Schema: SARIFSchema, Version: SARIFVersion}
+	for _, tool := range toolNames {
+		log.Runs = append(log.Runs, runs[tool].run)
+	}
+	return log
+}
+
+func sarifResult(finding model.Finding, ruleIndex int) SARIFResult {
+	result := SARIFResult{
+		RuleID:       finding.RuleName,
+		RuleIndex:    ruleIndex,
+		Level:        sarifLevel(finding.Severity),
+		Message:      SARIFMessage{Text: finding.Message},
+		Suppressions: []SARIFSuppression{},
+		Properties: map[string]interface{}{
+			"severity": finding.Severity,
+			"source":   finding.Source,
+			"status":   finding.TriageStatus(),
+		},
+	}
+	if finding.Path != "" {
+		location := SARIFPhysicalLocation{
+			ArtifactLocation: SARIFArtifactLocation{URI: strings.TrimPrefix(finding.Path, "./"), URIBaseID: "%SRCROOT%"},
+		}
+		if finding.Line > 0 {
+			location.Region = &SARIFRegion{StartLine: finding.Line}
+			if finding.Column > 0 {
+				location.Region.StartColumn = finding.Column
+			}
+		}
+		result.Locations = []SARIFLocation{{PhysicalLocation: location}}
+	}
+	if finding.Fingerprint != "" {
+		result.Fingerprints = map[string]string{fingerprintKey: finding.Fingerprint}
+	}
+	if finding.Suppressed {
+		result.Suppressions = append(result.Suppressions, SARIFSuppression{
+			Kind:          "external",
+			Status:        "accepted",
+			Justification: finding.SuppressionReason,
+		})
+	}
+	if finding.RuleNames != "" {
+		result.Properties["ruleNames"] = strings.Split(finding.RuleNames, ",")
+	}
+	if finding.Assignee != "" {
+		result.Properties["assignee"] = finding.Assignee
+	}
+	return result
+}
+
+// sarifLevel maps a normalized severity to a SARIF level
+func sarifLevel(severity string) string {
+	switch severity {
+	case model.SeverityHigh:
+		return "error"
+	case model.SeverityMedium:
+		return "warning"
+	case model.SeverityLow:
+		return "note"
+	default:
+		return "none"
+	}
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	return line
+}
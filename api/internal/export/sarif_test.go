@@ -0,0 +1,367 @@
+// internal/export/sarif_test.go
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"evraz_api/internal/model"
+)
+
+func sampleSARIF() SARIFLog {
+	checks := []Rule{
+		{ID: "project_dependency_management", Title: "Dependency management", Guideline: "Dependencies are pinned.\nEvery dependency has an exact version.", Scope: "project"},
+		{ID: "file_coding_standards", Title: "Coding standards", Scope: "file"},
+	}
+	findings := []model.Finding{
+		{
+			Source:   model.FindingSourceLLM,
+			RuleName: "file_coding_standards",
+			Severity: model.SeverityMedium,
+			Message:  "datetime.now() is called without a time zone",
+			Path:     "./app/clock.py",
+			Line:     5,
+			Column:   12,
+		},
+		{
+			Source:      model.FindingSourceComputed,
+			RuleName:    "file_coding_standards",
+			Severity:    model.SeverityHigh,
+			Message:     "The function has no docstring",
+			Path:        "./app/clock.py",
+			Line:        4,
+			Fingerprint: "4f2a9c",
+		},
+		{
+			Source:            model.FindingSourceExternal,
+			Tool:              "flake8",
+			RuleName:          "E501",
+			Severity:          model.SeverityLow,
+			Message:           "line too long (120 > 79 characters)",
+			Path:              "app/main.py",
+			Line:              10,
+			Suppressed:        true,
+			SuppressionReason: "Long URLs are allowed",
+		},
+		{
+			Source:   model.FindingSourceLLM,
+			RuleName: "project_dependency_management",
+			Severity: model.SeverityInfo,
+			Message:  "requirements.txt is missing at the root of the project directory",
+		},
+	}
+	return SARIF(model.Project{Name: "clock"}, "en", checks, findings)
+}
+
+func TestSARIFMatchesSchema(t *testing.T) {
+	data, err := json.Marshal(sampleSARIF())
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	schema := loadSchema(t)
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	for _, problem := range schema.validate(schema.root, document, "$") {
+		t.Error(problem)
+	}
+}
+
+func TestSARIFResults(t *testing.T) {
+	sarifLog := sampleSARIF()
+	if len(sarifLog.Runs) != 2 {
+		t.Fatalf("got %d runs, want the service's run and one for flake8", len(sarifLog.Runs))
+	}
+	own, external := sarifLog.Runs[0], sarifLog.Runs[1]
+	if own.Tool.Driver.Name != ToolName || external.Tool.Driver.Name != "flake8" {
+		t.Fatalf("got runs of %q and %q", own.Tool.Driver.Name, external.Tool.Driver.Name)
+	}
+
+	if len(own.Tool.Driver.Rules) != 2 {
+		t.Fatalf("got %d rules of the service, want one per check", len(own.Tool.Driver.Rules))
+	}
+	if help := own.Tool.Driver.Rules[0].Help; help == nil || !strings.HasPrefix(help.Text, "Dependencies are pinned.") {
+		t.Errorf("got help %+v, want the guideline of the check", help)
+	}
+
+	located := own.Results[0]
+	if located.Level != "warning" || len(located.Locations) != 1 {
+		t.Fatalf("got level %q and %d locations", located.Level, len(located.Locations))
+	}
+	location := located.Locations[0].PhysicalLocation
+	if location.ArtifactLocation.URI != "app/clock.py" || location.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("got artifact location %+v", location.ArtifactLocation)
+	}
+	if location.Region == nil || location.Region.StartLine != 5 || location.Region.StartColumn != 12 {
+		t.Errorf("got region %+v, want line 5 column 12", location.Region)
+	}
+	if located.Fingerprints != nil {
+		t.Errorf("got fingerprints %v for a finding without one", located.Fingerprints)
+	}
+
+	fingerprinted := own.Results[1]
+	if fingerprinted.Fingerprints[fingerprintKey] != "4f2a9c" {
+		t.Errorf("got fingerprints %v", fingerprinted.Fingerprints)
+	}
+	if len(fingerprinted.Suppressions) != 0 {
+		t.Errorf("got suppressions %v for a finding nothing suppresses", fingerprinted.Suppressions)
+	}
+
+	if len(external.Results) != 1 || len(external.Results[0].Suppressions) != 1 {
+		t.Fatalf("got results %+v, want the suppressed flake8 finding", external.Results)
+	}
+	suppression := external.Results[0].Suppressions[0]
+	if suppression.Kind != "external" || suppression.Status != "accepted" || suppression.Justification != "Long URLs are allowed" {
+		t.Errorf("got suppression %+v", suppression)
+	}
+
+	projectLevel := own.Results[2]
+	if projectLevel.Locations != nil || projectLevel.Level != "none" {
+		t.Errorf("got locations %v and level %q for a project-level finding", projectLevel.Locations, projectLevel.Level)
+	}
+}
+
+// TestSchemaValidator makes sure the validator rejects what the schema forbids, so that
+// TestSARIFMatchesSchema cannot pass by accident
+func TestSchemaValidator(t *testing.T) {
+	schema := loadSchema(t)
+	cases := map[string]string{
+		"unknown property":      `{"version": "2.1.0", "runs": [], "extra": true}`,
+		"missing runs":          `{"version": "2.1.0"}`,
+		"unknown version":       `{"version": "2.0.0", "runs": []}`,
+		"missing driver name":   `{"version": "2.1.0", "runs": [{"tool": {"driver": {}}}]}`,
+		"unknown level":         `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "level": "fatal"}]}]}`,
+		"line zero":             `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "a"}, "region": {"startLine": 0}}}]}]}]}`,
+		"invalid uri reference": `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "a%zz"}}}]}]}]}`,
+		"invalid date-time":     `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "invocations": [{"executionSuccessful": true, "startTimeUtc": "yesterday"}]}]}`,
+		"two graph indexes":     `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {"text": "m"}, "graphTraversals": [{"runGraphIndex": 0, "resultGraphIndex": 0}]}]}]}`,
+		"message without text":  `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "x"}}, "results": [{"message": {}}]}]}`,
+	}
+	for name, document := range cases {
+		t.Run(name, func(t *testing.T) {
+			var value interface{}
+			if err := json.Unmarshal([]byte(document), &value); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if problems := schema.validate(schema.root, value, "$"); len(problems) == 0 {
+				t.Error("the document is valid, want a violation")
+			}
+		})
+	}
+}
+
+// jsonSchema validates documents against testdata/sarif-schema-2.1.0.json, the official
+// OASIS schema of SARIF 2.1.0 (errata 01). It supports the draft-04 keywords the schema
+// uses; annotations such as default and description are ignored.
+type jsonSchema struct {
+	root        map[string]interface{}
+	definitions map[string]interface{}
+}
+
+func loadSchema(t *testing.T) *jsonSchema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "sarif-schema-2.1.0.json"))
+	if err != nil {
+		t.Fatalf("failed to read the schema: %v", err)
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(data, &root); err != nil {
+		t.Fatalf("failed to parse the schema: %v", err)
+	}
+	definitions, _ := root["definitions"].(map[string]interface{})
+	return &jsonSchema{root: root, definitions: definitions}
+}
+
+// validate returns the violations of the schema by the value at path
+func (s *jsonSchema) validate(schema map[string]interface{}, value interface{}, path string) []string {
+	if ref, ok := schema["$ref"].(string); ok {
+		name := strings.TrimPrefix(ref, "#/definitions/")
+		definition, ok := s.definitions[name].(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown reference %s", path, ref)}
+		}
+		return s.validate(definition, value, path)
+	}
+
+	var problems []string
+	if types, ok := schema["type"]; ok && !matchesType(types, value) {
+		return []string{fmt.Sprintf("%s: %v is not of type %v", path, value, types)}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, value, enum))
+		}
+	}
+	if anyOf, ok := schema["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, alternative := range anyOf {
+			if len(s.validate(alternative.(map[string]interface{}), value, path)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			problems = append(problems, fmt.Sprintf("%s: matches none of the alternatives", path))
+		}
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		matched := 0
+		for _, alternative := range oneOf {
+			if len(s.validate(alternative.(map[string]interface{}), value, path)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			problems = append(problems, fmt.Sprintf("%s: matches %d alternatives, want exactly one", path, matched))
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		problems = append(problems, s.validateObject(schema, value, path)...)
+	case []interface{}:
+		problems = append(problems, s.validateArray(schema, value, path)...)
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && value < minimum {
+			problems = append(problems, fmt.Sprintf("%s: %v is less than %v", path, value, minimum))
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && value > maximum {
+			problems = append(problems, fmt.Sprintf("%s: %v is greater than %v", path, value, maximum))
+		}
+	case string:
+		if pattern, ok := schema["pattern"].(string); ok && !regexp.MustCompile(pattern).MatchString(value) {
+			problems = append(problems, fmt.Sprintf("%s: %q does not match %s", path, value, pattern))
+		}
+		if format, ok := schema["format"].(string); ok && !matchesFormat(format, value) {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a valid %s", path, value, format))
+		}
+	}
+	return problems
+}
+
+func (s *jsonSchema) validateObject(schema map[string]interface{}, object map[string]interface{}, path string) []string {
+	var problems []string
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := object[name.(string)]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing required property %s", path, name))
+			}
+		}
+	}
+	properties, _ := schema["properties"].(map[string]interface{})
+	for name, property := range object {
+		propertyPath := path + "." + name
+		if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+			problems = append(problems, s.validate(propertySchema, property, propertyPath)...)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				problems = append(problems, fmt.Sprintf("%s: property is not allowed", propertyPath))
+			}
+		case map[string]interface{}:
+			problems = append(problems, s.validate(additional, property, propertyPath)...)
+		}
+	}
+	return problems
+}
+
+func (s *jsonSchema) validateArray(schema map[string]interface{}, array []interface{}, path string) []string {
+	var problems []string
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(array)) < minItems {
+		problems = append(problems, fmt.Sprintf("%s: has fewer than %v items", path, minItems))
+	}
+	if unique, _ := schema["uniqueItems"].(bool); unique {
+		seen := make(map[string]bool)
+		for i, item := range array {
+			encoded, _ := json.Marshal(item)
+			if seen[string(encoded)] {
+				problems = append(problems, fmt.Sprintf("%s[%d]: duplicate item", path, i))
+			}
+			seen[string(encoded)] = true
+		}
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range array {
+			problems = append(problems, s.validate(items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return problems
+}
+
+// matchesFormat reports whether the string is valid in the format
+func matchesFormat(format, value string) bool {
+	switch format {
+	case "uri":
+		uri, err := url.Parse(value)
+		return err == nil && uri.IsAbs()
+	case "uri-reference":
+		_, err := url.Parse(value)
+		return err == nil
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	}
+	return true
+}
+
+// matchesType reports whether the value is of the JSON type, or of one of the types, of
+// a type keyword
+func matchesType(types interface{}, value interface{}) bool {
+	names, ok := types.([]interface{})
+	if !ok {
+		names = []interface{}{types}
+	}
+	for _, name := range names {
+		switch name {
+		case "object":
+			if _, ok := value.(map[string]interface{}); ok {
+				return true
+			}
+		case "array":
+			if _, ok := value.([]interface{}); ok {
+				return true
+			}
+		case "string":
+			if _, ok := value.(string); ok {
+				return true
+			}
+		case "boolean":
+			if _, ok := value.(bool); ok {
+				return true
+			}
+		case "number":
+			if _, ok := value.(float64); ok {
+				return true
+			}
+		case "integer":
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+		case "null":
+			if value == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
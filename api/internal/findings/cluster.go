@@ -0,0 +1,208 @@
+// internal/findings/cluster.go
+
+package findings
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"evraz_api/internal/model"
+)
+
+// Options tunes how near-duplicate findings are clustered
+type Options struct {
+	// Token-set similarity at which findings in the same file are duplicates
+	FileThreshold float64
+	// Token-set similarity at which findings in different files are duplicates
+	CrossFileThreshold float64
+	// Optional message embeddings, indexed like the findings; nil disables them
+	Embeddings [][]float64
+	// Cosine similarity at which embedded findings are duplicates
+	EmbeddingThreshold float64
+}
+
+// DefaultOptions are tuned on review output, where prompts paraphrase each other
+var DefaultOptions = Options{
+	FileThreshold:      0.5,
+	CrossFileThreshold: 0.8,
+	EmbeddingThreshold: 0.9,
+}
+
+// stemLength truncates tokens to a common prefix, which folds most English and Russian
+// inflections ("handled"/"handling", "обработка"/"обработки") into one token
+const stemLength = 6
+
+var stopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "for": true, "is": true, "are": true, "be": true, "not": true,
+	"with": true, "this": true, "that": true, "it": true, "as": true, "by": true, "should": true,
+	"file": true, "code": true, "there": true, "no": true, "does": true, "do": true,
+	"и": true, "в": true, "не": true, "на": true, "с": true, "по": true, "для": true,
+	"что": true, "как": true, "из": true, "к": true, "или": true, "а": true, "нет": true,
+	"файл": true, "файле": true, "код": true, "коде": true, "следует": true,
+}
+
+// Normalize turns a message into its set of stemmed tokens, without stop words and numbers
+func Normalize(message string) map[string]bool {
+	tokens := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '_'
+	})
+	for _, word := range words {
+		if stopWords[word] || len([]rune(word)) < 2 {
+			continue
+		}
+		if runes := []rune(word); len(runes) > stemLength {
+			word = string(runes[:stemLength])
+		}
+		tokens[word] = true
+	}
+	return tokens
+}
+
+// Jaccard returns the token-set similarity of two normalized messages
+func Jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for token := range a {
+		if b[token] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// Cosine returns the cosine similarity of two embeddings
+func Cosine(a, b []float64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// Cluster groups near-duplicate findings and returns the clusters as indexes into the
+// slice, canonical finding first. Findings of the same rule at different lines are
+// separate occurrences and never merged, and only findings without a line (review
+// prompts) are merged across files.
+func Cluster(items []model.Finding, options Options) [][]int {
+	tokens := make([]map[string]bool, len(items))
+	for i, item := range items {
+		tokens[i] = Normalize(item.Message)
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range items {
+		for j := i + 1; j < len(items); j++ {
+			if duplicates(items, tokens, i, j, options) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range items {
+		root := find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	clusters := make([][]int, 0, len(groups))
+	for _, group := range groups {
+		sort.SliceStable(group, func(a, b int) bool {
+			return moreCanonical(items[group[a]], items[group[b]])
+		})
+		clusters = append(clusters, group)
+	}
+	sort.Slice(clusters, func(a, b int) bool { return clusters[a][0] < clusters[b][0] })
+	return clusters
+}
+
+func duplicates(items []model.Finding, tokens []map[string]bool, i, j int, options Options) bool {
+	a, b := items[i], items[j]
+	if a.RuleName == b.RuleName && a.Line != b.Line && a.Line > 0 && b.Line > 0 {
+		return false
+	}
+
+	threshold := options.FileThreshold
+	if !sameFile(a, b) {
+		if a.Line > 0 || b.Line > 0 {
+			return false
+		}
+		threshold = options.CrossFileThreshold
+	}
+
+	if Jaccard(tokens[i], tokens[j]) >= threshold {
+		return true
+	}
+	if options.Embeddings != nil && i < len(options.Embeddings) && j < len(options.Embeddings) {
+		embeddingThreshold := options.EmbeddingThreshold
+		if !sameFile(a, b) {
+			// Keep the same margin between file and cross-file matches as for tokens
+			embeddingThreshold += (1 - embeddingThreshold) / 2
+		}
+		return Cosine(options.Embeddings[i], options.Embeddings[j]) >= embeddingThreshold
+	}
+	return false
+}
+
+func sameFile(a, b model.Finding) bool {
+	if a.ProjectFileID == nil || b.ProjectFileID == nil {
+		return a.ProjectFileID == nil && b.ProjectFileID == nil
+	}
+	return *a.ProjectFileID == *b.ProjectFileID
+}
+
+var severityRanks = map[string]int{
+	model.SeverityHigh:   3,
+	model.SeverityMedium: 2,
+	model.SeverityLow:    1,
+	model.SeverityInfo:   0,
+}
+
+// moreCanonical prefers the more severe finding, then one with a location, then the
+// more detailed message
+func moreCanonical(a, b model.Finding) bool {
+	if severityRanks[a.Severity] != severityRanks[b.Severity] {
+		return severityRanks[a.Severity] > severityRanks[b.Severity]
+	}
+	if (a.Line > 0) != (b.Line > 0) {
+		return a.Line > 0
+	}
+	return len(a.Message) > len(b.Message)
+}
+
+// RuleNames lists the distinct rules of a cluster in order of appearance
+func RuleNames(items []model.Finding, cluster []int) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, i := range cluster {
+		if name := items[i].RuleName; !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
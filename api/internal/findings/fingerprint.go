@@ -0,0 +1,65 @@
+// internal/findings/fingerprint.go
+
+package findings
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// fingerprintContext is the number of lines around a finding's line that are part of its
+// fingerprint; enough to tell occurrences apart, few enough to survive nearby edits
+const fingerprintContext = 2
+
+// Fingerprint identifies a finding across runs and versions of a project. It combines the
+// rule, the file path, the normalized message and, for findings with a line, the code
+// around that line with whitespace removed, so that it does not change when unrelated
+// lines are added above the finding or the LLM words the same message slightly differently.
+func Fingerprint(finding model.Finding, content string) string {
+	tokens := make([]string, 0)
+	for token := range Normalize(finding.Message) {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	var builder strings.Builder
+	builder.WriteString(finding.RuleName)
+	builder.WriteByte(0)
+	builder.WriteString(finding.Path)
+	builder.WriteByte(0)
+	builder.WriteString(strings.Join(tokens, " "))
+	builder.WriteByte(0)
+	builder.WriteString(nearbyCode(content, finding.Line))
+
+	sum := sha256.Sum256([]byte(builder.String()))
+	return hex.EncodeToString(sum[:16])
+}
+
+// nearbyCode returns the lines around the given 1-based line with whitespace removed
+func nearbyCode(content string, line int) string {
+	if line <= 0 || content == "" {
+		return ""
+	}
+	lines := strings.Split(content, "\n")
+	if line > len(lines) {
+		return ""
+	}
+	start := line - 1 - fingerprintContext
+	if start < 0 {
+		start = 0
+	}
+	end := line + fingerprintContext
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	normalized := make([]string, 0, end-start)
+	for _, text := range lines[start:end] {
+		normalized = append(normalized, strings.Join(strings.Fields(text), ""))
+	}
+	return strings.Join(normalized, "\n")
+}
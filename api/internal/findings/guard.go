@@ -0,0 +1,193 @@
+// internal/findings/guard.go
+
+package findings
+
+import (
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+var (
+	definitionPattern = regexp.MustCompile(`(?m)^\s*(?:async\s+)?(?:def|class)\s+([A-Za-z_]\w*)`)
+	wordPattern       = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+	backtickPattern = regexp.MustCompile("`([^`\n]+)`")
+	callPattern     = regexp.MustCompile(`\b([A-Za-z_][\w.]*)\(\)?`)
+	snakePattern    = regexp.MustCompile(`\b([a-z][a-z0-9]*(?:_[a-z0-9]+)+)\b`)
+	pathPattern     = regexp.MustCompile(`(?:[\w.-]+/)+[\w-]+\.[A-Za-z]\w*|\b[\w-]+\.(?:py|toml|cfg|ini|txt|yaml|yml|json|md)\b`)
+	linePattern     = regexp.MustCompile(`(?i)\b(?:line|lines|строк[аеиу]?|строках)\s+(\d+)`)
+	// absencePattern recognizes messages about files the project lacks, which cite paths
+	// that rightly do not exist
+	absencePattern = regexp.MustCompile(`(?i)\b(?:missing|absent|not found|does not exist|doesn't exist|no such file|lacks?)\b|отсутству|не найден|не существует|нет файла`)
+)
+
+// wellKnownNames are identifiers a review may cite that come from Python itself or
+// common libraries rather than from the project
+var wellKnownNames = map[string]bool{
+	"print": true, "len": true, "open": true, "isinstance": true, "super": true, "__init__": true,
+	"logging": true, "logger": true, "getLogger": true, "datetime": true, "utcnow": true, "now": true,
+	"timezone": true, "zoneinfo": true, "pytz": true, "asyncio": true, "BaseSettings": true,
+	"BaseModel": true, "Exception": true, "except": true, "raise": true, "try": true,
+	"sqlite": true, "pytest": true, "unittest": true, "mock": true, "typing": true,
+	"yapf": true, "isort": true, "flake8": true, "pylint": true, "mypy": true, "ruff": true,
+	"setup": true, "requirements": true, "README": true, "pyproject": true,
+}
+
+// SymbolIndex holds what a finding may legitimately refer to in a project
+type SymbolIndex struct {
+	words     map[string]bool // Every identifier in the project, including defined symbols
+	paths     map[string]bool // Full paths without leading slash
+	names     map[string]bool // Base names of files
+	lineCount map[uint]int    // Project file ID -> number of lines
+}
+
+// NewSymbolIndex indexes the files of a project
+func NewSymbolIndex(files []model.ProjectFile) *SymbolIndex {
+	index := &SymbolIndex{
+		words:     make(map[string]bool),
+		paths:     make(map[string]bool),
+		names:     make(map[string]bool),
+		lineCount: make(map[uint]int, len(files)),
+	}
+	for _, file := range files {
+		filePath := strings.TrimPrefix(file.Path, "/")
+		index.paths[filePath] = true
+		index.names[path.Base(filePath)] = true
+		for _, dir := range strings.Split(path.Dir(filePath), "/") {
+			index.names[dir] = true
+		}
+		index.lineCount[file.ID] = strings.Count(file.Content, "\n") + 1
+
+		for _, word := range wordPattern.FindAllString(file.Content, -1) {
+			index.words[word] = true
+		}
+		for _, match := range definitionPattern.FindAllStringSubmatch(file.Content, -1) {
+			index.words[match[1]] = true
+		}
+	}
+	return index
+}
+
+// GuardResult lists what a finding cites that the project does not contain
+type GuardResult struct {
+	UnknownSymbols []string
+	UnknownPaths   []string
+	InvalidLines   []int
+}
+
+// Flagged reports whether the finding cites anything that does not exist
+func (r GuardResult) Flagged() bool {
+	return len(r.UnknownSymbols) > 0 || len(r.UnknownPaths) > 0 || len(r.InvalidLines) > 0
+}
+
+// Reason describes why the finding was flagged
+func (r GuardResult) Reason() string {
+	var parts []string
+	if len(r.UnknownSymbols) > 0 {
+		parts = append(parts, "unknown symbols: "+strings.Join(r.UnknownSymbols, ", "))
+	}
+	if len(r.UnknownPaths) > 0 {
+		parts = append(parts, "unknown paths: "+strings.Join(r.UnknownPaths, ", "))
+	}
+	if len(r.InvalidLines) > 0 {
+		lines := make([]string, len(r.InvalidLines))
+		for i, line := range r.InvalidLines {
+			lines[i] = strconv.Itoa(line)
+		}
+		parts = append(parts, "lines out of range: "+strings.Join(lines, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Check verifies the symbols, paths and line numbers a finding cites. Line numbers are
+// only checked for findings attached to a file, and paths are not checked when the
+// finding reports that files are missing.
+func (index *SymbolIndex) Check(finding model.Finding) GuardResult {
+	var result GuardResult
+	message := finding.Message
+	seen := make(map[string]bool)
+	reportsAbsence := absencePattern.MatchString(message)
+
+	for _, cited := range pathPattern.FindAllString(message, -1) {
+		cited = strings.Trim(cited, "./")
+		if cited == "" || seen[cited] || strings.Contains(cited, "://") {
+			continue
+		}
+		seen[cited] = true
+		if !reportsAbsence && !index.knownPath(cited) {
+			result.UnknownPaths = append(result.UnknownPaths, cited)
+		}
+	}
+
+	for _, symbol := range citedSymbols(message) {
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		if !index.knownSymbol(symbol) {
+			result.UnknownSymbols = append(result.UnknownSymbols, symbol)
+		}
+	}
+
+	if finding.ProjectFileID != nil {
+		if count, ok := index.lineCount[*finding.ProjectFileID]; ok {
+			for _, match := range linePattern.FindAllStringSubmatch(message, -1) {
+				line, err := strconv.Atoi(match[1])
+				if err == nil && (line < 1 || line > count) {
+					result.InvalidLines = append(result.InvalidLines, line)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// citedSymbols extracts the identifiers a message refers to: quoted code, calls and
+// snake_case names. Plain words are left alone since they are usually prose.
+func citedSymbols(message string) []string {
+	var symbols []string
+	for _, match := range backtickPattern.FindAllStringSubmatch(message, -1) {
+		quoted := strings.TrimSpace(match[1])
+		if pathPattern.MatchString(quoted) {
+			continue
+		}
+		for _, word := range wordPattern.FindAllString(quoted, -1) {
+			symbols = append(symbols, word)
+		}
+	}
+	withoutPaths := pathPattern.ReplaceAllString(message, " ")
+	for _, match := range callPattern.FindAllStringSubmatch(withoutPaths, -1) {
+		if !strings.Contains(match[0], "(") {
+			continue
+		}
+		parts := strings.Split(match[1], ".")
+		symbols = append(symbols, parts[len(parts)-1])
+	}
+	for _, match := range snakePattern.FindAllStringSubmatch(withoutPaths, -1) {
+		symbols = append(symbols, match[1])
+	}
+	return symbols
+}
+
+// knownSymbol reports whether the symbol appears anywhere in the project; very short
+// names are too ambiguous to judge
+func (index *SymbolIndex) knownSymbol(symbol string) bool {
+	return len(symbol) < 3 || wellKnownNames[symbol] || index.words[symbol]
+}
+
+// knownPath accepts full paths, trailing parts of paths and bare file or directory names
+func (index *SymbolIndex) knownPath(cited string) bool {
+	if index.paths[cited] || index.names[cited] {
+		return true
+	}
+	for known := range index.paths {
+		if strings.HasSuffix(known, "/"+cited) || strings.HasPrefix(known, cited+"/") {
+			return true
+		}
+	}
+	return false
+}
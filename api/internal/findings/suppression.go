@@ -0,0 +1,137 @@
+// internal/findings/suppression.go
+
+package findings
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/utils"
+)
+
+// Suppression tells why a finding is suppressed
+type Suppression struct {
+	RuleID *uint // Empty when the baseline or a rule of the configuration file matched
+	Reason string
+}
+
+// Suppressor matches findings against the suppression rules and the baseline of a project
+type Suppressor struct {
+	rules       []compiledRule
+	baseline    map[string]bool
+	baselineWhy string
+}
+
+type compiledRule struct {
+	rule    model.SuppressionRule
+	path    *utils.Glob
+	message *regexp.Regexp
+}
+
+// NewSuppressor prepares the rules that have not expired at the given time and the
+// fingerprints of the baseline, which may be nil. Invalid rules are skipped; rules without
+// an ID come from the project's configuration file.
+func NewSuppressor(rules []model.SuppressionRule, baseline *model.Baseline, now time.Time) *Suppressor {
+	suppressor := &Suppressor{baseline: make(map[string]bool)}
+	for _, rule := range rules {
+		if rule.Expired(now) {
+			continue
+		}
+		compiled, err := compileRule(rule)
+		if err != nil {
+			continue
+		}
+		suppressor.rules = append(suppressor.rules, compiled)
+	}
+	if baseline != nil {
+		for _, entry := range baseline.Fingerprints {
+			suppressor.baseline[entry.Fingerprint] = true
+		}
+		suppressor.baselineWhy = fmt.Sprintf("baseline of %s", baseline.CreatedAt.Format("2006-01-02"))
+		if baseline.Reason != "" {
+			suppressor.baselineWhy += ": " + baseline.Reason
+		}
+	}
+	return suppressor
+}
+
+// Match returns why the finding is suppressed. Rules are tried before the baseline, since
+// their reason says more about the finding.
+func (s *Suppressor) Match(finding model.Finding) (Suppression, bool) {
+	for _, compiled := range s.rules {
+		if !compiled.matches(finding) {
+			continue
+		}
+		suppression := Suppression{Reason: compiled.rule.Reason}
+		if compiled.rule.ID != 0 {
+			ruleID := compiled.rule.ID
+			suppression.RuleID = &ruleID
+		}
+		return suppression, true
+	}
+	if finding.Fingerprint != "" && s.baseline[finding.Fingerprint] {
+		return Suppression{Reason: s.baselineWhy}, true
+	}
+	return Suppression{}, false
+}
+
+// ValidateRule checks that the rule has a reason, at least one matcher and valid patterns
+func ValidateRule(rule model.SuppressionRule) error {
+	if strings.TrimSpace(rule.Reason) == "" {
+		return errors.New("a suppression rule needs a reason")
+	}
+	if rule.RuleName == "" && rule.PathGlob == "" && rule.MessagePattern == "" {
+		return errors.New("a suppression rule needs a rule name, a path glob or a message pattern")
+	}
+	_, err := compileRule(rule)
+	return err
+}
+
+func compileRule(rule model.SuppressionRule) (compiledRule, error) {
+	compiled := compiledRule{rule: rule}
+	if rule.PathGlob != "" {
+		glob, err := utils.CompileGlob(rule.PathGlob)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid path glob %q: %w", rule.PathGlob, err)
+		}
+		compiled.path = glob
+	}
+	if rule.MessagePattern != "" {
+		pattern, err := regexp.Compile(rule.MessagePattern)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid message pattern %q: %w", rule.MessagePattern, err)
+		}
+		compiled.message = pattern
+	}
+	return compiled, nil
+}
+
+func (c compiledRule) matches(finding model.Finding) bool {
+	if c.rule.RuleName != "" && !matchesRuleName(c.rule.RuleName, finding) {
+		return false
+	}
+	if c.path != nil && (finding.Path == "" || !c.path.Match(finding.Path)) {
+		return false
+	}
+	if c.message != nil && !c.message.MatchString(finding.Message) {
+		return false
+	}
+	return true
+}
+
+// matchesRuleName also looks at the rules merged into a canonical finding
+func matchesRuleName(ruleName string, finding model.Finding) bool {
+	if finding.RuleName == ruleName {
+		return true
+	}
+	for _, name := range strings.Split(finding.RuleNames, ",") {
+		if strings.TrimSpace(name) == ruleName {
+			return true
+		}
+	}
+	return false
+}
@@ -4,12 +4,26 @@ package handler
 
 import (
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"evraz_api/internal/dto"
+	"evraz_api/internal/export"
+	"evraz_api/internal/findings"
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/ingest"
+	"evraz_api/internal/model"
+	"evraz_api/internal/prompts"
+	"evraz_api/internal/quality"
 	"evraz_api/internal/repository"
 	"evraz_api/internal/usecase"
 	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/phpdave11/gofpdf"
@@ -19,20 +33,32 @@ type ProjectHandlers struct {
 	ProjectUsecase         *usecase.ProjectUsecase
 	ProjectFileUsecase     *usecase.ProjectFileUsecase
 	ProjectAnalysisUsecase *usecase.ProjectAnalysisUsecase
+	TranslationUsecase     *usecase.TranslationUsecase
+	FindingUsecase         *usecase.FindingUsecase
+	QAUsecase              *usecase.QAUsecase
 	FileAnalysisRepo       repository.FileAnalysisRepository
+	Catalog                *i18n.Catalog
 }
 
 func NewProjectHandlers(
 	projectUsecase *usecase.ProjectUsecase,
 	projectFileUsecase *usecase.ProjectFileUsecase,
 	projectAnalysisUsecase *usecase.ProjectAnalysisUsecase,
+	translationUsecase *usecase.TranslationUsecase,
+	findingUsecase *usecase.FindingUsecase,
+	qaUsecase *usecase.QAUsecase,
 	fileAnalysisRepo repository.FileAnalysisRepository,
+	catalog *i18n.Catalog,
 ) *ProjectHandlers {
 	return &ProjectHandlers{
 		ProjectUsecase:         projectUsecase,
 		ProjectFileUsecase:     projectFileUsecase,
 		ProjectAnalysisUsecase: projectAnalysisUsecase,
+		TranslationUsecase:     translationUsecase,
+		FindingUsecase:         findingUsecase,
+		QAUsecase:              qaUsecase,
 		FileAnalysisRepo:       fileAnalysisRepo,
+		Catalog:                catalog,
 	}
 }
 
@@ -53,6 +79,11 @@ func (h *ProjectHandlers) UploadProject(c *gin.Context) {
 	}
 	req.File = file
 
+	// The coverage report is optional
+	if coverage, err := c.FormFile("coverage"); err == nil {
+		req.Coverage = coverage
+	}
+
 	// Call the use case with the DTO
 	projectDTO, err := h.ProjectUsecase.UploadProject(req)
 	if err != nil {
@@ -60,86 +91,1239 @@ func (h *ProjectHandlers) UploadProject(c *gin.Context) {
 		return
 	}
 
-	// Return response using DTO
-	resp := dto.UploadProjectResponse{
-		Message: "Project uploaded successfully",
-		Project: projectDTO,
+	// Return response using DTO
+	resp := dto.UploadProjectResponse{
+		Message: h.Catalog.T(projectDTO.Language, "message.project_uploaded"),
+		Project: projectDTO,
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+func (h *ProjectHandlers) AnalyzeProject(c *gin.Context) {
+	var req dto.AnalyzeProjectRequest
+
+	// Bind JSON input to DTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	// Call the use case with the project ID
+	if err := h.ProjectAnalysisUsecase.AnalyzeProject(req.ProjectID, req.Language); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response DTO
+	resp := dto.AnalyzeProjectResponse{
+		Message: h.Catalog.T(req.Language, "message.project_analyzed"),
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *ProjectHandlers) AnalyzeFile(c *gin.Context) {
+	var req dto.AnalyzeFileRequest
+
+	// Bind JSON input to DTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	// Call the use case with the file ID
+	if err := h.ProjectAnalysisUsecase.AnalyzeFile(req.FileID, req.Language); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Return response DTO
+	resp := dto.AnalyzeFileResponse{
+		Message: h.Catalog.T(req.Language, "message.file_analyzed"),
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "all projects" endpoint
+func (h *ProjectHandlers) GetAllProjects(c *gin.Context) {
+	projects, err := h.ProjectUsecase.GetAllProjects()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	projectIDs := make([]uint, len(projects))
+	for i, project := range projects {
+		projectIDs[i] = project.ID
+	}
+	scores, err := h.ProjectAnalysisUsecase.GetLatestScores(projectIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	projectDTOs := make([]dto.ProjectDTO, len(projects))
+	for i, project := range projects {
+		projectDTOs[i] = dto.ProjectDTO{
+			ID:                    project.ID,
+			ProgrammingLanguageID: project.ProgrammingLanguageID,
+			Name:                  project.Name,
+			Path:                  project.Path,
+			Tree:                  project.Tree,
+			WasAnalyzed:           project.WasAnalyzed,
+			Language:              project.Language,
+			Version:               project.Version,
+			RootProjectID:         project.RootProjectID,
+		}
+		if run, ok := scores[project.ID]; ok {
+			projectDTOs[i].Score = run.Score
+			projectDTOs[i].Grade = run.Grade
+		}
+	}
+
+	resp := dto.GetAllProjectsResponse{
+		Projects: projectDTOs,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "project versions" endpoint
+func (h *ProjectHandlers) GetProjectVersions(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	versions, err := h.ProjectUsecase.GetVersions(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	versionDTOs := make([]dto.ProjectVersionDTO, len(versions))
+	for i, version := range versions {
+		versionDTOs[i] = dto.ProjectVersionDTO{
+			ProjectID:   version.ID,
+			Version:     version.Version,
+			WasAnalyzed: version.WasAnalyzed,
+			CreatedAt:   version.CreatedAt,
+		}
+	}
+
+	resp := dto.GetProjectVersionsResponse{
+		Versions: versionDTOs,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "project overview" endpoint
+func (h *ProjectHandlers) GetProjectOverview(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	project, files, analysisResults, err := h.ProjectUsecase.GetProjectOverview(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Substitute stored translations when another language is requested
+	if language := c.Query("lang"); language != "" {
+		if !h.Catalog.IsSupported(language) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+			return
+		}
+		analysisResults, err = h.TranslationUsecase.LocalizeProjectResults(analysisResults, h.Catalog.Resolve(language))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	projectDTO := dto.ProjectDTO{
+		ID:                    project.ID,
+		ProgrammingLanguageID: project.ProgrammingLanguageID,
+		Name:                  project.Name,
+		Path:                  project.Path,
+		Tree:                  project.Tree,
+		WasAnalyzed:           project.WasAnalyzed,
+		Language:              project.Language,
+		Version:               project.Version,
+		RootProjectID:         project.RootProjectID,
+		Summary:               project.Summary,
+		ConfigPath:            project.ConfigPath,
+	}
+	if project.Config != "" {
+		projectDTO.Config = json.RawMessage(project.Config)
+	}
+
+	coverage, err := h.ProjectUsecase.GetProjectCoverage(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	coverageByFile := make(map[uint]model.FileCoverage, len(coverage))
+	for _, fileCoverage := range coverage {
+		coverageByFile[fileCoverage.ProjectFileID] = fileCoverage
+	}
+
+	fileDTOs := make([]dto.ProjectFileDTO, len(files))
+	for i, file := range files {
+		fileDTOs[i] = dto.ProjectFileDTO{
+			ID:          file.ID,
+			Name:        file.Name,
+			WasAnalyzed: file.WasAnalyzed,
+			Summary:     file.Summary,
+		}
+		if fileCoverage, ok := coverageByFile[file.ID]; ok {
+			fileDTOs[i].Coverage = toFileCoverageDTO(fileCoverage)
+		}
+	}
+
+	analysisResultDTOs := make([]dto.ProjectAnalysisResultDTO, len(analysisResults))
+	for i, result := range analysisResults {
+		analysisResultDTOs[i] = dto.ProjectAnalysisResultDTO{
+			ID:              result.ID,
+			PromptName:      result.PromptName,
+			Compliance:      result.Compliance,
+			Issues:          result.Issues,
+			Recommendations: result.Recommendations,
+			Status:          result.Status,
+			StatusReason:    result.StatusReason,
+		}
+	}
+
+	packages, err := h.ProjectAnalysisUsecase.GetPackageSummaries(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	packageDTOs := make([]dto.PackageSummaryDTO, len(packages))
+	for i, pkg := range packages {
+		packageDTOs[i] = dto.PackageSummaryDTO{
+			Path:    pkg.Path,
+			Files:   pkg.Files,
+			Summary: pkg.Summary,
+		}
+	}
+
+	resp := dto.GetProjectOverviewResponse{
+		Project:                projectDTO,
+		Packages:               packageDTOs,
+		Files:                  fileDTOs,
+		ProjectAnalysisResults: analysisResultDTOs,
+	}
+	if len(coverage) > 0 {
+		resp.Coverage = toFileCoverageDTO(model.TotalCoverage(coverage))
+	}
+
+	scores, err := h.ProjectAnalysisUsecase.GetLatestScores([]uint{project.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if run, ok := scores[project.ID]; ok {
+		resp.Quality = toQualityDTO(run)
+		resp.Project.Score = run.Score
+		resp.Project.Grade = run.Grade
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// writeFindings lists the canonical findings of a file; review findings are left out
+// when the issues are already listed per check
+func (h *ProjectHandlers) writeFindings(pdf *gofpdf.Fpdf, language string, findings []model.Finding, includeReview bool) {
+	var listed []model.Finding
+	for _, finding := range findings {
+		if finding.Source != model.FindingSourceLLM || includeReview {
+			listed = append(listed, finding)
+		}
+	}
+	if len(listed) == 0 {
+		return
+	}
+
+	pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.findings"), "", "", false)
+	for _, finding := range listed {
+		rules := finding.RuleNames
+		if rules == "" {
+			rules = finding.RuleName
+		}
+		text := h.Catalog.T(language, "pdf.finding", finding.Severity, rules, finding.Message)
+		if finding.Line > 0 {
+			text = h.Catalog.T(language, "pdf.finding_at_line", finding.Severity, rules, finding.Line, finding.Message)
+		}
+		pdf.MultiCell(0, 8, text, "", "", false)
+	}
+	pdf.Ln(5)
+}
+
+// writeQualityChart draws the score of a run with a bar per category
+func (h *ProjectHandlers) writeQualityChart(pdf *gofpdf.Fpdf, fontName, language string, run model.AnalysisRun) {
+	const (
+		labelWidth = 50.0
+		barWidth   = 100.0
+		barHeight  = 5.0
+		rowHeight  = 8.0
+	)
+
+	pdf.SetFont(fontName, "B", 14)
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.quality_title"))
+	pdf.Ln(10)
+	pdf.SetFont(fontName, "", 12)
+	pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.quality_score", *run.Score, run.Grade, run.ID, run.CodeLines), "", "", false)
+
+	pdf.SetFont(fontName, "", 10)
+	left, _ := pdf.GetXY()
+	for _, category := range run.CategoryScores {
+		_, y := pdf.GetXY()
+		pdf.SetXY(left, y)
+		pdf.CellFormat(labelWidth, rowHeight, h.Catalog.T(language, "quality."+category.Category), "", 0, "L", false, 0, "")
+
+		barTop := y + (rowHeight-barHeight)/2
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Rect(left+labelWidth, barTop, barWidth, barHeight, "D")
+		switch {
+		case category.Score >= 75:
+			pdf.SetFillColor(76, 175, 80)
+		case category.Score >= 45:
+			pdf.SetFillColor(255, 193, 7)
+		default:
+			pdf.SetFillColor(229, 57, 53)
+		}
+		if category.Score > 0 {
+			pdf.Rect(left+labelWidth, barTop, barWidth*category.Score/100, barHeight, "F")
+		}
+
+		pdf.SetXY(left+labelWidth+barWidth+3, y)
+		pdf.CellFormat(20, rowHeight, fmt.Sprintf("%.1f", category.Score), "", 1, "L", false, 0, "")
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetFillColor(255, 255, 255)
+	pdf.SetX(left)
+	pdf.Ln(4)
+	pdf.SetFont(fontName, "", 12)
+}
+
+// writeRunDiff adds the changes between two runs: new and resolved findings and the checks
+// whose verdict changed; persisting findings are only counted
+func (h *ProjectHandlers) writeRunDiff(pdf *gofpdf.Fpdf, fontName, language string, diff *usecase.RunDiff) {
+	pdf.SetFont(fontName, "B", 14)
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.diff_title", diff.From.ID, diff.To.ID))
+	pdf.Ln(10)
+	pdf.SetFont(fontName, "", 12)
+	pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.diff_summary",
+		len(diff.New), len(diff.Resolved), len(diff.Persisting)), "", "", false)
+
+	writeList := func(key string, findings []model.AnalysisRunFinding) {
+		if len(findings) == 0 {
+			return
+		}
+		pdf.MultiCell(0, 10, h.Catalog.T(language, key), "", "", false)
+		for _, finding := range findings {
+			location := finding.Path
+			if finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", finding.Path, finding.Line)
+			}
+			text := h.Catalog.T(language, "pdf.finding", finding.Severity, finding.RuleName, finding.Message)
+			if location != "" {
+				text = h.Catalog.T(language, "pdf.diff_finding", finding.Severity, finding.RuleName, location, finding.Message)
+			}
+			pdf.MultiCell(0, 8, text, "", "", false)
+		}
+	}
+	writeList("pdf.diff_new", diff.New)
+	writeList("pdf.diff_resolved", diff.Resolved)
+
+	if len(diff.ComplianceFlips) > 0 {
+		pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.diff_flips"), "", "", false)
+		for _, flip := range diff.ComplianceFlips {
+			title := h.Catalog.CheckTitle(language, flip.PromptName)
+			from := h.Catalog.Compliance(language, flip.From)
+			to := h.Catalog.Compliance(language, flip.To)
+			text := h.Catalog.T(language, "pdf.diff_flip", title, from, to)
+			if flip.Path != "" {
+				text = h.Catalog.T(language, "pdf.diff_flip_file", title, flip.Path, from, to)
+			}
+			pdf.MultiCell(0, 8, text, "", "", false)
+		}
+	}
+	pdf.Ln(10)
+}
+
+func toFileCoverageDTO(coverage model.FileCoverage) *dto.FileCoverageDTO {
+	return &dto.FileCoverageDTO{
+		LineRate:        coverage.LineRate(),
+		BranchRate:      coverage.BranchRate(),
+		LinesValid:      coverage.LinesValid,
+		LinesCovered:    coverage.LinesCovered,
+		BranchesValid:   coverage.BranchesValid,
+		BranchesCovered: coverage.BranchesCovered,
+		MissingLines:    coverage.MissingLines,
+	}
+}
+
+// Handler for the "project settings" endpoint
+func (h *ProjectHandlers) UpdateProjectSettings(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	var req dto.UpdateProjectSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+	if !h.Catalog.IsSupported(req.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+
+	project, err := h.ProjectUsecase.UpdateProjectSettings(uint(projectID), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := dto.UpdateProjectSettingsResponse{
+		Message: h.Catalog.T(project.Language, "message.settings_updated"),
+		Project: dto.ProjectDTO{
+			ID:                    project.ID,
+			ProgrammingLanguageID: project.ProgrammingLanguageID,
+			Name:                  project.Name,
+			Path:                  project.Path,
+			Tree:                  project.Tree,
+			WasAnalyzed:           project.WasAnalyzed,
+			Language:              project.Language,
+			Version:               project.Version,
+			RootProjectID:         project.RootProjectID,
+		},
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "translate project" endpoint
+func (h *ProjectHandlers) TranslateProject(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	language := c.Query("lang")
+	if !h.Catalog.IsSupported(language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+
+	translated, err := h.TranslationUsecase.TranslateProject(uint(projectID), language)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := dto.TranslateProjectResponse{
+		Message:    h.Catalog.T(language, "message.project_translated"),
+		Language:   h.Catalog.Resolve(language),
+		Translated: translated,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "ask the codebase" endpoint
+func (h *ProjectHandlers) Ask(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	var req dto.AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+	if req.Language != "" && !h.Catalog.IsSupported(req.Language) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+		return
+	}
+
+	answer, err := h.QAUsecase.Ask(uint(projectID), req.Question, req.Language)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	citationDTOs := make([]dto.CitationDTO, len(answer.Citations))
+	for i, citation := range answer.Citations {
+		citationDTOs[i] = dto.CitationDTO(citation)
+	}
+	resp := dto.AskResponse{
+		MessageID: answer.MessageID,
+		Answer:    answer.Answer,
+		Citations: citationDTOs,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "conversation" endpoint
+func (h *ProjectHandlers) GetConversation(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	messages, err := h.QAUsecase.GetConversation(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	messageDTOs := make([]dto.QAMessageDTO, len(messages))
+	for i, message := range messages {
+		messageDTOs[i] = dto.QAMessageDTO{
+			ID:        message.ID,
+			Role:      message.Role,
+			Content:   message.Content,
+			CreatedAt: message.CreatedAt,
+		}
+		if message.Citations != "" {
+			if err := json.Unmarshal([]byte(message.Citations), &messageDTOs[i].Citations); err != nil {
+				log.Printf("failed to parse citations of message %d: %v", message.ID, err)
+			}
+		}
+	}
+
+	resp := dto.GetConversationResponse{
+		Messages: messageDTOs,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "import linter report" endpoint
+func (h *ProjectHandlers) ImportLinterReport(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	// The tool is detected from the report when not given
+	tool := c.PostForm("tool")
+	if tool != "" && !ingest.IsSupportedTool(tool) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported tool"})
+		return
+	}
+
+	report, err := c.FormFile("report")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to get report"})
+		return
+	}
+	file, err := report.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read report"})
+		return
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read report"})
+		return
+	}
+
+	result, err := h.FindingUsecase.ImportLinterReport(uint(projectID), tool, content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := dto.ImportLinterReportResponse{
+		Message:   h.Catalog.T(c.Query("lang"), "message.linter_imported"),
+		Tool:      result.Tool,
+		Imported:  result.Imported,
+		Unmatched: result.Unmatched,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "project findings" endpoint
+func (h *ProjectHandlers) GetFindings(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	filter := usecase.FindingFilter{
+		Source:            c.Query("source"),
+		IncludeDuplicates: c.Query("duplicates") == "true",
+		IncludeHidden:     c.Query("hidden") == "true",
+		IncludeSuppressed: c.Query("suppressed") == "true",
+		Status:            c.Query("status"),
+		Assignee:          c.Query("assignee"),
+	}
+	// The "all findings" view shows everything reports leave out
+	if c.Query("all") == "true" {
+		filter.IncludeDuplicates = true
+		filter.IncludeHidden = true
+		filter.IncludeSuppressed = true
+	}
+	findings, err := h.FindingUsecase.GetFindings(uint(projectID), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	findingDTOs := make([]dto.FindingDTO, len(findings))
+	for i, finding := range findings {
+		findingDTOs[i] = toFindingDTO(finding)
+	}
+
+	resp := dto.GetFindingsResponse{
+		Findings: findingDTOs,
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toFindingDTO(finding model.Finding) dto.FindingDTO {
+	return dto.FindingDTO{
+		ID:              finding.ID,
+		ProjectFileID:   finding.ProjectFileID,
+		Source:          finding.Source,
+		RuleName:        finding.RuleName,
+		Tool:            finding.Tool,
+		Severity:        finding.Severity,
+		Message:         finding.Message,
+		Path:            finding.Path,
+		Line:            finding.Line,
+		Column:          finding.Column,
+		CanonicalID:     finding.CanonicalID,
+		RuleNames:       finding.RuleNames,
+		Occurrences:     finding.Occurrences,
+		GPTCallID:       finding.GPTCallID,
+		Supported:       finding.Supported,
+		Confidence:      finding.Confidence,
+		CriticReason:    finding.CriticReason,
+		CriticGPTCallID: finding.CriticGPTCallID,
+		Hidden:          finding.Hidden,
+		AnalysisRunID:   finding.AnalysisRunID,
+		Hallucinated:    finding.Hallucinated,
+		GuardReason:     finding.GuardReason,
+		Fingerprint:     finding.Fingerprint,
+		Status:          finding.TriageStatus(),
+		Assignee:        finding.Assignee,
+		TriagedAt:       finding.TriagedAt,
+
+		Suppressed:        finding.Suppressed,
+		SuppressionRuleID: finding.SuppressionRuleID,
+		SuppressionReason: finding.SuppressionReason,
+	}
+}
+
+// parseFindingParams reads the project and finding IDs of a finding route; it answers the
+// request itself and returns false when one of them is invalid
+func parseFindingParams(c *gin.Context) (uint, uint, bool) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return 0, 0, false
+	}
+	findingID, err := strconv.ParseUint(c.Param("finding_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid finding_id"})
+		return 0, 0, false
+	}
+	return uint(projectID), uint(findingID), true
+}
+
+// Handler for the "update finding triage" endpoint
+func (h *ProjectHandlers) UpdateFindingTriage(c *gin.Context) {
+	projectID, findingID, ok := parseFindingParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateFindingTriageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+	if req.Status != nil && !model.IsFindingStatus(*req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown status"})
+		return
+	}
+
+	finding, err := h.FindingUsecase.UpdateTriage(projectID, findingID, usecase.TriageUpdate{
+		Status:   req.Status,
+		Assignee: req.Assignee,
+		Actor:    req.Actor,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toFindingDTO(*finding))
+}
+
+// Handler for the "add finding comment" endpoint
+func (h *ProjectHandlers) AddFindingComment(c *gin.Context) {
+	projectID, findingID, ok := parseFindingParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AddFindingCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+
+	comment, err := h.FindingUsecase.AddComment(projectID, findingID, req.Author, req.Body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.FindingCommentDTO{
+		ID:        comment.ID,
+		Author:    comment.Author,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+	})
+}
+
+// Handler for the "finding comments" endpoint
+func (h *ProjectHandlers) GetFindingComments(c *gin.Context) {
+	projectID, findingID, ok := parseFindingParams(c)
+	if !ok {
+		return
+	}
+
+	comments, err := h.FindingUsecase.GetComments(projectID, findingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	commentDTOs := make([]dto.FindingCommentDTO, len(comments))
+	for i, comment := range comments {
+		commentDTOs[i] = dto.FindingCommentDTO{
+			ID:        comment.ID,
+			Author:    comment.Author,
+			Body:      comment.Body,
+			CreatedAt: comment.CreatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, dto.GetFindingCommentsResponse{Comments: commentDTOs})
+}
+
+// Handler for the "finding history" endpoint
+func (h *ProjectHandlers) GetFindingHistory(c *gin.Context) {
+	projectID, findingID, ok := parseFindingParams(c)
+	if !ok {
+		return
+	}
+
+	events, err := h.FindingUsecase.GetHistory(projectID, findingID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	eventDTOs := make([]dto.FindingEventDTO, len(events))
+	for i, event := range events {
+		eventDTOs[i] = dto.FindingEventDTO{
+			ID:        event.ID,
+			Actor:     event.Actor,
+			Action:    event.Action,
+			OldValue:  event.OldValue,
+			NewValue:  event.NewValue,
+			Detail:    event.Detail,
+			CreatedAt: event.CreatedAt,
+		}
+	}
+	c.JSON(http.StatusOK, dto.GetFindingHistoryResponse{Events: eventDTOs})
+}
+
+// Handler for the "suppressions" endpoint: the suppression rules and the baseline
+func (h *ProjectHandlers) GetSuppressions(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	rules, baseline, err := h.FindingUsecase.GetSuppressions(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := dto.GetSuppressionsResponse{Rules: make([]dto.SuppressionRuleDTO, len(rules))}
+	for i, rule := range rules {
+		resp.Rules[i] = toSuppressionRuleDTO(rule)
+	}
+	if baseline != nil {
+		baselineDTO := toBaselineDTO(*baseline)
+		resp.Baseline = &baselineDTO
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Handler for the "create suppression rule" endpoint
+func (h *ProjectHandlers) CreateSuppressionRule(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	var req dto.CreateSuppressionRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+		return
+	}
+	rule := model.SuppressionRule{
+		RuleName:       req.RuleName,
+		PathGlob:       req.PathGlob,
+		MessagePattern: req.MessagePattern,
+		Reason:         req.Reason,
+		Author:         req.Author,
+		ExpiresAt:      req.ExpiresAt,
+	}
+	if err := findings.ValidateRule(rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.FindingUsecase.CreateSuppressionRule(uint(projectID), rule)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, toSuppressionRuleDTO(*created))
+}
+
+// Handler for the "delete suppression rule" endpoint
+func (h *ProjectHandlers) DeleteSuppressionRule(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+	ruleID, err := strconv.ParseUint(c.Param("suppression_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suppression_id"})
+		return
+	}
+
+	if err := h.FindingUsecase.DeleteSuppressionRule(uint(projectID), uint(ruleID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Suppression rule deleted"})
+}
+
+// Handler for the "create baseline" endpoint: accepts the project's current findings
+func (h *ProjectHandlers) CreateBaseline(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	var req dto.CreateBaselineRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+			return
+		}
+	}
+
+	baseline, err := h.FindingUsecase.CreateBaseline(uint(projectID), req.Author, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, toBaselineDTO(*baseline))
+}
+
+// Handler for the "delete baseline" endpoint
+func (h *ProjectHandlers) DeleteBaseline(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("project_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	if err := h.FindingUsecase.DeleteBaseline(uint(projectID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Baseline deleted"})
+}
+
+func toSuppressionRuleDTO(rule model.SuppressionRule) dto.SuppressionRuleDTO {
+	return dto.SuppressionRuleDTO{
+		ID:             rule.ID,
+		ProjectID:      rule.ProjectID,
+		RuleName:       rule.RuleName,
+		PathGlob:       rule.PathGlob,
+		MessagePattern: rule.MessagePattern,
+		Reason:         rule.Reason,
+		Author:         rule.Author,
+		ExpiresAt:      rule.ExpiresAt,
+		Expired:        rule.Expired(time.Now()),
+		CreatedAt:      rule.CreatedAt,
+	}
+}
+
+func toBaselineDTO(baseline model.Baseline) dto.BaselineDTO {
+	return dto.BaselineDTO{
+		ID:           baseline.ID,
+		ProjectID:    baseline.ProjectID,
+		Author:       baseline.Author,
+		Reason:       baseline.Reason,
+		Fingerprints: len(baseline.Fingerprints),
+		CreatedAt:    baseline.CreatedAt,
+	}
+}
+
+func (h *ProjectHandlers) GetRuns(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	runs, err := h.ProjectAnalysisUsecase.GetRuns(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	runDTOs := make([]dto.AnalysisRunDTO, len(runs))
+	for i, run := range runs {
+		runDTOs[i] = toAnalysisRunDTO(run)
+	}
+
+	c.JSON(http.StatusOK, dto.GetRunsResponse{Runs: runDTOs})
+}
+
+func toAnalysisRunDTO(run model.AnalysisRun) dto.AnalysisRunDTO {
+	stats := make([]dto.AnalysisRunPromptStatDTO, len(run.PromptStats))
+	for i, stat := range run.PromptStats {
+		stats[i] = dto.AnalysisRunPromptStatDTO{
+			PromptName:        stat.PromptName,
+			Findings:          stat.Findings,
+			Hallucinated:      stat.Hallucinated,
+			CriticRejected:    stat.CriticRejected,
+			HallucinationRate: stat.HallucinationRate(),
+		}
+	}
+	return dto.AnalysisRunDTO{
+		ID:            run.ID,
+		ProjectID:     run.ProjectID,
+		ProjectFileID: run.ProjectFileID,
+		Scope:         run.Scope,
+		Status:        run.Status,
+		Error:         run.Error,
+		Language:      run.Language,
+		StartedAt:     run.CreatedAt,
+		FinishedAt:    run.FinishedAt,
+		PromptStats:   stats,
+		Quality:       toQualityDTO(run),
+	}
+}
+
+// toQualityDTO returns the quality score of the run, or nil when it was not scored
+func toQualityDTO(run model.AnalysisRun) *dto.QualityDTO {
+	if run.Score == nil {
+		return nil
+	}
+	categories := make([]dto.CategoryScoreDTO, len(run.CategoryScores))
+	for i, category := range run.CategoryScores {
+		categories[i] = dto.CategoryScoreDTO{
+			Category:     category.Category,
+			Score:        category.Score,
+			Penalty:      category.Penalty,
+			Findings:     category.Findings,
+			FailedChecks: category.FailedChecks,
+		}
+	}
+	return &dto.QualityDTO{
+		RunID:      run.ID,
+		Score:      *run.Score,
+		Grade:      run.Grade,
+		CodeLines:  run.CodeLines,
+		Categories: categories,
+	}
+}
+
+// GetRunDiff compares the findings and verdicts of two runs; without from and to it
+// compares the latest run with the one before it
+func (h *ProjectHandlers) GetRunDiff(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+	fromRunID, toRunID, ok := parseRunPair(c, "from", "to")
+	if !ok {
+		return
+	}
+
+	diff, err := h.ProjectAnalysisUsecase.DiffRuns(uint(projectID), fromRunID, toRunID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, toRunDiffDTO(diff))
+}
+
+// parseRunPair reads optional run IDs from the query; it answers the request itself and
+// returns false when one of them is invalid
+func parseRunPair(c *gin.Context, fromParam, toParam string) (uint, uint, bool) {
+	var ids [2]uint
+	for i, param := range []string{fromParam, toParam} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+			return 0, 0, false
+		}
+		ids[i] = uint(id)
+	}
+	return ids[0], ids[1], true
+}
+
+func toRunFindingDTOs(findings []model.AnalysisRunFinding) []dto.RunFindingDTO {
+	findingDTOs := make([]dto.RunFindingDTO, len(findings))
+	for i, finding := range findings {
+		findingDTOs[i] = dto.RunFindingDTO{
+			FindingID:   finding.FindingID,
+			Fingerprint: finding.Fingerprint,
+			Source:      finding.Source,
+			RuleName:    finding.RuleName,
+			Severity:    finding.Severity,
+			Message:     finding.Message,
+			Path:        finding.Path,
+			Line:        finding.Line,
+		}
+	}
+	return findingDTOs
+}
+
+func toRunDiffDTO(diff *usecase.RunDiff) dto.RunDiffResponse {
+	flips := make([]dto.ComplianceFlipDTO, len(diff.ComplianceFlips))
+	for i, flip := range diff.ComplianceFlips {
+		flips[i] = dto.ComplianceFlipDTO{
+			PromptName: flip.PromptName,
+			Path:       flip.Path,
+			From:       flip.From,
+			To:         flip.To,
+		}
+	}
+
+	return dto.RunDiffResponse{
+		FromRun:         toAnalysisRunDTO(diff.From),
+		ToRun:           toAnalysisRunDTO(diff.To),
+		New:             toRunFindingDTOs(diff.New),
+		Resolved:        toRunFindingDTOs(diff.Resolved),
+		Persisting:      toRunFindingDTOs(diff.Persisting),
+		ComplianceFlips: flips,
 	}
-	c.JSON(http.StatusCreated, resp)
 }
 
-func (h *ProjectHandlers) AnalyzeProject(c *gin.Context) {
-	var req dto.AnalyzeProjectRequest
+// GetGate checks the latest run of the project against quality gate policies; policy
+// takes one or more comma-separated policy names. A failed gate is not an HTTP error:
+// the passed field tells the outcome. A project without a completed run is a conflict,
+// not a server error.
+func (h *ProjectHandlers) GetGate(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
 
-	// Bind JSON input to DTO
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
+	var names []string
+	for _, name := range strings.Split(c.DefaultQuery("policy", quality.DefaultPolicy), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid policy"})
 		return
 	}
 
-	// Call the use case with the project ID
-	if err := h.ProjectAnalysisUsecase.AnalyzeProject(req.ProjectID); err != nil {
+	result, err := h.ProjectAnalysisUsecase.EvaluateGate(uint(projectID), names)
+	if errors.Is(err, usecase.ErrUnknownGatePolicy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, usecase.ErrNoCompletedRuns) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Return response DTO
-	resp := dto.AnalyzeProjectResponse{
-		Message: "Project analysis completed",
+	violationDTOs := make([]dto.GateViolationDTO, len(result.Violations))
+	for i, violation := range result.Violations {
+		violationDTOs[i] = dto.GateViolationDTO{
+			Policy:    violation.Policy,
+			Condition: violation.Condition,
+			Expected:  violation.Expected,
+			Actual:    violation.Actual,
+			Message:   violation.Message,
+		}
+		if len(violation.Findings) > 0 {
+			violationDTOs[i].Findings = toRunFindingDTOs(violation.Findings)
+		}
+	}
+	resp := dto.GateResponse{
+		Passed:     result.Passed,
+		Policies:   result.Policies,
+		RunID:      result.Run.ID,
+		Score:      result.Run.Score,
+		Grade:      result.Run.Grade,
+		Violations: violationDTOs,
+	}
+	if result.PreviousRun != nil {
+		resp.PreviousRunID = &result.PreviousRun.ID
 	}
 	c.JSON(http.StatusOK, resp)
 }
 
-func (h *ProjectHandlers) AnalyzeFile(c *gin.Context) {
-	var req dto.AnalyzeFileRequest
+// Handler for the "review patch" endpoint: a unified diff as the diff field or the patch
+// file, or base and head archives of a merge request
+func (h *ProjectHandlers) ReviewPatch(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
 
-	// Bind JSON input to DTO
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var req dto.ReviewPatchRequest
+	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input data"})
 		return
 	}
+	if patchFile, err := c.FormFile("patch"); err == nil {
+		req.Patch = patchFile
+	}
+	if base, err := c.FormFile("base"); err == nil {
+		req.Base = base
+	}
+	if head, err := c.FormFile("head"); err == nil {
+		req.Head = head
+	}
+	if (req.Base == nil) != (req.Head == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Both base and head archives are required"})
+		return
+	}
+	if req.Base == nil && req.Patch == nil && req.Diff == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A diff or base and head archives are required"})
+		return
+	}
 
-	// Call the use case with the file ID
-	if err := h.ProjectAnalysisUsecase.AnalyzeFile(req.FileID); err != nil {
+	review, err := h.ProjectAnalysisUsecase.ReviewPatch(uint(projectID), req)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Return response DTO
-	resp := dto.AnalyzeFileResponse{
-		Message: "File analysis completed",
-	}
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, toPatchReviewDTO(*review))
 }
 
-// Handler for the "all projects" endpoint
-func (h *ProjectHandlers) GetAllProjects(c *gin.Context) {
-	projects, err := h.ProjectUsecase.GetAllProjects()
+// Handler for the "patch reviews" endpoint
+func (h *ProjectHandlers) GetPatchReviews(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	reviews, err := h.ProjectAnalysisUsecase.GetPatchReviews(uint(projectID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	projectDTOs := make([]dto.ProjectDTO, len(projects))
-	for i, project := range projects {
-		projectDTOs[i] = dto.ProjectDTO{
-			ID:                    project.ID,
-			ProgrammingLanguageID: project.ProgrammingLanguageID,
-			Name:                  project.Name,
-			Path:                  project.Path,
-			Tree:                  project.Tree,
-			WasAnalyzed:           project.WasAnalyzed,
-		}
+	reviewDTOs := make([]dto.PatchReviewDTO, len(reviews))
+	for i, review := range reviews {
+		reviewDTOs[i] = toPatchReviewDTO(review)
 	}
+	c.JSON(http.StatusOK, dto.GetPatchReviewsResponse{Reviews: reviewDTOs})
+}
 
-	resp := dto.GetAllProjectsResponse{
-		Projects: projectDTOs,
+func toPatchReviewDTO(review model.PatchReview) dto.PatchReviewDTO {
+	files := make([]dto.PatchReviewFileDTO, len(review.Files))
+	for i, file := range review.Files {
+		files[i] = dto.PatchReviewFileDTO{
+			Path:       file.Path,
+			OldPath:    file.OldPath,
+			Change:     file.Change,
+			AddedLines: file.AddedLines,
+			Reviewed:   file.Reviewed,
+		}
+	}
+	findingDTOs := make([]dto.PatchFindingDTO, len(review.Findings))
+	for i, finding := range review.Findings {
+		findingDTOs[i] = dto.PatchFindingDTO{
+			ID:        finding.ID,
+			Path:      finding.Path,
+			Line:      finding.Line,
+			RuleName:  finding.RuleName,
+			Severity:  finding.Severity,
+			Message:   finding.Message,
+			GPTCallID: finding.GPTCallID,
+		}
+	}
+	return dto.PatchReviewDTO{
+		ID:         review.ID,
+		ProjectID:  review.ProjectID,
+		Source:     review.Source,
+		Status:     review.Status,
+		Error:      review.Error,
+		Language:   review.Language,
+		Diff:       review.Diff,
+		CreatedAt:  review.CreatedAt,
+		FinishedAt: review.FinishedAt,
+		Files:      files,
+		Findings:   findingDTOs,
 	}
-	c.JSON(http.StatusOK, resp)
 }
 
-// Handler for the "project overview" endpoint
-func (h *ProjectHandlers) GetProjectOverview(c *gin.Context) {
+func (h *ProjectHandlers) GetAgentToolCalls(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
 	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
 	if err != nil {
@@ -147,47 +1331,29 @@ func (h *ProjectHandlers) GetProjectOverview(c *gin.Context) {
 		return
 	}
 
-	project, files, analysisResults, err := h.ProjectUsecase.GetProjectOverview(uint(projectID))
+	calls, err := h.ProjectAnalysisUsecase.GetAgentToolCalls(uint(projectID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	projectDTO := dto.ProjectDTO{
-		ID:                    project.ID,
-		ProgrammingLanguageID: project.ProgrammingLanguageID,
-		Name:                  project.Name,
-		Path:                  project.Path,
-		Tree:                  project.Tree,
-		WasAnalyzed:           project.WasAnalyzed,
-	}
-
-	fileDTOs := make([]dto.ProjectFileDTO, len(files))
-	for i, file := range files {
-		fileDTOs[i] = dto.ProjectFileDTO{
-			ID:          file.ID,
-			Name:        file.Name,
-			WasAnalyzed: file.WasAnalyzed,
-		}
-	}
-
-	analysisResultDTOs := make([]dto.ProjectAnalysisResultDTO, len(analysisResults))
-	for i, result := range analysisResults {
-		analysisResultDTOs[i] = dto.ProjectAnalysisResultDTO{
-			ID:              result.ID,
-			PromptName:      result.PromptName,
-			Compliance:      result.Compliance,
-			Issues:          result.Issues,
-			Recommendations: result.Recommendations,
+	callDTOs := make([]dto.AgentToolCallDTO, len(calls))
+	for i, call := range calls {
+		callDTOs[i] = dto.AgentToolCallDTO{
+			ID:            call.ID,
+			AnalysisRunID: call.AnalysisRunID,
+			PromptName:    call.PromptName,
+			GPTCallID:     call.GPTCallID,
+			Step:          call.Step,
+			ToolName:      call.ToolName,
+			Arguments:     call.Arguments,
+			Result:        call.Result,
+			Error:         call.Error,
+			CreatedAt:     call.CreatedAt,
 		}
 	}
 
-	resp := dto.GetProjectOverviewResponse{
-		Project:                projectDTO,
-		Files:                  fileDTOs,
-		ProjectAnalysisResults: analysisResultDTOs,
-	}
-	c.JSON(http.StatusOK, resp)
+	c.JSON(http.StatusOK, dto.GetAgentToolCallsResponse{ToolCalls: callDTOs})
 }
 
 func (h *ProjectHandlers) GetFileAnalysisResults(c *gin.Context) {
@@ -246,7 +1412,85 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 		return
 	}
 
+	coverage, err := h.ProjectUsecase.GetProjectCoverage(uint(projectID))
+	if err != nil {
+		fmt.Printf("Error getting project coverage: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	coverageByFile := make(map[uint]model.FileCoverage, len(coverage))
+	for _, fileCoverage := range coverage {
+		coverageByFile[fileCoverage.ProjectFileID] = fileCoverage
+	}
+
+	// Suppressed findings are left out of the report, but still tell which files have their
+	// review issues stored as findings
+	findings, err := h.FindingUsecase.GetFindings(uint(projectID), usecase.FindingFilter{IncludeSuppressed: true})
+	if err != nil {
+		fmt.Printf("Error getting project findings: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	findingsByFile := make(map[uint][]model.Finding)
+	reviewFindings := make(map[uint]bool) // Files whose review issues are stored as findings
+	for _, finding := range findings {
+		if finding.ProjectFileID != nil {
+			if finding.Source == model.FindingSourceLLM {
+				reviewFindings[*finding.ProjectFileID] = true
+			}
+			if !finding.Suppressed {
+				findingsByFile[*finding.ProjectFileID] = append(findingsByFile[*finding.ProjectFileID], finding)
+			}
+		}
+	}
+
+	scores, err := h.ProjectAnalysisUsecase.GetLatestScores([]uint{uint(projectID)})
+	if err != nil {
+		fmt.Printf("Error getting project quality score: %v\n", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Changes between two runs are included on request; diff=true compares the latest two
+	var diff *usecase.RunDiff
+	if c.Query("diff") == "true" || c.Query("diff_from") != "" || c.Query("diff_to") != "" {
+		fromRunID, toRunID, ok := parseRunPair(c, "diff_from", "diff_to")
+		if !ok {
+			return
+		}
+		diff, err = h.ProjectAnalysisUsecase.DiffRuns(uint(projectID), fromRunID, toRunID)
+		if err != nil {
+			fmt.Printf("Error comparing analysis runs: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	fmt.Printf("Retrieved project: %+v\n", project)
+	language := h.Catalog.Resolve(project.Language)
+
+	// Substitute stored translations when another language is requested
+	if requested := c.Query("lang"); requested != "" {
+		if !h.Catalog.IsSupported(requested) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+			return
+		}
+		language = h.Catalog.Resolve(requested)
+		// Findings are stored in the analysis language only, so translated issues are listed per check
+		reviewFindings = map[uint]bool{}
+		analysisResults, err = h.TranslationUsecase.LocalizeProjectResults(analysisResults, language)
+		if err != nil {
+			fmt.Printf("Error localizing project analysis results: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		files, err = h.TranslationUsecase.LocalizeFileResults(files, language)
+		if err != nil {
+			fmt.Printf("Error localizing file analysis results: %v\n", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
 
 	// Initialize PDF
 	pdf := gofpdf.New("P", "mm", "A4", "")
@@ -290,7 +1534,7 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 	fmt.Println("Font set successfully.")
 
 	// Add Project Title
-	title := fmt.Sprintf("Project Report: %s", project.Name)
+	title := h.Catalog.T(language, "pdf.title", project.Name)
 	pdf.Cell(40, 10, title)
 	pdf.Ln(20)
 	if pdf.Err() {
@@ -312,10 +1556,16 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 	fmt.Println("Font size set for content.")
 
 	// Add Project Details
-	pdf.Cell(40, 10, fmt.Sprintf("Project ID: %d", project.ID))
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.project_id", project.ID))
 	pdf.Ln(10)
-	pdf.Cell(40, 10, fmt.Sprintf("Programming Language ID: %d", project.ProgrammingLanguageID))
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.programming_language_id", project.ProgrammingLanguageID))
 	pdf.Ln(10)
+	if len(coverage) > 0 {
+		total := model.TotalCoverage(coverage)
+		pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.coverage_total",
+			total.LineRate(), total.LinesCovered, total.LinesValid,
+			total.BranchRate(), total.BranchesCovered, total.BranchesValid), "", "", false)
+	}
 	// Add other project details as needed
 	if pdf.Err() {
 		errMsg := fmt.Sprintf("Error after adding project details: %v", pdf.Error())
@@ -325,20 +1575,49 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 	}
 	fmt.Println("Added project details to PDF.")
 
+	if run, ok := scores[uint(projectID)]; ok {
+		h.writeQualityChart(pdf, fontName, language, run)
+		if pdf.Err() {
+			errMsg := fmt.Sprintf("Error after adding quality chart: %v", pdf.Error())
+			fmt.Println(errMsg)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+			return
+		}
+		fmt.Println("Added quality chart to PDF.")
+	}
+
+	if diff != nil {
+		h.writeRunDiff(pdf, fontName, language, diff)
+		if pdf.Err() {
+			errMsg := fmt.Sprintf("Error after adding run diff: %v", pdf.Error())
+			fmt.Println(errMsg)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": errMsg})
+			return
+		}
+		fmt.Println("Added run diff to PDF.")
+	}
+
 	// Add Project Analysis Results
 	pdf.SetFont(fontName, "B", 14)
-	pdf.Cell(40, 10, "Project Analysis Results")
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.project_results"))
 	pdf.Ln(10)
 	pdf.SetFont(fontName, "", 12)
 	for _, result := range analysisResults {
 		pdf.SetFont(fontName, "B", 12)
-		pdf.Cell(40, 10, result.PromptName)
+		pdf.Cell(40, 10, h.Catalog.CheckTitle(language, result.PromptName))
 		pdf.Ln(10)
 
 		pdf.SetFont(fontName, "", 12)
-		pdf.MultiCell(0, 10, fmt.Sprintf("Compliance: %s", result.Compliance), "", "", false)
-		pdf.MultiCell(0, 10, fmt.Sprintf("Issues: %s", result.Issues), "", "", false)
-		pdf.MultiCell(0, 10, fmt.Sprintf("Recommendations: %s", result.Recommendations), "", "", false)
+		switch result.Status {
+		case model.AnalysisStatusNotApplicable:
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.not_applicable", result.StatusReason), "", "", false)
+		case model.AnalysisStatusError:
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.check_error", result.StatusReason), "", "", false)
+		default:
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.compliance", h.Catalog.Compliance(language, result.Compliance)), "", "", false)
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.issues", result.Issues), "", "", false)
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.recommendations", result.Recommendations), "", "", false)
+		}
 		pdf.Ln(10)
 
 		if pdf.Err() {
@@ -352,25 +1631,31 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 
 	// Add Files and their Analysis Results
 	pdf.SetFont(fontName, "B", 14)
-	pdf.Cell(40, 10, "File Analysis Results")
+	pdf.Cell(40, 10, h.Catalog.T(language, "pdf.file_results"))
 	pdf.Ln(10)
 	pdf.SetFont(fontName, "", 12)
 	for _, file := range files {
 		// Add File Name
 		pdf.SetFont(fontName, "B", 12)
-		pdf.Cell(40, 10, fmt.Sprintf("File: %s", file.Name))
+		pdf.Cell(40, 10, h.Catalog.T(language, "pdf.file", file.Name))
 		pdf.Ln(10)
 		pdf.SetFont(fontName, "", 12)
-
+		if fileCoverage, ok := coverageByFile[file.ID]; ok {
+			pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.coverage_file",
+				fileCoverage.LineRate(), fileCoverage.BranchRate()), "", "", false)
+		}
 		if len(file.FileAnalysisResults) > 0 {
 			for _, analysis := range file.FileAnalysisResults {
 				pdf.SetFont(fontName, "B", 12)
-				pdf.Cell(40, 10, fmt.Sprintf("Analysis: %s", analysis.PromptName))
+				pdf.Cell(40, 10, h.Catalog.T(language, "pdf.analysis", h.Catalog.CheckTitle(language, analysis.PromptName)))
 				pdf.Ln(10)
 				pdf.SetFont(fontName, "", 12)
-				pdf.MultiCell(0, 10, fmt.Sprintf("Compliance: %s", analysis.Compliance), "", "", false)
-				pdf.MultiCell(0, 10, fmt.Sprintf("Issues: %s", analysis.Issues), "", "", false)
-				pdf.MultiCell(0, 10, fmt.Sprintf("Recommendations: %s", analysis.Recommendations), "", "", false)
+				pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.compliance", h.Catalog.Compliance(language, analysis.Compliance)), "", "", false)
+				// Deduplicated issues are listed once below the checks
+				if !reviewFindings[file.ID] {
+					pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.issues", analysis.Issues), "", "", false)
+				}
+				pdf.MultiCell(0, 10, h.Catalog.T(language, "pdf.recommendations", analysis.Recommendations), "", "", false)
 				pdf.Ln(10)
 
 				if pdf.Err() {
@@ -382,9 +1667,11 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 				fmt.Printf("Added analysis for file '%s' to PDF.\n", file.Name)
 			}
 		} else {
-			pdf.Cell(40, 10, "No analysis results for this file.")
+			pdf.Cell(40, 10, h.Catalog.T(language, "pdf.no_file_results"))
 			pdf.Ln(10)
 		}
+
+		h.writeFindings(pdf, language, findingsByFile[file.ID], reviewFindings[file.ID])
 	}
 
 	// Output PDF
@@ -403,3 +1690,128 @@ func (h *ProjectHandlers) GenerateProjectPDF(c *gin.Context) {
 	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
 	fmt.Println("PDF sent to client.")
 }
+
+// exportChecks describes the review checks for exports, project-level checks first, with
+// their guidelines as help
+func (h *ProjectHandlers) exportChecks(language string) []export.Rule {
+	var checks []export.Rule
+	addChecks := func(scope string, names []string) {
+		for _, name := range names {
+			check := export.Rule{ID: name, Title: h.Catalog.CheckTitle(language, name), Scope: scope}
+			if prompt, ok := h.ProjectAnalysisUsecase.Prompts.ByName(name); ok {
+				check.Guideline = prompt.BaseTaskDesc
+			}
+			checks = append(checks, check)
+		}
+	}
+	addChecks("project", prompts.ProjectPromptNames)
+	addChecks("file", prompts.FilePromptNames)
+	return checks
+}
+
+// Handler for the SARIF export: the canonical findings of the project, suppressed ones
+// included with their suppression, and the review checks as rules
+func (h *ProjectHandlers) ExportSARIF(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	project, _, _, err := h.ProjectUsecase.GetProjectOverview(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	language := h.Catalog.Resolve(project.Language)
+	if requested := c.Query("lang"); requested != "" {
+		if !h.Catalog.IsSupported(requested) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language"})
+			return
+		}
+		language = h.Catalog.Resolve(requested)
+	}
+
+	findings, err := h.FindingUsecase.GetFindings(uint(projectID), usecase.FindingFilter{IncludeSuppressed: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	sarifLog := export.SARIF(project, language, h.exportChecks(language), findings)
+	data, err := json.MarshalIndent(sarifLog, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode SARIF: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.sarif\"", project.Name))
+	c.Data(http.StatusOK, "application/sarif+json", data)
+}
+
+// Handler for the Code Climate export, the code quality report format of GitLab
+func (h *ProjectHandlers) ExportCodeClimate(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	project, _, analysisResults, err := h.ProjectUsecase.GetProjectOverview(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	files, err := h.ProjectFileUsecase.GetProjectFilesWithAnalysis(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	// Suppressed findings still tell which checks have their issues stored as findings
+	findings, err := h.FindingUsecase.GetFindings(uint(projectID), usecase.FindingFilter{IncludeSuppressed: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	issues := export.CodeClimate(findings, analysisResults, files)
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode code quality report: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-codequality.json\"", project.Name))
+	c.Data(http.StatusOK, "application/json", data)
+}
+
+// Handler for the JUnit XML export: a test case per check per file, failed when the
+// check is not met
+func (h *ProjectHandlers) ExportJUnit(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := strconv.ParseUint(projectIDStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project_id"})
+		return
+	}
+
+	project, _, analysisResults, err := h.ProjectUsecase.GetProjectOverview(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	files, err := h.ProjectFileUsecase.GetProjectFilesWithAnalysis(uint(projectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	report := export.JUnit(project, analysisResults, files)
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to encode JUnit report: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-junit.xml\"", project.Name))
+	c.Data(http.StatusOK, "application/xml", append([]byte(xml.Header), data...))
+}
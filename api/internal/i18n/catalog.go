@@ -0,0 +1,85 @@
+// internal/i18n/catalog.go
+
+package i18n
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Catalog resolves report languages and translates system-generated messages
+type Catalog struct {
+	supported       map[string]bool
+	defaultLanguage string
+}
+
+// NewCatalog creates a catalog limited to the supported languages that have messages
+func NewCatalog(supportedLanguages []string, defaultLanguage string) *Catalog {
+	supported := make(map[string]bool)
+	for _, language := range supportedLanguages {
+		language = normalize(language)
+		if _, ok := messages[language]; ok {
+			supported[language] = true
+		} else {
+			fmt.Printf("Language %q has no message catalog, ignoring\n", language)
+		}
+	}
+
+	defaultLanguage = normalize(defaultLanguage)
+	if !supported[defaultLanguage] {
+		fmt.Printf("Default language %q is not supported, falling back to %q\n", defaultLanguage, fallbackLanguage)
+		defaultLanguage = fallbackLanguage
+		supported[defaultLanguage] = true
+	}
+
+	return &Catalog{
+		supported:       supported,
+		defaultLanguage: defaultLanguage,
+	}
+}
+
+func normalize(language string) string {
+	return strings.ToLower(strings.TrimSpace(language))
+}
+
+// IsSupported reports whether the language can be used for reports
+func (c *Catalog) IsSupported(language string) bool {
+	return c.supported[normalize(language)]
+}
+
+// DefaultLanguage returns the language used when neither request nor project specify one
+func (c *Catalog) DefaultLanguage() string {
+	return c.defaultLanguage
+}
+
+// Resolve picks the first supported language from the candidates (e.g. request, project),
+// falling back to the default language
+func (c *Catalog) Resolve(candidates ...string) string {
+	for _, language := range candidates {
+		if c.IsSupported(language) {
+			return normalize(language)
+		}
+	}
+	return c.defaultLanguage
+}
+
+// LLMLanguage returns the language name passed to prompts, e.g. "Russian (русский)"
+func (c *Catalog) LLMLanguage(language string) string {
+	return llmLanguages[c.Resolve(language)]
+}
+
+// T returns the message for key in the given language, formatted with args.
+// Missing translations fall back to the default language and then to the key itself.
+func (c *Catalog) T(language, key string, args ...interface{}) string {
+	format, ok := messages[c.Resolve(language)][key]
+	if !ok {
+		format, ok = messages[c.defaultLanguage][key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
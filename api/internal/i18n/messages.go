@@ -0,0 +1,216 @@
+// internal/i18n/messages.go
+
+package i18n
+
+import "strings"
+
+// fallbackLanguage is used when the configured default language has no catalog
+const fallbackLanguage = "ru"
+
+// llmLanguages maps a locale to the language name used in prompt instructions
+var llmLanguages = map[string]string{
+	"ru": "Russian (русский)",
+	"en": "English",
+}
+
+// messages holds every system-generated message and report label, per locale
+var messages = map[string]map[string]string{
+	"ru": {
+		// Lists
+		"list.and": " и ",
+
+		// Fallback analysis results
+		"analysis.file_missing":     "%s отсутствует в корне проекта",
+		"analysis.files_missing":    "%s отсутствуют в корне проекта",
+		"analysis.file_not_found":   "%s не найден",
+		"analysis.no_relevant_code": "Не найден код по темам: %s",
+		"analysis.check_disabled":   "Проверка отключена в %s",
+
+		// Discovery topics
+		"topic.transactions": "управление транзакциями",
+		"topic.async":        "асинхронный код",
+		"topic.datetime":     "работа с датой и временем",
+
+		// Computed findings
+		"finding.tests_missing":      "Для модуля %s нет тестов",
+		"finding.tests_not_mirrored": "Тесты модуля %s находятся в %s и не повторяют структуру исходного кода",
+		"finding.config_invalid":     "Ошибка в файле конфигурации %s: %s",
+
+		// API messages
+		"message.project_uploaded":   "Проект успешно загружен",
+		"message.project_analyzed":   "Анализ проекта завершён",
+		"message.file_analyzed":      "Анализ файла завершён",
+		"message.settings_updated":   "Настройки проекта обновлены",
+		"message.project_translated": "Результаты анализа переведены",
+		"message.linter_imported":    "Отчёт линтера импортирован",
+
+		// Compliance values
+		"compliance.true":  "Да",
+		"compliance.false": "Нет",
+
+		// Check titles
+		"check.ProjectStructure":        "Структура проекта",
+		"check.KeyFiles":                "Ключевые файлы",
+		"check.ApplicationArchitecture": "Архитектура приложения",
+		"check.DependencyManagement":    "Управление зависимостями",
+		"check.ProjectSettings":         "Настройки проекта",
+		"check.TestingStrategy":         "Стратегия тестирования",
+		"check.AdditionalTechnical":     "Дополнительные технические требования",
+		"check.DateTimeHandling":        "Работа с датой и временем",
+		"check.ApplicationLayerCode":    "Код слоя приложения",
+		"check.AdaptersLayerCode":       "Код слоя адаптеров",
+		"check.CodingStandards":         "Стандарты кодирования",
+		"check.ErrorHandlingAndLogging": "Обработка ошибок и логирование",
+		"check.AdditionalTechnicalFile": "Дополнительные технические требования (файл)",
+		"check.DateTimeHandlingFile":    "Работа с датой и временем (файл)",
+
+		// PDF report labels
+		"pdf.title":                   "Отчёт по проекту: %s",
+		"pdf.project_id":              "ID проекта: %d",
+		"pdf.programming_language_id": "ID языка программирования: %d",
+		"pdf.project_results":         "Результаты анализа проекта",
+		"pdf.file_results":            "Результаты анализа файлов",
+		"pdf.file":                    "Файл: %s",
+		"pdf.analysis":                "Анализ: %s",
+		"pdf.compliance":              "Соответствие: %s",
+		"pdf.issues":                  "Проблемы: %s",
+		"pdf.recommendations":         "Рекомендации: %s",
+		"pdf.no_file_results":         "Нет результатов анализа для этого файла.",
+		"pdf.not_applicable":          "Не применимо: %s",
+		"pdf.check_error":             "Проверка не выполнена: %s",
+		"pdf.coverage_total":          "Покрытие тестами: строки %.1f%% (%d/%d), ветви %.1f%% (%d/%d)",
+		"pdf.coverage_file":           "Покрытие: строки %.1f%%, ветви %.1f%%",
+		"pdf.findings":                "Замечания:",
+		"pdf.finding":                 "[%s] %s: %s",
+		"pdf.finding_at_line":         "[%s] %s, строка %d: %s",
+		"pdf.diff_title":              "Изменения между запусками %d и %d",
+		"pdf.diff_summary":            "Новые: %d, исправленные: %d, оставшиеся: %d",
+		"pdf.diff_new":                "Новые замечания:",
+		"pdf.diff_resolved":           "Исправленные замечания:",
+		"pdf.diff_flips":              "Изменения соответствия:",
+		"pdf.diff_finding":            "[%s] %s, %s: %s",
+		"pdf.diff_flip":               "%s: %s → %s",
+		"pdf.diff_flip_file":          "%s (%s): %s → %s",
+		"pdf.quality_title":           "Оценка качества",
+		"pdf.quality_score":           "Оценка: %.1f из 100, класс %s (запуск %d, строк кода: %d)",
+		"quality.structure":           "Структура",
+		"quality.dependencies":        "Зависимости",
+		"quality.architecture":        "Архитектура",
+		"quality.testing":             "Тестирование",
+		"quality.coding_standards":    "Стандарты кода",
+		"quality.error_handling":      "Обработка ошибок",
+		"quality.datetime":            "Дата и время",
+	},
+	"en": {
+		// Lists
+		"list.and": " and ",
+
+		// Fallback analysis results
+		"analysis.file_missing":     "%s is missing at the root of the project directory",
+		"analysis.files_missing":    "%s are missing at the root of the project directory",
+		"analysis.file_not_found":   "%s not found",
+		"analysis.no_relevant_code": "No code found for: %s",
+		"analysis.check_disabled":   "Check disabled in %s",
+
+		// Discovery topics
+		"topic.transactions": "transaction handling",
+		"topic.async":        "asynchronous code",
+		"topic.datetime":     "date and time handling",
+
+		// Computed findings
+		"finding.tests_missing":      "Module %s has no tests",
+		"finding.tests_not_mirrored": "Tests of module %s are in %s, which does not mirror the source structure",
+		"finding.config_invalid":     "Invalid configuration file %s: %s",
+
+		// API messages
+		"message.project_uploaded":   "Project uploaded successfully",
+		"message.project_analyzed":   "Project analysis completed",
+		"message.file_analyzed":      "File analysis completed",
+		"message.settings_updated":   "Project settings updated",
+		"message.project_translated": "Analysis results translated",
+		"message.linter_imported":    "Linter report imported",
+
+		// Compliance values
+		"compliance.true":  "Yes",
+		"compliance.false": "No",
+
+		// Check titles
+		"check.ProjectStructure":        "Project structure",
+		"check.KeyFiles":                "Key files",
+		"check.ApplicationArchitecture": "Application architecture",
+		"check.DependencyManagement":    "Dependency management",
+		"check.ProjectSettings":         "Project settings",
+		"check.TestingStrategy":         "Testing strategy",
+		"check.AdditionalTechnical":     "Additional technical requirements",
+		"check.DateTimeHandling":        "Date and time handling",
+		"check.ApplicationLayerCode":    "Application layer code",
+		"check.AdaptersLayerCode":       "Adapters layer code",
+		"check.CodingStandards":         "Coding standards",
+		"check.ErrorHandlingAndLogging": "Error handling and logging",
+		"check.AdditionalTechnicalFile": "Additional technical requirements (file)",
+		"check.DateTimeHandlingFile":    "Date and time handling (file)",
+
+		// PDF report labels
+		"pdf.title":                   "Project Report: %s",
+		"pdf.project_id":              "Project ID: %d",
+		"pdf.programming_language_id": "Programming Language ID: %d",
+		"pdf.project_results":         "Project Analysis Results",
+		"pdf.file_results":            "File Analysis Results",
+		"pdf.file":                    "File: %s",
+		"pdf.analysis":                "Analysis: %s",
+		"pdf.compliance":              "Compliance: %s",
+		"pdf.issues":                  "Issues: %s",
+		"pdf.recommendations":         "Recommendations: %s",
+		"pdf.no_file_results":         "No analysis results for this file.",
+		"pdf.not_applicable":          "Not applicable: %s",
+		"pdf.check_error":             "Check could not be completed: %s",
+		"pdf.coverage_total":          "Test coverage: lines %.1f%% (%d/%d), branches %.1f%% (%d/%d)",
+		"pdf.coverage_file":           "Coverage: lines %.1f%%, branches %.1f%%",
+		"pdf.findings":                "Findings:",
+		"pdf.finding":                 "[%s] %s: %s",
+		"pdf.finding_at_line":         "[%s] %s, line %d: %s",
+		"pdf.diff_title":              "Changes between runs %d and %d",
+		"pdf.diff_summary":            "New: %d, resolved: %d, persisting: %d",
+		"pdf.diff_new":                "New findings:",
+		"pdf.diff_resolved":           "Resolved findings:",
+		"pdf.diff_flips":              "Compliance changes:",
+		"pdf.diff_finding":            "[%s] %s, %s: %s",
+		"pdf.diff_flip":               "%s: %s → %s",
+		"pdf.diff_flip_file":          "%s (%s): %s → %s",
+		"pdf.quality_title":           "Quality score",
+		"pdf.quality_score":           "Score: %.1f of 100, grade %s (run %d, lines of code: %d)",
+		"quality.structure":           "Structure",
+		"quality.dependencies":        "Dependencies",
+		"quality.architecture":        "Architecture",
+		"quality.testing":             "Testing",
+		"quality.coding_standards":    "Coding standards",
+		"quality.error_handling":      "Error handling",
+		"quality.datetime":            "Date and time",
+	},
+}
+
+// JoinList joins items as a natural-language list, e.g. "a, b and c"
+func (c *Catalog) JoinList(language string, items []string) string {
+	if len(items) <= 1 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + c.T(language, "list.and") + items[len(items)-1]
+}
+
+// CheckTitle returns the human-readable title of a check (prompt name)
+func (c *Catalog) CheckTitle(language, promptName string) string {
+	key := "check." + promptName
+	if title := c.T(language, key); title != key {
+		return title
+	}
+	return promptName
+}
+
+// Compliance returns the localized label of a stored compliance value ("true"/"false")
+func (c *Catalog) Compliance(language, compliance string) string {
+	key := "compliance." + compliance
+	if label := c.T(language, key); label != key {
+		return label
+	}
+	return compliance
+}
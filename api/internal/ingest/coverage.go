@@ -0,0 +1,205 @@
+// internal/ingest/coverage.go
+
+package ingest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// coverageReportNames are the file names of coverage reports detected in uploaded archives
+var coverageReportNames = map[string]bool{
+	"coverage.xml":           true,
+	"cobertura.xml":          true,
+	"cobertura-coverage.xml": true,
+	"coverage.json":          true,
+	".coverage":              true,
+}
+
+// IsCoverageReport reports whether a file name is a known coverage report
+func IsCoverageReport(name string) bool {
+	return coverageReportNames[path.Base(name)]
+}
+
+// FileCoverage is the coverage of a single source file as listed in a report
+type FileCoverage struct {
+	Path            string
+	LinesValid      int
+	LinesCovered    int
+	BranchesValid   int
+	BranchesCovered int
+	MissingLines    []int
+}
+
+// CoverageReport is a parsed coverage report
+type CoverageReport struct {
+	Format  string   // "cobertura" or "coverage.py"
+	Sources []string // Source roots the file paths are relative to
+	Files   []FileCoverage
+}
+
+// ParseCoverage parses a Cobertura XML or coverage.py JSON report, detected by content
+func ParseCoverage(content []byte) (*CoverageReport, error) {
+	trimmed := bytes.TrimSpace(content)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseCobertura(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return parseCoveragePy(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("SQLite format")):
+		return nil, fmt.Errorf("coverage data file is not a report; export it with `coverage xml` or `coverage json`")
+	}
+	return nil, fmt.Errorf("unsupported coverage report format")
+}
+
+type coberturaReport struct {
+	Sources  []string `xml:"sources>source"`
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number            int    `xml:"number,attr"`
+				Hits              int    `xml:"hits,attr"`
+				Branch            bool   `xml:"branch,attr"`
+				ConditionCoverage string `xml:"condition-coverage,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// conditionCoveragePattern extracts "(covered/total)" from e.g. "50% (1/2)"
+var conditionCoveragePattern = regexp.MustCompile(`\((\d+)/(\d+)\)`)
+
+func parseCobertura(content []byte) (*CoverageReport, error) {
+	var raw coberturaReport
+	if err := xml.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Cobertura report: %w", err)
+	}
+
+	// Several classes may share a file, so lines are merged per file
+	type lineState struct {
+		covered                bool
+		branches, branchesSeen int
+	}
+	byFile := make(map[string]map[int]*lineState)
+	for _, pkg := range raw.Packages {
+		for _, class := range pkg.Classes {
+			lines, ok := byFile[class.Filename]
+			if !ok {
+				lines = make(map[int]*lineState)
+				byFile[class.Filename] = lines
+			}
+			for _, line := range class.Lines {
+				state, ok := lines[line.Number]
+				if !ok {
+					state = &lineState{}
+					lines[line.Number] = state
+				}
+				state.covered = state.covered || line.Hits > 0
+				if !line.Branch {
+					continue
+				}
+				if match := conditionCoveragePattern.FindStringSubmatch(line.ConditionCoverage); match != nil {
+					covered, _ := strconv.Atoi(match[1])
+					total, _ := strconv.Atoi(match[2])
+					if total > state.branches {
+						state.branches = total
+					}
+					if covered > state.branchesSeen {
+						state.branchesSeen = covered
+					}
+				}
+			}
+		}
+	}
+
+	report := &CoverageReport{Format: "cobertura"}
+	for _, source := range raw.Sources {
+		if source = strings.TrimSpace(source); source != "" {
+			report.Sources = append(report.Sources, source)
+		}
+	}
+	for filename, lines := range byFile {
+		file := FileCoverage{Path: filename, LinesValid: len(lines)}
+		for number, state := range lines {
+			if state.covered {
+				file.LinesCovered++
+			} else {
+				file.MissingLines = append(file.MissingLines, number)
+			}
+			file.BranchesValid += state.branches
+			file.BranchesCovered += state.branchesSeen
+		}
+		sort.Ints(file.MissingLines)
+		report.Files = append(report.Files, file)
+	}
+	sortFiles(report.Files)
+	return report, nil
+}
+
+type coveragePyReport struct {
+	Files map[string]struct {
+		Summary struct {
+			CoveredLines    int `json:"covered_lines"`
+			NumStatements   int `json:"num_statements"`
+			NumBranches     int `json:"num_branches"`
+			CoveredBranches int `json:"covered_branches"`
+		} `json:"summary"`
+		MissingLines []int `json:"missing_lines"`
+	} `json:"files"`
+}
+
+func parseCoveragePy(content []byte) (*CoverageReport, error) {
+	var raw coveragePyReport
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse coverage.py report: %w", err)
+	}
+	if raw.Files == nil {
+		return nil, fmt.Errorf("coverage.py report has no files section")
+	}
+
+	report := &CoverageReport{Format: "coverage.py"}
+	for filename, file := range raw.Files {
+		missing := append([]int(nil), file.MissingLines...)
+		sort.Ints(missing)
+		report.Files = append(report.Files, FileCoverage{
+			Path:            filename,
+			LinesValid:      file.Summary.NumStatements,
+			LinesCovered:    file.Summary.CoveredLines,
+			BranchesValid:   file.Summary.NumBranches,
+			BranchesCovered: file.Summary.CoveredBranches,
+			MissingLines:    missing,
+		})
+	}
+	sortFiles(report.Files)
+	return report, nil
+}
+
+func sortFiles(files []FileCoverage) {
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+}
+
+// FormatLineRanges compacts line numbers into ranges, e.g. "3-5, 9"
+func FormatLineRanges(lines []int) string {
+	var parts []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(lines[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ", ")
+}
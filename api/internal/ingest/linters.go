@@ -0,0 +1,276 @@
+// internal/ingest/linters.go
+
+package ingest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// Supported linters
+const (
+	ToolRuff   = "ruff"
+	ToolPylint = "pylint"
+	ToolFlake8 = "flake8"
+	ToolMypy   = "mypy"
+)
+
+// linterReportNames are the file names of linter reports detected in uploaded archives
+var linterReportNames = map[string]string{
+	"ruff.json":          ToolRuff,
+	"ruff-report.json":   ToolRuff,
+	"pylint.json":        ToolPylint,
+	"pylint-report.json": ToolPylint,
+	"flake8.txt":         ToolFlake8,
+	"flake8-report.txt":  ToolFlake8,
+	"mypy.txt":           ToolMypy,
+	"mypy-report.txt":    ToolMypy,
+}
+
+// LinterReportTool returns the linter a report file name belongs to, or "" when the file
+// is not a known linter report
+func LinterReportTool(name string) string {
+	return linterReportNames[path.Base(name)]
+}
+
+// IsSupportedTool reports whether the tool's output can be imported
+func IsSupportedTool(tool string) bool {
+	switch tool {
+	case ToolRuff, ToolPylint, ToolFlake8, ToolMypy:
+		return true
+	}
+	return false
+}
+
+// LintEntry is a single normalized linter message
+type LintEntry struct {
+	Tool     string
+	Path     string
+	Line     int
+	Column   int
+	RuleID   string // "<tool>:<code>", e.g. "ruff:F401"
+	Severity string // One of the model severities
+	Message  string
+}
+
+// ParseLinterReport parses a linter report. An empty tool is detected from the content.
+func ParseLinterReport(tool string, content []byte) ([]LintEntry, error) {
+	content = bytes.TrimSpace(content)
+	if tool == "" {
+		tool = detectTool(content)
+	}
+
+	switch tool {
+	case ToolRuff:
+		return parseRuff(content)
+	case ToolPylint:
+		return parsePylint(content)
+	case ToolFlake8:
+		return parseFlake8(content)
+	case ToolMypy:
+		return parseMypy(content)
+	case "":
+		return nil, fmt.Errorf("could not detect the linter of the report")
+	}
+	return nil, fmt.Errorf("unsupported linter: %s", tool)
+}
+
+var (
+	flake8LinePattern = regexp.MustCompile(`^(.+?):(\d+):(\d+): ([A-Z]+\d+) (.*)$`)
+	mypyLinePattern   = regexp.MustCompile(`^(.+?):(\d+):(?:(\d+):)? (error|warning|note): (.*?)(?:\s+\[([\w-]+)\])?$`)
+)
+
+func detectTool(content []byte) string {
+	if bytes.HasPrefix(content, []byte("[")) {
+		switch {
+		case bytes.Contains(content, []byte(`"message-id"`)):
+			return ToolPylint
+		case bytes.Contains(content, []byte(`"location"`)):
+			return ToolRuff
+		}
+		return ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case mypyLinePattern.MatchString(line):
+			return ToolMypy
+		case flake8LinePattern.MatchString(line):
+			return ToolFlake8
+		}
+	}
+	return ""
+}
+
+type ruffMessage struct {
+	Code     *string `json:"code"`
+	Message  string  `json:"message"`
+	Filename string  `json:"filename"`
+	Location struct {
+		Row    int `json:"row"`
+		Column int `json:"column"`
+	} `json:"location"`
+}
+
+func parseRuff(content []byte) ([]LintEntry, error) {
+	var messages []ruffMessage
+	if err := json.Unmarshal(content, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse ruff report: %w", err)
+	}
+
+	entries := make([]LintEntry, 0, len(messages))
+	for _, message := range messages {
+		// Syntax errors have no code
+		code := "E999"
+		if message.Code != nil && *message.Code != "" {
+			code = *message.Code
+		}
+		entries = append(entries, LintEntry{
+			Tool:     ToolRuff,
+			Path:     message.Filename,
+			Line:     message.Location.Row,
+			Column:   message.Location.Column,
+			RuleID:   ToolRuff + ":" + code,
+			Severity: codeSeverity(code),
+			Message:  message.Message,
+		})
+	}
+	return entries, nil
+}
+
+type pylintMessage struct {
+	Type      string `json:"type"`
+	Path      string `json:"path"`
+	Line      int    `json:"line"`
+	Column    int    `json:"column"`
+	Symbol    string `json:"symbol"`
+	Message   string `json:"message"`
+	MessageID string `json:"message-id"`
+}
+
+func parsePylint(content []byte) ([]LintEntry, error) {
+	var messages []pylintMessage
+	if err := json.Unmarshal(content, &messages); err != nil {
+		return nil, fmt.Errorf("failed to parse pylint report: %w", err)
+	}
+
+	entries := make([]LintEntry, 0, len(messages))
+	for _, message := range messages {
+		text := message.Message
+		if message.Symbol != "" {
+			text = fmt.Sprintf("%s (%s)", text, message.Symbol)
+		}
+		entries = append(entries, LintEntry{
+			Tool:     ToolPylint,
+			Path:     message.Path,
+			Line:     message.Line,
+			Column:   message.Column,
+			RuleID:   ToolPylint + ":" + message.MessageID,
+			Severity: pylintSeverity(message.Type),
+			Message:  text,
+		})
+	}
+	return entries, nil
+}
+
+func parseFlake8(content []byte) ([]LintEntry, error) {
+	var entries []LintEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		match := flake8LinePattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		entries = append(entries, LintEntry{
+			Tool:     ToolFlake8,
+			Path:     match[1],
+			Line:     line,
+			Column:   column,
+			RuleID:   ToolFlake8 + ":" + match[4],
+			Severity: codeSeverity(match[4]),
+			Message:  match[5],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read flake8 report: %w", err)
+	}
+	return entries, nil
+}
+
+func parseMypy(content []byte) ([]LintEntry, error) {
+	var entries []LintEntry
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		match := mypyLinePattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		// Notes only explain the error before them
+		if match == nil || match[4] == "note" {
+			continue
+		}
+		line, _ := strconv.Atoi(match[2])
+		column, _ := strconv.Atoi(match[3])
+		code := match[6]
+		if code == "" {
+			code = match[4]
+		}
+		severity := model.SeverityMedium
+		if match[4] == "warning" {
+			severity = model.SeverityLow
+		}
+		entries = append(entries, LintEntry{
+			Tool:     ToolMypy,
+			Path:     match[1],
+			Line:     line,
+			Column:   column,
+			RuleID:   ToolMypy + ":" + code,
+			Severity: severity,
+			Message:  match[5],
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mypy report: %w", err)
+	}
+	return entries, nil
+}
+
+// codeSeverity maps flake8/ruff rule codes to a severity. Syntax errors and undefined
+// names (the codes flake8 recommends failing builds on) are high.
+func codeSeverity(code string) string {
+	for _, prefix := range []string{"E9", "F63", "F7", "F82"} {
+		if strings.HasPrefix(code, prefix) {
+			return model.SeverityHigh
+		}
+	}
+	switch {
+	case strings.HasPrefix(code, "S"): // flake8-bandit security checks
+		return model.SeverityHigh
+	case strings.HasPrefix(code, "F"), strings.HasPrefix(code, "B"), strings.HasPrefix(code, "C9"):
+		return model.SeverityMedium
+	case strings.HasPrefix(code, "D"):
+		return model.SeverityInfo
+	}
+	return model.SeverityLow
+}
+
+func pylintSeverity(messageType string) string {
+	switch messageType {
+	case "fatal", "error":
+		return model.SeverityHigh
+	case "warning":
+		return model.SeverityMedium
+	case "refactor", "convention":
+		return model.SeverityLow
+	}
+	return model.SeverityInfo
+}
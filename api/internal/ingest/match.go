@@ -0,0 +1,70 @@
+// internal/ingest/match.go
+
+package ingest
+
+import (
+	"path"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// PathMatcher maps paths reported by external tools to project files. Tools report paths
+// relative to wherever they ran, or absolute, so files are matched by their longest
+// common trailing path.
+type PathMatcher struct {
+	byName map[string][]model.ProjectFile
+}
+
+func NewPathMatcher(files []model.ProjectFile) *PathMatcher {
+	byName := make(map[string][]model.ProjectFile)
+	for _, file := range files {
+		name := path.Base(file.Path)
+		byName[name] = append(byName[name], file)
+	}
+	return &PathMatcher{byName: byName}
+}
+
+// Match returns the project file the reported path refers to, if any
+func (m *PathMatcher) Match(reportedPath string) (model.ProjectFile, bool) {
+	reported := splitPath(reportedPath)
+	if len(reported) == 0 {
+		return model.ProjectFile{}, false
+	}
+
+	var best model.ProjectFile
+	bestScore, ties := 0, 0
+	for _, file := range m.byName[reported[len(reported)-1]] {
+		score := commonSuffix(reported, splitPath(file.Path))
+		switch {
+		case score > bestScore:
+			best, bestScore, ties = file, score, 0
+		case score == bestScore:
+			ties++
+		}
+	}
+	// An ambiguous match (two files sharing the same trailing path) is not guessed
+	if bestScore == 0 || ties > 0 {
+		return model.ProjectFile{}, false
+	}
+	return best, true
+}
+
+func splitPath(p string) []string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	var parts []string
+	for _, part := range strings.Split(path.Clean("/"+p), "/") {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
+
+func commonSuffix(a, b []string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[len(a)-1-n] == b[len(b)-1-n] {
+		n++
+	}
+	return n
+}
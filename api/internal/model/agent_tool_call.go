@@ -0,0 +1,25 @@
+// internal/model/agent_tool_call.go
+
+package model
+
+import (
+	"time"
+)
+
+// AgentToolCall records a tool the review agent called, for audit
+type AgentToolCall struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	ProjectID     uint   `gorm:"not null;index" json:"projectId"`
+	AnalysisRunID *uint  `gorm:"index" json:"analysisRunId,omitempty"`
+	PromptName    string `json:"promptName"`             // Check the agent was running
+	GPTCallID     uint   `gorm:"index" json:"gptCallId"` // Model turn that requested the call
+	Step          int    `json:"step"`
+	ToolName      string `json:"toolName"`
+	Arguments     string `gorm:"type:text" json:"arguments"`
+	Result        string `gorm:"type:text" json:"result"`
+	Error         string `gorm:"type:text" json:"error,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
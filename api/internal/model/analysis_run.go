@@ -0,0 +1,109 @@
+// internal/model/analysis_run.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Scopes of an analysis run
+const (
+	AnalysisScopeProject = "project"
+	AnalysisScopeFile    = "file"
+)
+
+// Statuses of an analysis run
+const (
+	RunStatusRunning   = "running"
+	RunStatusCompleted = "completed"
+	RunStatusFailed    = "failed"
+)
+
+// AnalysisRun records a single analysis of a project or of one of its files
+type AnalysisRun struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID     uint       `gorm:"not null;index" json:"projectId"`
+	ProjectFileID *uint      `json:"projectFileId,omitempty"` // Set for file runs
+	Scope         string     `json:"scope"`
+	Status        string     `json:"status"`
+	Error         string     `gorm:"type:text" json:"error,omitempty"`
+	Language      string     `json:"language"`
+	FinishedAt    *time.Time `json:"finishedAt,omitempty"`
+
+	// Quality score of the project as seen by the run, see quality.Compute; unset until
+	// the run completed
+	Score     *float64 `json:"score,omitempty"`
+	Grade     string   `json:"grade,omitempty"`
+	CodeLines int      `json:"codeLines,omitempty"`
+
+	Project        Project                    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	PromptStats    []AnalysisRunPromptStat    `gorm:"foreignKey:AnalysisRunID" json:"promptStats"`
+	CategoryScores []AnalysisRunCategoryScore `gorm:"foreignKey:AnalysisRunID" json:"categoryScores"`
+}
+
+// AnalysisRunCategoryScore is the score of one category of checks in a run
+type AnalysisRunCategoryScore struct {
+	ID            uint    `gorm:"primaryKey" json:"id"`
+	AnalysisRunID uint    `gorm:"not null;index" json:"analysisRunId"`
+	Category      string  `json:"category"`
+	Score         float64 `json:"score"`
+	Penalty       float64 `json:"penalty"`
+	Findings      int     `json:"findings"`
+	FailedChecks  int     `json:"failedChecks"` // Project-level checks that are not met
+
+	AnalysisRun AnalysisRun `gorm:"foreignKey:AnalysisRunID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// AnalysisRunPromptStat summarizes the findings one prompt produced in a run
+type AnalysisRunPromptStat struct {
+	ID             uint   `gorm:"primaryKey" json:"id"`
+	AnalysisRunID  uint   `gorm:"not null;index" json:"analysisRunId"`
+	PromptName     string `json:"promptName"`
+	Findings       int    `json:"findings"`
+	Hallucinated   int    `json:"hallucinated"`   // Flagged by the hallucination guard
+	CriticRejected int    `json:"criticRejected"` // Hidden by the critic
+
+	AnalysisRun AnalysisRun `gorm:"foreignKey:AnalysisRunID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// HallucinationRate returns the share of the prompt's findings flagged by the guard
+func (s AnalysisRunPromptStat) HallucinationRate() float64 {
+	if s.Findings == 0 {
+		return 0
+	}
+	return float64(s.Hallucinated) / float64(s.Findings)
+}
+
+// AnalysisRunFinding is a finding as it was visible to the user when a run finished;
+// runs are compared by the fingerprints of their snapshots
+type AnalysisRunFinding struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	AnalysisRunID uint   `gorm:"not null;index" json:"analysisRunId"`
+	FindingID     uint   `json:"findingId"`
+	Fingerprint   string `gorm:"index" json:"fingerprint"`
+	Source        string `json:"source"`
+	RuleName      string `json:"ruleName"`
+	Severity      string `json:"severity"`
+	Message       string `gorm:"type:text" json:"message"`
+	Path          string `json:"path,omitempty"`
+	Line          int    `json:"line,omitempty"`
+
+	AnalysisRun AnalysisRun `gorm:"foreignKey:AnalysisRunID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// AnalysisRunCompliance is the verdict of a check as it was when a run finished
+type AnalysisRunCompliance struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	AnalysisRunID uint   `gorm:"not null;index" json:"analysisRunId"`
+	PromptName    string `json:"promptName"`
+	Path          string `json:"path,omitempty"` // Empty for project-level checks
+	Compliance    string `gorm:"type:text" json:"compliance"`
+
+	AnalysisRun AnalysisRun `gorm:"foreignKey:AnalysisRunID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,28 @@
+// internal/model/analysis_translation.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AnalysisTranslation is a localized variant of a stored project or file analysis result.
+// Exactly one of ProjectAnalysisResultID and FileAnalysisResultID is set.
+type AnalysisTranslation struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	Language                string `gorm:"not null;index" json:"language"`
+	ProjectAnalysisResultID *uint  `gorm:"index" json:"projectAnalysisResultId,omitempty"`
+	FileAnalysisResultID    *uint  `gorm:"index" json:"fileAnalysisResultId,omitempty"`
+	Issues                  string `gorm:"type:text" json:"issues"`
+	Recommendations         string `gorm:"type:text" json:"recommendations"`
+	GPTCallID               *uint  `json:"gpt_call_id,omitempty"`
+
+	ProjectAnalysisResult *ProjectAnalysisResult `gorm:"foreignKey:ProjectAnalysisResultID;constraint:OnDelete:CASCADE"`
+	FileAnalysisResult    *FileAnalysisResult    `gorm:"foreignKey:FileAnalysisResultID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,24 @@
+// internal/model/code_chunk.go
+
+package model
+
+import (
+	"time"
+)
+
+// CodeChunk is a range of lines of a project file indexed for question answering
+type CodeChunk struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	ProjectID     uint   `gorm:"not null;index" json:"projectId"`
+	ProjectFileID uint   `gorm:"not null;index" json:"projectFileId"`
+	Path          string `json:"path"`
+	StartLine     int    `json:"startLine"`
+	EndLine       int    `json:"endLine"`
+	Content       string `gorm:"type:text" json:"content"`
+	Terms         string `gorm:"type:text" json:"-"` // JSON object of BM25 term frequencies
+	Length        int    `json:"length"`             // Number of terms
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
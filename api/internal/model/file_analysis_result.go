@@ -15,6 +15,7 @@ type FileAnalysisResult struct {
 	Compliance      string         `gorm:"type:text" json:"compliance"`
 	Issues          string         `gorm:"type:text" json:"issues"`
 	Recommendations string         `gorm:"type:text" json:"recommendations"`
+	Language        string         `json:"language"`
 
 	ProjectFileID uint        `gorm:"not null;index" json:"projectFileId"`
 	ProjectFile   ProjectFile `gorm:"foreignKey:ProjectFileID;constraint:OnDelete:CASCADE"`
@@ -0,0 +1,58 @@
+// internal/model/file_coverage.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FileCoverage is the test coverage of a project file taken from an uploaded coverage report
+type FileCoverage struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID       uint   `gorm:"not null;index" json:"projectId"`
+	ProjectFileID   uint   `gorm:"not null;index" json:"projectFileId"`
+	ReportPath      string `json:"reportPath"` // Report the numbers were taken from
+	LinesValid      int    `json:"linesValid"`
+	LinesCovered    int    `json:"linesCovered"`
+	BranchesValid   int    `json:"branchesValid"`
+	BranchesCovered int    `json:"branchesCovered"`
+	MissingLines    string `gorm:"type:text" json:"missingLines"` // Line ranges, e.g. "3-5, 9"
+
+	Project     Project     `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	ProjectFile ProjectFile `gorm:"foreignKey:ProjectFileID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// LineRate returns the share of covered lines in percent
+func (c FileCoverage) LineRate() float64 {
+	return percent(c.LinesCovered, c.LinesValid)
+}
+
+// BranchRate returns the share of covered branches in percent
+func (c FileCoverage) BranchRate() float64 {
+	return percent(c.BranchesCovered, c.BranchesValid)
+}
+
+func percent(covered, valid int) float64 {
+	if valid == 0 {
+		return 0
+	}
+	return float64(covered) * 100 / float64(valid)
+}
+
+// TotalCoverage sums the coverage of several files
+func TotalCoverage(coverage []FileCoverage) FileCoverage {
+	var total FileCoverage
+	for _, c := range coverage {
+		total.LinesValid += c.LinesValid
+		total.LinesCovered += c.LinesCovered
+		total.BranchesValid += c.BranchesValid
+		total.BranchesCovered += c.BranchesCovered
+	}
+	return total
+}
@@ -0,0 +1,90 @@
+// internal/model/finding.go
+
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sources of a finding
+const (
+	FindingSourceLLM      = "llm"      // Raised by a review prompt
+	FindingSourceComputed = "computed" // Computed by the service itself
+	FindingSourceExternal = "external" // Imported from an external tool such as a linter
+)
+
+// Normalized severities of a finding
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+	SeverityInfo   = "info"
+)
+
+// NormalizeSeverity returns the normalized form of a severity written by a user or an LLM,
+// or false when it is not high, medium, low or info
+func NormalizeSeverity(severity string) (string, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(severity))
+	switch normalized {
+	case SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo:
+		return normalized, true
+	}
+	return "", false
+}
+
+// Finding is a single issue found in a project, optionally located in a file
+type Finding struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID     uint   `gorm:"not null;index" json:"projectId"`
+	ProjectFileID *uint  `gorm:"index" json:"projectFileId,omitempty"`
+	AnalysisRunID *uint  `gorm:"index" json:"analysisRunId,omitempty"` // Run that produced a review finding
+	Source        string `gorm:"index" json:"source"`
+	RuleName      string `json:"ruleName"` // Prompt name, computed check or external rule id
+	Tool          string `json:"tool,omitempty"`
+	Severity      string `json:"severity"`
+	Message       string `gorm:"type:text" json:"message"`
+	Path          string `json:"path,omitempty"`
+	Line          int    `json:"line,omitempty"`
+	Column        int    `json:"column,omitempty"`
+
+	// Deduplication: duplicates point to the canonical finding of their cluster, which
+	// lists every rule that raised the problem
+	CanonicalID *uint  `gorm:"index" json:"canonicalId,omitempty"`
+	RuleNames   string `json:"ruleNames,omitempty"`          // Comma-separated, set on canonical findings
+	Occurrences int    `gorm:"default:1" json:"occurrences"` // Size of the cluster, set on canonical findings
+
+	// Review findings: the call that raised the finding and the critic's verdict on it
+	GPTCallID       *uint    `json:"gptCallId,omitempty"`
+	Supported       *bool    `json:"supported,omitempty"`
+	Confidence      *float64 `json:"confidence,omitempty"`
+	CriticReason    string   `gorm:"type:text" json:"criticReason,omitempty"`
+	CriticGPTCallID *uint    `json:"criticGptCallId,omitempty"`
+	Hidden          bool     `gorm:"index" json:"hidden"` // Rejected by the critic or the guard; kept for audit
+
+	// Hallucination guard: set when the finding cites symbols, paths or lines that do not exist
+	Hallucinated bool   `json:"hallucinated"`
+	GuardReason  string `gorm:"type:text" json:"guardReason,omitempty"`
+
+	// Stable identity of the finding across runs and versions, see findings.Fingerprint
+	Fingerprint string `gorm:"index" json:"fingerprint,omitempty"`
+
+	// Triage: set by users and carried over to matching findings of later runs
+	Status    string     `gorm:"index;default:open" json:"status"`
+	Assignee  string     `json:"assignee,omitempty"`
+	TriagedAt *time.Time `json:"triagedAt,omitempty"` // Last status or assignee change by a user
+
+	// Suppression: set when a suppression rule or the project's baseline matches the
+	// finding, which is then left out of reports and quality gates
+	Suppressed        bool   `gorm:"index" json:"suppressed"`
+	SuppressionRuleID *uint  `json:"suppressionRuleId,omitempty"` // Empty for the baseline and the configuration file
+	SuppressionReason string `gorm:"type:text" json:"suppressionReason,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
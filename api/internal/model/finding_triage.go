@@ -0,0 +1,78 @@
+// internal/model/finding_triage.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Triage statuses of a finding
+const (
+	FindingStatusOpen          = "open"
+	FindingStatusInProgress    = "in_progress"
+	FindingStatusFixed         = "fixed"
+	FindingStatusWontFix       = "wont_fix"
+	FindingStatusFalsePositive = "false_positive"
+)
+
+// IsFindingStatus reports whether the status is one of the triage statuses
+func IsFindingStatus(status string) bool {
+	switch status {
+	case FindingStatusOpen, FindingStatusInProgress, FindingStatusFixed, FindingStatusWontFix, FindingStatusFalsePositive:
+		return true
+	}
+	return false
+}
+
+// TriageStatus returns the triage status of the finding; findings stored before triage
+// existed have none and are open
+func (f Finding) TriageStatus() string {
+	if f.Status == "" {
+		return FindingStatusOpen
+	}
+	return f.Status
+}
+
+// Actions recorded in the audit trail of a finding
+const (
+	FindingEventStatus      = "status"
+	FindingEventAssignee    = "assignee"
+	FindingEventComment     = "comment"
+	FindingEventCarriedOver = "carried_over" // Triage copied from a matching finding of an earlier run
+)
+
+// FindingActorSystem is the actor of changes the service makes itself
+const FindingActorSystem = "system"
+
+// FindingComment is a comment in the discussion of a finding
+type FindingComment struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	FindingID uint   `gorm:"not null;index" json:"findingId"`
+	ProjectID uint   `gorm:"not null;index" json:"projectId"`
+	Author    string `json:"author"`
+	Body      string `gorm:"type:text" json:"body"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// FindingEvent is an entry of the audit trail of a finding
+type FindingEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	FindingID uint   `gorm:"not null;index" json:"findingId"`
+	ProjectID uint   `gorm:"not null;index" json:"projectId"`
+	Actor     string `json:"actor"`
+	Action    string `json:"action"`
+	OldValue  string `gorm:"type:text" json:"oldValue,omitempty"`
+	NewValue  string `gorm:"type:text" json:"newValue,omitempty"`
+	Detail    string `gorm:"type:text" json:"detail,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
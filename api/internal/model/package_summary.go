@@ -0,0 +1,25 @@
+// internal/model/package_summary.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PackageSummary is the summary of a project directory, rolled up from its file summaries
+type PackageSummary struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID uint   `gorm:"not null;index" json:"projectId"`
+	Path      string `json:"path"` // Directory of the package, "/" for the project root
+	Files     int    `json:"files"`
+	Summary   string `gorm:"type:text" json:"summary"`
+	GPTCallID *uint  `json:"gptCallId,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,70 @@
+// internal/model/patch_review.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Sources of a reviewed patch
+const (
+	PatchSourceDiff     = "diff"     // A unified diff applied to the stored files
+	PatchSourceArchives = "archives" // Base and head archives compared with each other
+)
+
+// Changes of a file in a patch
+const (
+	FileChangeAdded    = "added"
+	FileChangeModified = "modified"
+	FileChangeDeleted  = "deleted"
+)
+
+// PatchReview is a review of the changes of a merge request to a project. Its findings are
+// kept apart from the project's findings since they refer to code that is not stored.
+type PatchReview struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID  uint       `gorm:"not null;index" json:"projectId"`
+	Source     string     `json:"source"`
+	Status     string     `json:"status"` // One of the run statuses
+	Error      string     `gorm:"type:text" json:"error,omitempty"`
+	Language   string     `json:"language"`
+	Diff       string     `gorm:"type:text" json:"diff"` // Unified diff of the reviewed changes
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+
+	Project  Project           `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Files    []PatchReviewFile `gorm:"foreignKey:PatchReviewID" json:"files"`
+	Findings []PatchFinding    `gorm:"foreignKey:PatchReviewID" json:"findings"`
+}
+
+// PatchReviewFile is a file changed by a reviewed patch
+type PatchReviewFile struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	PatchReviewID uint   `gorm:"not null;index" json:"patchReviewId"`
+	Path          string `json:"path"`
+	OldPath       string `json:"oldPath,omitempty"` // Set when the file was renamed
+	Change        string `json:"change"`
+	AddedLines    int    `json:"addedLines"`
+	Reviewed      bool   `json:"reviewed"` // False for deleted files and files of other languages
+
+	PatchReview PatchReview `gorm:"foreignKey:PatchReviewID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// PatchFinding is an issue a file-level check found in the added lines of a patch
+type PatchFinding struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	PatchReviewID uint   `gorm:"not null;index" json:"patchReviewId"`
+	Path          string `json:"path"`
+	Line          int    `json:"line"`
+	RuleName      string `json:"ruleName"`
+	Severity      string `json:"severity"`
+	Message       string `gorm:"type:text" json:"message"`
+	GPTCallID     *uint  `json:"gptCallId,omitempty"`
+
+	PatchReview PatchReview `gorm:"foreignKey:PatchReviewID;constraint:OnDelete:CASCADE" json:"-"`
+}
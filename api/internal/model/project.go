@@ -19,6 +19,17 @@ type Project struct {
 	Path                  string         `json:"path"`
 	Tree                  string         `json:"tree"`
 	WasAnalyzed           bool           `json:"was_analyzed"`
+	Language              string         `json:"language"` // Report language, e.g. "ru" or "en"
+	// Versions of a project point at its first version, which has no root
+	RootProjectID     *uint `gorm:"index" json:"root_project_id,omitempty"`
+	PreviousVersionID *uint `json:"previous_version_id,omitempty"`
+	Version           int   `gorm:"default:1" json:"version"`
+	// Project summary rolled up from package summaries, for projects too large for prompts
+	Summary string `gorm:"type:text" json:"summary,omitempty"`
+	// Analysis configuration read from the configuration file in the project root, as
+	// JSON, see projectconfig.Config
+	Config     string `gorm:"type:text" json:"config,omitempty"`
+	ConfigPath string `json:"config_path,omitempty"`
 
 	ProgrammingLanguage ProgrammingLanguage `gorm:"foreignKey:ProgrammingLanguageID"`
 }
@@ -8,6 +8,13 @@ import (
 	"gorm.io/gorm"
 )
 
+// Statuses of a project-level check
+const (
+	AnalysisStatusCompleted     = "completed"      // The check ran and produced a compliance verdict
+	AnalysisStatusNotApplicable = "not_applicable" // The master agent skipped the check
+	AnalysisStatusError         = "error"          // The check ran but its reply could not be used
+)
+
 type ProjectAnalysisResult struct {
 	ID        uint           `gorm:"primaryKey" json:"id"`
 	CreatedAt time.Time      `json:"createdAt"`
@@ -19,6 +26,16 @@ type ProjectAnalysisResult struct {
 	Compliance      string `gorm:"type:text" json:"compliance"`
 	Issues          string `gorm:"type:text" json:"issues"`
 	Recommendations string `gorm:"type:text" json:"recommendations"`
+	Language        string `json:"language"`
+	Status          string `json:"status"`
+	StatusReason    string `gorm:"type:text" json:"statusReason"`    // Justification of the master agent or error details
+	InputHash       string `gorm:"index" json:"inputHash,omitempty"` // SHA-256 of the prompt the result was produced from
 
 	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
 }
+
+// Completed reports whether the check produced a compliance verdict; results stored before
+// checks had a status are completed
+func (r ProjectAnalysisResult) Completed() bool {
+	return r.Status == AnalysisStatusCompleted || r.Status == ""
+}
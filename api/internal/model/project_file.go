@@ -17,6 +17,12 @@ type ProjectFile struct {
 	Content     string         `json:"content"`
 	WasAnalyzed bool           `json:"was_analyzed"`
 	GPTCallID   *uint          `json:"gpt_call_id,omitempty"`
+	ContentHash string         `gorm:"index" json:"content_hash"` // SHA-256 of the content
+	// Unchanged file of the previous project version whose results were carried over
+	CarriedFromID *uint `json:"carried_from_id,omitempty"`
+	// Short description of the file used by project-level prompts when code does not fit
+	Summary          string `gorm:"type:text" json:"summary,omitempty"`
+	SummaryGPTCallID *uint  `json:"summary_gpt_call_id,omitempty"`
 
 	Project             Project              `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
 	FileAnalysisResults []FileAnalysisResult `gorm:"foreignKey:ProjectFileID"`
@@ -0,0 +1,31 @@
+// internal/model/qa_message.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Roles of a question answering message
+const (
+	QARoleUser      = "user"
+	QARoleAssistant = "assistant"
+)
+
+// QAMessage is a question about a project or the answer to it
+type QAMessage struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID uint   `gorm:"not null;index" json:"projectId"`
+	Role      string `json:"role"`
+	Content   string `gorm:"type:text" json:"content"`
+	Citations string `gorm:"type:text" json:"citations,omitempty"` // JSON list of cited line ranges
+	GPTCallID *uint  `json:"gptCallId,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,57 @@
+// internal/model/suppression.go
+
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SuppressionRule hides the findings it matches from reports and quality gates. Every
+// matcher that is set must match; a rule stops applying once it expires.
+type SuppressionRule struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID      uint       `gorm:"not null;index" json:"projectId"`
+	RuleName       string     `json:"ruleName,omitempty"`                        // Prompt name, computed check or external rule id
+	PathGlob       string     `json:"pathGlob,omitempty"`                        // Supports *, ? and **
+	MessagePattern string     `gorm:"type:text" json:"messagePattern,omitempty"` // Regular expression
+	Reason         string     `gorm:"type:text;not null" json:"reason"`
+	Author         string     `json:"author,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+
+	Project Project `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// Expired reports whether the rule no longer applies at the given time
+func (rule SuppressionRule) Expired(now time.Time) bool {
+	return rule.ExpiresAt != nil && !rule.ExpiresAt.After(now)
+}
+
+// Baseline accepts the findings of a project as they were when it was taken: findings
+// with one of its fingerprints are suppressed, so that only new problems are reported
+type Baseline struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt time.Time      `json:"createdAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deletedAt,omitempty"`
+
+	ProjectID uint   `gorm:"not null;index" json:"projectId"`
+	Author    string `json:"author,omitempty"`
+	Reason    string `gorm:"type:text" json:"reason,omitempty"`
+
+	Project      Project               `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE" json:"-"`
+	Fingerprints []BaselineFingerprint `gorm:"foreignKey:BaselineID" json:"fingerprints"`
+}
+
+// BaselineFingerprint is the fingerprint of a finding accepted by a baseline
+type BaselineFingerprint struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	BaselineID  uint   `gorm:"not null;index" json:"baselineId"`
+	Fingerprint string `gorm:"index" json:"fingerprint"`
+
+	Baseline Baseline `gorm:"foreignKey:BaselineID;constraint:OnDelete:CASCADE" json:"-"`
+}
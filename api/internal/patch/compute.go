@@ -0,0 +1,193 @@
+// internal/patch/compute.go
+
+package patch
+
+import "strings"
+
+// contextLines is the number of unchanged lines kept around changes, as in diff -u
+const contextLines = 3
+
+// maxEditSpan bounds the changed region Compute diffs line by line; larger rewrites are
+// reported as one replacement, which is what a reviewer sees anyway
+const maxEditSpan = 2000
+
+// Compute returns the changes between two versions of a file. An empty old content
+// means the file is new and an empty new content that it was deleted.
+func Compute(oldPath, newPath, oldContent, newContent string) FileDiff {
+	diff := FileDiff{OldPath: oldPath, NewPath: newPath}
+	oldLines := splitLines(oldContent)
+	newLines := splitLines(newContent)
+
+	// Common prefix and suffix are matched directly, only the middle is diffed
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	var script []Line
+	for _, text := range oldLines[:prefix] {
+		script = append(script, Line{Kind: LineContext, Text: text})
+	}
+	script = append(script, editScript(oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])...)
+	for _, text := range oldLines[len(oldLines)-suffix:] {
+		script = append(script, Line{Kind: LineContext, Text: text})
+	}
+
+	diff.Hunks = groupHunks(script)
+	return diff
+}
+
+// editScript returns the shortest sequence of kept, removed and added lines turning a
+// into b (Myers' algorithm)
+func editScript(a, b []string) []Line {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 || n+m > maxEditSpan {
+		script := make([]Line, 0, n+m)
+		for _, text := range a {
+			script = append(script, Line{Kind: LineRemoved, Text: text})
+		}
+		for _, text := range b {
+			script = append(script, Line{Kind: LineAdded, Text: text})
+		}
+		return script
+	}
+
+	max := n + m
+	offset := max
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		snapshot := make([]int, len(v))
+		copy(snapshot, v)
+		trace = append(trace, snapshot)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset, d)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the saved frontiers back from the end to recover the edit script
+func backtrack(a, b []string, trace [][]int, offset, depth int) []Line {
+	var reversed []Line
+	x, y := len(a), len(b)
+	for d := depth; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			reversed = append(reversed, Line{Kind: LineContext, Text: a[x]})
+		}
+		if x == prevX {
+			y--
+			reversed = append(reversed, Line{Kind: LineAdded, Text: b[y]})
+		} else {
+			x--
+			reversed = append(reversed, Line{Kind: LineRemoved, Text: a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		reversed = append(reversed, Line{Kind: LineContext, Text: a[x]})
+	}
+
+	script := make([]Line, len(reversed))
+	for i, line := range reversed {
+		script[len(reversed)-1-i] = line
+	}
+	return script
+}
+
+// groupHunks splits an edit script into hunks with contextLines of context around changes
+func groupHunks(script []Line) []Hunk {
+	var hunks []Hunk
+	oldLine, newLine := 1, 1
+	oldAt := make([]int, len(script))
+	newAt := make([]int, len(script))
+	for i, line := range script {
+		oldAt[i], newAt[i] = oldLine, newLine
+		if line.Kind != LineAdded {
+			oldLine++
+		}
+		if line.Kind != LineRemoved {
+			newLine++
+		}
+	}
+
+	for i := 0; i < len(script); i++ {
+		if script[i].Kind == LineContext {
+			continue
+		}
+		start := i - contextLines
+		if start < 0 {
+			start = 0
+		}
+		// Extend the hunk while the next change is close enough to share context
+		end := i
+		for j := i; j < len(script) && j <= end+2*contextLines; j++ {
+			if script[j].Kind != LineContext {
+				end = j
+			}
+		}
+		stop := end + contextLines + 1
+		if stop > len(script) {
+			stop = len(script)
+		}
+
+		hunk := Hunk{OldStart: oldAt[start], NewStart: newAt[start], Lines: append([]Line(nil), script[start:stop]...)}
+		for _, line := range hunk.Lines {
+			if line.Kind != LineAdded {
+				hunk.OldLines++
+			}
+			if line.Kind != LineRemoved {
+				hunk.NewLines++
+			}
+		}
+		// Like diff -u, an empty side starts at the line before the hunk
+		if hunk.OldLines == 0 {
+			hunk.OldStart--
+		}
+		if hunk.NewLines == 0 {
+			hunk.NewStart--
+		}
+		hunks = append(hunks, hunk)
+		i = stop - 1
+	}
+	return hunks
+}
+
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
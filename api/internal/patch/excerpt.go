@@ -0,0 +1,55 @@
+// internal/patch/excerpt.go
+
+package patch
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Excerpt renders the parts of a file around the given added lines for a review prompt:
+// every line is prefixed with its number, added lines are marked with "+" and skipped
+// regions with "...". Windows of nearby changes are merged.
+func Excerpt(content string, added []int, context int) string {
+	lines := splitLines(content)
+	if len(lines) == 0 || len(added) == 0 {
+		return ""
+	}
+	isAdded := make(map[int]bool, len(added))
+	for _, line := range added {
+		isAdded[line] = true
+	}
+
+	// Mark the lines to show
+	show := make([]bool, len(lines)+1)
+	for _, line := range added {
+		for i := line - context; i <= line+context; i++ {
+			if i >= 1 && i <= len(lines) {
+				show[i] = true
+			}
+		}
+	}
+
+	var builder strings.Builder
+	width := len(fmt.Sprint(len(lines)))
+	skipped := false
+	for number := 1; number <= len(lines); number++ {
+		if !show[number] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			builder.WriteString("...\n")
+		}
+		skipped = false
+		marker := " "
+		if isAdded[number] {
+			marker = "+"
+		}
+		fmt.Fprintf(&builder, "%*d %s| %s\n", width, number, marker, lines[number-1])
+	}
+	if skipped {
+		builder.WriteString("...\n")
+	}
+	return builder.String()
+}
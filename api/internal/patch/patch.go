@@ -0,0 +1,325 @@
+// internal/patch/patch.go
+
+package patch
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Kinds of a line of a hunk
+const (
+	LineContext = ' '
+	LineAdded   = '+'
+	LineRemoved = '-'
+)
+
+// Line is a single line of a hunk
+type Line struct {
+	Kind byte
+	Text string
+}
+
+// Hunk is a group of changes with its position in the old and the new file
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+// FileDiff holds the changes of one file; OldPath is empty for new files and NewPath is
+// empty for deleted ones
+type FileDiff struct {
+	OldPath string
+	NewPath string
+	Hunks   []Hunk
+}
+
+// Path returns the path of the file after the change, or before it for deleted files
+func (d FileDiff) Path() string {
+	if d.NewPath != "" {
+		return d.NewPath
+	}
+	return d.OldPath
+}
+
+func (d FileDiff) IsNew() bool {
+	return d.OldPath == ""
+}
+
+func (d FileDiff) IsDeleted() bool {
+	return d.NewPath == ""
+}
+
+// AddedLines returns the 1-based numbers, in the new file, of the lines the diff adds or
+// modifies, as given by the hunk headers
+func (d FileDiff) AddedLines() []int {
+	var added []int
+	for _, hunk := range d.Hunks {
+		added = append(added, hunkAddedLines(hunk, hunk.NewStart)...)
+	}
+	return added
+}
+
+func hunkAddedLines(hunk Hunk, start int) []int {
+	var added []int
+	line := start
+	for _, hunkLine := range hunk.Lines {
+		switch hunkLine.Kind {
+		case LineAdded:
+			added = append(added, line)
+			line++
+		case LineContext:
+			line++
+		}
+	}
+	return added
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse reads a unified diff as produced by git diff or diff -u
+func Parse(text string) ([]FileDiff, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	var diffs []FileDiff
+	var current *FileDiff
+	flush := func() {
+		if current != nil && (len(current.Hunks) > 0 || current.OldPath != current.NewPath) {
+			diffs = append(diffs, *current)
+		}
+		current = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flush()
+			current = &FileDiff{}
+			if fields := strings.Fields(line); len(fields) == 4 {
+				current.OldPath = stripPrefix(fields[2])
+				current.NewPath = stripPrefix(fields[3])
+			}
+		case strings.HasPrefix(line, "new file mode") && current != nil:
+			current.OldPath = ""
+		case strings.HasPrefix(line, "deleted file mode") && current != nil:
+			current.NewPath = ""
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			if current == nil || len(current.Hunks) > 0 {
+				flush()
+				current = &FileDiff{}
+			}
+			current.OldPath = headerPath(line[4:])
+			current.NewPath = headerPath(lines[i+1][4:])
+			i++
+		case strings.HasPrefix(line, "@@ "):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk outside of a file diff", i+1)
+			}
+			hunk, consumed, err := parseHunk(lines[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current.Hunks = append(current.Hunks, hunk)
+			i += consumed - 1
+		}
+	}
+	flush()
+
+	if len(diffs) == 0 {
+		return nil, errors.New("no file changes found in the diff")
+	}
+	return diffs, nil
+}
+
+// parseHunk reads a hunk starting at its header and returns the number of lines it spans
+func parseHunk(lines []string) (Hunk, int, error) {
+	match := hunkHeaderPattern.FindStringSubmatch(lines[0])
+	if match == nil {
+		return Hunk{}, 0, fmt.Errorf("invalid hunk header %q", lines[0])
+	}
+	hunk := Hunk{
+		OldStart: atoi(match[1], 0),
+		OldLines: atoi(match[2], 1),
+		NewStart: atoi(match[3], 0),
+		NewLines: atoi(match[4], 1),
+	}
+
+	oldSeen, newSeen := 0, 0
+	consumed := 1
+	for ; consumed < len(lines) && (oldSeen < hunk.OldLines || newSeen < hunk.NewLines); consumed++ {
+		line := lines[consumed]
+		if strings.HasPrefix(line, `\`) { // "\ No newline at end of file"
+			continue
+		}
+		kind, text := byte(LineContext), ""
+		if line != "" { // Some tools strip the space of empty context lines
+			kind, text = line[0], line[1:]
+		}
+		switch kind {
+		case LineContext:
+			oldSeen++
+			newSeen++
+		case LineRemoved:
+			oldSeen++
+		case LineAdded:
+			newSeen++
+		default:
+			return Hunk{}, 0, fmt.Errorf("unexpected line %q in hunk", line)
+		}
+		hunk.Lines = append(hunk.Lines, Line{Kind: kind, Text: text})
+	}
+	if oldSeen != hunk.OldLines || newSeen != hunk.NewLines {
+		return Hunk{}, 0, errors.New("hunk is shorter than its header says")
+	}
+	for consumed < len(lines) && strings.HasPrefix(lines[consumed], `\`) {
+		consumed++
+	}
+	return hunk, consumed, nil
+}
+
+// Apply applies the diff of one file to its content and returns the new content together
+// with the 1-based numbers of the added lines in it. A hunk whose context moved, because
+// the stored file differs slightly from the base of the diff, is applied where its context
+// is found.
+func Apply(content string, diff FileDiff) (string, []int, error) {
+	if diff.IsDeleted() {
+		return "", nil, nil
+	}
+
+	trailingNewline := content == "" || strings.HasSuffix(content, "\n")
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	var result []string
+	var added []int
+	position := 0 // Next line of the old content to copy
+	for index, hunk := range diff.Hunks {
+		var oldBlock []string
+		for _, line := range hunk.Lines {
+			if line.Kind != LineAdded {
+				oldBlock = append(oldBlock, line.Text)
+			}
+		}
+
+		expected := hunk.OldStart - 1
+		if hunk.OldLines == 0 {
+			expected = hunk.OldStart // Pure insertions start after the given line
+		}
+		start := findBlock(lines, oldBlock, expected, position)
+		if start < 0 {
+			return "", nil, fmt.Errorf("hunk %d of %s does not apply", index+1, diff.Path())
+		}
+
+		result = append(result, lines[position:start]...)
+		added = append(added, hunkAddedLines(hunk, len(result)+1)...)
+		for _, line := range hunk.Lines {
+			if line.Kind != LineRemoved {
+				result = append(result, line.Text)
+			}
+		}
+		position = start + len(oldBlock)
+	}
+	result = append(result, lines[position:]...)
+
+	newContent := strings.Join(result, "\n")
+	if trailingNewline && len(result) > 0 {
+		newContent += "\n"
+	}
+	return newContent, added, nil
+}
+
+// findBlock returns where the block occurs in lines at or after from, preferring the
+// occurrence closest to the expected position, or -1 when it does not occur
+func findBlock(lines, block []string, expected, from int) int {
+	matches := func(start int) bool {
+		if start < from || start+len(block) > len(lines) {
+			return false
+		}
+		for i, text := range block {
+			if strings.TrimRight(lines[start+i], " \t\r") != strings.TrimRight(text, " \t\r") {
+				return false
+			}
+		}
+		return true
+	}
+	if expected < from {
+		expected = from
+	}
+	for offset := 0; offset <= len(lines); offset++ {
+		if matches(expected + offset) {
+			return expected + offset
+		}
+		if offset > 0 && matches(expected-offset) {
+			return expected - offset
+		}
+	}
+	return -1
+}
+
+// headerPath extracts the path of a ---/+++ line; /dev/null becomes an empty path
+func headerPath(value string) string {
+	if tab := strings.IndexByte(value, '\t'); tab >= 0 {
+		value = value[:tab] // diff -u appends the modification time
+	}
+	value = strings.TrimSpace(value)
+	if value == "/dev/null" {
+		return ""
+	}
+	return stripPrefix(value)
+}
+
+// stripPrefix removes the a/ and b/ prefixes git puts before paths
+func stripPrefix(path string) string {
+	if strings.HasPrefix(path, "a/") || strings.HasPrefix(path, "b/") {
+		return path[2:]
+	}
+	return path
+}
+
+func atoi(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return fallback
+	}
+	return number
+}
+
+// Format renders file diffs as a unified diff in the layout of git diff
+func Format(diffs []FileDiff) string {
+	var builder strings.Builder
+	for _, diff := range diffs {
+		oldPath, newPath := "a/"+diff.OldPath, "b/"+diff.NewPath
+		switch {
+		case diff.IsNew():
+			oldPath = "/dev/null"
+			fmt.Fprintf(&builder, "diff --git a/%s b/%s\nnew file mode 100644\n", diff.NewPath, diff.NewPath)
+		case diff.IsDeleted():
+			newPath = "/dev/null"
+			fmt.Fprintf(&builder, "diff --git a/%s b/%s\ndeleted file mode 100644\n", diff.OldPath, diff.OldPath)
+		default:
+			fmt.Fprintf(&builder, "diff --git %s %s\n", oldPath, newPath)
+		}
+		fmt.Fprintf(&builder, "--- %s\n+++ %s\n", oldPath, newPath)
+		for _, hunk := range diff.Hunks {
+			fmt.Fprintf(&builder, "@@ -%d,%d +%d,%d @@\n", hunk.OldStart, hunk.OldLines, hunk.NewStart, hunk.NewLines)
+			for _, line := range hunk.Lines {
+				builder.WriteByte(line.Kind)
+				builder.WriteString(line.Text)
+				builder.WriteByte('\n')
+			}
+		}
+	}
+	return builder.String()
+}
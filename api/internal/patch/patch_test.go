@@ -0,0 +1,377 @@
+// internal/patch/patch_test.go
+
+package patch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		name  string
+		diff  string
+		want  []FileDiff
+		added [][]int // Added lines of every file diff
+	}{
+		{
+			name: "git diff with two hunks",
+			diff: `diff --git a/app/clock.py b/app/clock.py
+index 3b18e51..a9c4f2d 100644
+--- a/app/clock.py
++++ b/app/clock.py
+@@ -1,3 +1,3 @@
+-from datetime import datetime
++from datetime import datetime, timezone
+
+
+@@ -10,2 +10,3 @@ def now():
+ def later():
++    """Returns the time in an hour"""
+     return now()
+`,
+			want: []FileDiff{{
+				OldPath: "app/clock.py",
+				NewPath: "app/clock.py",
+				Hunks: []Hunk{
+					{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 3, Lines: []Line{
+						{LineRemoved, "from datetime import datetime"},
+						{LineAdded, "from datetime import datetime, timezone"},
+						{LineContext, ""},
+						{LineContext, ""},
+					}},
+					{OldStart: 10, OldLines: 2, NewStart: 10, NewLines: 3, Lines: []Line{
+						{LineContext, "def later():"},
+						{LineAdded, `    """Returns the time in an hour"""`},
+						{LineContext, "    return now()"},
+					}},
+				},
+			}},
+			added: [][]int{{1, 11}},
+		},
+		{
+			name: "new and deleted files",
+			diff: `diff --git a/app/new.py b/app/new.py
+new file mode 100644
+--- /dev/null
++++ b/app/new.py
+@@ -0,0 +1,2 @@
++import os
++print(os.getcwd())
+diff --git a/app/old.py b/app/old.py
+deleted file mode 100644
+--- a/app/old.py
++++ /dev/null
+@@ -1 +0,0 @@
+-print("old")
+`,
+			want: []FileDiff{
+				{NewPath: "app/new.py", Hunks: []Hunk{{OldStart: 0, OldLines: 0, NewStart: 1, NewLines: 2, Lines: []Line{
+					{LineAdded, "import os"},
+					{LineAdded, "print(os.getcwd())"},
+				}}}},
+				{OldPath: "app/old.py", Hunks: []Hunk{{OldStart: 1, OldLines: 1, NewStart: 0, NewLines: 0, Lines: []Line{
+					{LineRemoved, `print("old")`},
+				}}}},
+			},
+			added: [][]int{{1, 2}, nil},
+		},
+		{
+			name: "diff -u with timestamps and no newline at end of file",
+			diff: "--- app/main.py\t2024-05-01 10:00:00.000000000 +0300\n" +
+				"+++ app/main.py\t2024-05-02 10:00:00.000000000 +0300\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				" import sys\n" +
+				"-sys.exit(1)\n" +
+				"\\ No newline at end of file\n" +
+				"+sys.exit(0)\n" +
+				"\\ No newline at end of file\n",
+			want: []FileDiff{{
+				OldPath: "app/main.py",
+				NewPath: "app/main.py",
+				Hunks: []Hunk{{OldStart: 1, OldLines: 2, NewStart: 1, NewLines: 2, Lines: []Line{
+					{LineContext, "import sys"},
+					{LineRemoved, "sys.exit(1)"},
+					{LineAdded, "sys.exit(0)"},
+				}}},
+			}},
+			added: [][]int{{2}},
+		},
+		{
+			name: "empty context line without its space",
+			diff: "--- a/a.py\n+++ b/a.py\n@@ -1,3 +1,3 @@\n x = 1\n\n-y = 2\n+y = 3\n",
+			want: []FileDiff{{
+				OldPath: "a.py",
+				NewPath: "a.py",
+				Hunks: []Hunk{{OldStart: 1, OldLines: 3, NewStart: 1, NewLines: 3, Lines: []Line{
+					{LineContext, "x = 1"},
+					{LineContext, ""},
+					{LineRemoved, "y = 2"},
+					{LineAdded, "y = 3"},
+				}}},
+			}},
+			added: [][]int{{3}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diffs, err := Parse(tc.diff)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(diffs, tc.want) {
+				t.Fatalf("got %+v, want %+v", diffs, tc.want)
+			}
+			for i, diff := range diffs {
+				if added := diff.AddedLines(); !reflect.DeepEqual(added, tc.added[i]) {
+					t.Errorf("file %d: got added lines %v, want %v", i, added, tc.added[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"no changes":          "just some text\n",
+		"hunk outside a file": "@@ -1 +1 @@\n-a\n+b\n",
+		"truncated hunk":      "--- a/a.py\n+++ b/a.py\n@@ -1,3 +1,3 @@\n x = 1\n",
+		"invalid hunk line":   "--- a/a.py\n+++ b/a.py\n@@ -1,2 +1,2 @@\n x = 1\n*y = 2\n",
+		"invalid hunk header": "--- a/a.py\n+++ b/a.py\n@@ -x +1 @@\n-a\n+b\n",
+	}
+	for name, diff := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diffs, err := Parse(diff); err == nil {
+				t.Errorf("got %+v, want an error", diffs)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		diff    string
+		want    string
+		added   []int
+	}{
+		{
+			name:    "hunk at its position",
+			content: "a\nb\nc\nd\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -2,2 +2,2 @@\n b\n-c\n+C\n",
+			want:    "a\nb\nC\nd\n",
+			added:   []int{3},
+		},
+		{
+			name:    "context moved down",
+			content: "new 1\nnew 2\na\nb\nc\nd\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -2,2 +2,3 @@\n b\n+b2\n c\n",
+			want:    "new 1\nnew 2\na\nb\nb2\nc\nd\n",
+			added:   []int{5},
+		},
+		{
+			name:    "context moved up",
+			content: "b\nc\nd\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -3,2 +3,2 @@\n c\n-d\n+D\n",
+			want:    "b\nc\nD\n",
+			added:   []int{3},
+		},
+		{
+			name:    "context matched without trailing whitespace",
+			content: "a  \nb\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -1,2 +1,2 @@\n a\n-b\n+B\n",
+			want:    "a\nB\n",
+			added:   []int{2},
+		},
+		{
+			name:    "pure insertion",
+			content: "a\nb\nc\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -2,0 +3,2 @@\n+x\n+y\n",
+			want:    "a\nb\nx\ny\nc\n",
+			added:   []int{3, 4},
+		},
+		{
+			name:    "insertion at the start",
+			content: "a\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -0,0 +1 @@\n+first\n",
+			want:    "first\na\n",
+			added:   []int{1},
+		},
+		{
+			name:    "two hunks",
+			content: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -1,2 +1,2 @@\n-1\n+one\n 2\n@@ -9,2 +9,3 @@\n 9\n+9.5\n 10\n",
+			want:    "one\n2\n3\n4\n5\n6\n7\n8\n9\n9.5\n10\n",
+			added:   []int{1, 10},
+		},
+		{
+			name:    "new file",
+			content: "",
+			diff:    "--- /dev/null\n+++ b/f.py\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+			want:    "a\nb\n",
+			added:   []int{1, 2},
+		},
+		{
+			name:    "deleted file",
+			content: "a\nb\n",
+			diff:    "--- a/f.py\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			want:    "",
+		},
+		{
+			name:    "no newline at end of file",
+			content: "a\nb",
+			diff:    "--- a/f.py\n+++ b/f.py\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+			want:    "a\nc",
+			added:   []int{2},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			diffs, err := Parse(tc.diff)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			content, added, err := Apply(tc.content, diffs[0])
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if content != tc.want {
+				t.Errorf("got content %q, want %q", content, tc.want)
+			}
+			if !reflect.DeepEqual(added, tc.added) {
+				t.Errorf("got added lines %v, want %v", added, tc.added)
+			}
+		})
+	}
+}
+
+func TestApplyMismatch(t *testing.T) {
+	diffs, err := Parse("--- a/f.py\n+++ b/f.py\n@@ -1,2 +1,2 @@\n a\n-b\n+c\n")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if content, _, err := Apply("x\ny\n", diffs[0]); err == nil {
+		t.Errorf("got %q, want an error for a hunk whose context is missing", content)
+	}
+}
+
+func TestComputeRoundTrip(t *testing.T) {
+	numbered := func(from, to int, replace map[int]string) string {
+		var lines []string
+		for i := from; i <= to; i++ {
+			line, ok := replace[i]
+			if !ok {
+				line = "line " + strings.Repeat("x", i%3) + string(rune('a'+i%26))
+			}
+			if line != "-" {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	cases := []struct {
+		name       string
+		oldPath    string
+		newPath    string
+		oldContent string
+		newContent string
+		hunks      int
+	}{
+		{
+			name:       "modified line",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: numbered(1, 10, nil),
+			newContent: numbered(1, 10, map[int]string{5: "changed"}),
+			hunks:      1,
+		},
+		{
+			name:       "insertion at the start and removal at the end",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: "a\nb\nc\n",
+			newContent: "start\na\nb\n",
+			hunks:      1,
+		},
+		{
+			name:       "distant changes",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: numbered(1, 30, nil),
+			newContent: numbered(1, 30, map[int]string{2: "two", 15: "-", 28: "twenty-eight"}),
+			hunks:      3,
+		},
+		{
+			name:       "close changes share a hunk",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: numbered(1, 20, nil),
+			newContent: numbered(1, 20, map[int]string{8: "eight", 12: "twelve"}),
+			hunks:      1,
+		},
+		{
+			name:       "repeated lines",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: "pass\npass\nx = 1\npass\npass\n",
+			newContent: "pass\nx = 1\npass\ny = 2\npass\npass\n",
+			hunks:      1,
+		},
+		{
+			name:       "rewritten file",
+			oldPath:    "f.py",
+			newPath:    "f.py",
+			oldContent: "a\nb\n",
+			newContent: "c\nd\ne\n",
+			hunks:      1,
+		},
+		{
+			name:       "new file",
+			newPath:    "f.py",
+			newContent: "import os\nprint(os.getcwd())\n",
+			hunks:      1,
+		},
+		{
+			name:       "deleted file",
+			oldPath:    "f.py",
+			oldContent: "import os\n",
+			hunks:      1,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			computed := Compute(tc.oldPath, tc.newPath, tc.oldContent, tc.newContent)
+			if len(computed.Hunks) != tc.hunks {
+				t.Errorf("got %d hunks, want %d", len(computed.Hunks), tc.hunks)
+			}
+
+			diffs, err := Parse(Format([]FileDiff{computed}))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(diffs) != 1 {
+				t.Fatalf("got %d file diffs, want 1", len(diffs))
+			}
+			if !reflect.DeepEqual(diffs[0], computed) {
+				t.Fatalf("parsed %+v, computed %+v", diffs[0], computed)
+			}
+
+			content, added, err := Apply(tc.oldContent, diffs[0])
+			if err != nil {
+				t.Fatalf("Apply: %v", err)
+			}
+			if content != tc.newContent {
+				t.Errorf("got content %q, want %q", content, tc.newContent)
+			}
+			if want := computed.AddedLines(); !reflect.DeepEqual(added, want) {
+				t.Errorf("got added lines %v, want %v", added, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+// internal/projectconfig/config.go
+
+package projectconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/quality"
+	"evraz_api/internal/utils"
+)
+
+// FileNames lists the names under which the configuration file is looked up in the root
+// of a project, in order
+var FileNames = []string{"evraz.yaml", "evraz.yml", ".evraz.yaml", ".evraz.yml"}
+
+// Layer values as returned by the file master prompt
+const (
+	LayerApplication = 0
+	LayerAdapters    = 1
+	LayerOther       = 2
+)
+
+// Config is the analysis configuration a project ships in its root:
+//
+//	language: en
+//	checks:
+//	  disable: [DateTimeHandling, DateTimeHandlingFile]
+//	exclude: ["migrations/**", "*_pb2.py"]
+//	layers:
+//	  application: ["app/services/**"]
+//	  adapters: ["app/adapters/**", "app/api/**"]
+//	severity:
+//	  CodingStandards: low
+//	suppressions:
+//	  - rule: E501
+//	    path: "legacy/**"
+//	    reason: Legacy code is reformatted separately
+//	    expires: 2025-12-31
+//	gates:
+//	  release:
+//	    max_new: {high: 0}
+//	    min_score: 70
+type Config struct {
+	Language     string                    `yaml:"language" json:"language,omitempty"`
+	Checks       Checks                    `yaml:"checks" json:"checks"`
+	Exclude      []string                  `yaml:"exclude" json:"exclude,omitempty"` // Path globs of files that are not analyzed
+	Layers       Layers                    `yaml:"layers" json:"layers"`
+	Severity     map[string]string         `yaml:"severity" json:"severity,omitempty"` // Severity by rule name
+	Suppressions []Suppression             `yaml:"suppressions" json:"suppressions,omitempty"`
+	Gates        map[string]quality.Policy `yaml:"gates" json:"gates,omitempty"` // Quality gate policies by name
+
+	exclude []*utils.Glob
+	layers  [3][]*utils.Glob // Indexed by layer value
+}
+
+// Checks selects the checks that run: when Enable is set only the listed checks run, and
+// checks listed in Disable never do
+type Checks struct {
+	Enable  []string `yaml:"enable" json:"enable,omitempty"`
+	Disable []string `yaml:"disable" json:"disable,omitempty"`
+}
+
+// Layers assigns files to architecture layers by path glob instead of asking the LLM
+type Layers struct {
+	Application []string `yaml:"application" json:"application,omitempty"`
+	Adapters    []string `yaml:"adapters" json:"adapters,omitempty"`
+	Other       []string `yaml:"other" json:"other,omitempty"` // Only the universal checks apply
+}
+
+// Suppression hides the findings it matches, like a suppression rule stored with the project
+type Suppression struct {
+	Rule    string `yaml:"rule" json:"rule,omitempty"`
+	Path    string `yaml:"path" json:"path,omitempty"`
+	Message string `yaml:"message" json:"message,omitempty"` // Regular expression
+	Reason  string `yaml:"reason" json:"reason"`
+	Expires string `yaml:"expires" json:"expires,omitempty"` // YYYY-MM-DD or RFC 3339
+}
+
+// IsConfigFile reports whether the project path is a configuration file in the root
+func IsConfigFile(filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "./")
+	if path.Dir(filePath) != "." {
+		return false
+	}
+	for _, name := range FileNames {
+		if filePath == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Load restores a configuration stored with Encode; empty data is the default configuration
+func Load(data string) (*Config, error) {
+	config := &Config{}
+	if data == "" {
+		return config, nil
+	}
+	if err := json.Unmarshal([]byte(data), config); err != nil {
+		return &Config{}, fmt.Errorf("invalid stored configuration: %w", err)
+	}
+	if err := config.compile(); err != nil {
+		return &Config{}, err
+	}
+	return config, nil
+}
+
+// Encode serializes the configuration for storage on the project
+func (c *Config) Encode() (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (c *Config) compile() error {
+	c.exclude = nil
+	for _, pattern := range c.Exclude {
+		glob, err := utils.CompileGlob(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid exclude glob %q: %w", pattern, err)
+		}
+		c.exclude = append(c.exclude, glob)
+	}
+	for layer, patterns := range [3][]string{c.Layers.Application, c.Layers.Adapters, c.Layers.Other} {
+		c.layers[layer] = nil
+		for _, pattern := range patterns {
+			glob, err := utils.CompileGlob(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid layer glob %q: %w", pattern, err)
+			}
+			c.layers[layer] = append(c.layers[layer], glob)
+		}
+	}
+	return nil
+}
+
+// CheckEnabled reports whether the check with the given prompt name runs
+func (c *Config) CheckEnabled(name string) bool {
+	for _, disabled := range c.Checks.Disable {
+		if disabled == name {
+			return false
+		}
+	}
+	if len(c.Checks.Enable) == 0 {
+		return true
+	}
+	for _, enabled := range c.Checks.Enable {
+		if enabled == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded reports whether the file is left out of the analysis
+func (c *Config) Excluded(filePath string) bool {
+	for _, glob := range c.exclude {
+		if glob.Match(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Layer returns the layer the configuration assigns the file to, if any
+func (c *Config) Layer(filePath string) (int, bool) {
+	for layer, globs := range c.layers {
+		for _, glob := range globs {
+			if glob.Match(filePath) {
+				return layer, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// SeverityFor returns the severity the configuration sets for findings of the rule
+func (c *Config) SeverityFor(ruleName string) (string, bool) {
+	severity, ok := c.Severity[ruleName]
+	return severity, ok
+}
+
+// SuppressionRules returns the suppressions of the configuration as suppression rules
+// without an ID; configPath names the file in their reasons
+func (c *Config) SuppressionRules(configPath string) []model.SuppressionRule {
+	rules := make([]model.SuppressionRule, 0, len(c.Suppressions))
+	for _, suppression := range c.Suppressions {
+		rule := model.SuppressionRule{
+			RuleName:       suppression.Rule,
+			PathGlob:       suppression.Path,
+			MessagePattern: suppression.Message,
+			Reason:         fmt.Sprintf("%s: %s", configPath, suppression.Reason),
+		}
+		if expires, err := parseDate(suppression.Expires); err == nil && !expires.IsZero() {
+			rule.ExpiresAt = &expires
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// parseDate reads an expiry date; a date without a time expires at the end of that day
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.AddDate(0, 0, 1), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,242 @@
+// internal/projectconfig/parse.go
+
+package projectconfig
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"evraz_api/internal/findings"
+	"evraz_api/internal/model"
+	"evraz_api/internal/quality"
+	"evraz_api/internal/utils"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem found in the configuration file
+type Issue struct {
+	Line    int // 0 when the problem has no single line
+	Message string
+}
+
+var (
+	yamlErrorPattern    = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	goTypePattern       = regexp.MustCompile(` (?:in|into) (?:type )?[\w.\[\]*]+$`)
+	unknownFieldPattern = regexp.MustCompile(`^field (\S+) not found$`)
+)
+
+// Parse reads and validates a configuration file. Invalid entries are reported as issues
+// and left out, so that a mistake in one of them does not disable the rest; a file that
+// is not valid YAML yields the default configuration. checks lists the known check names
+// and isLanguage tells the supported report languages.
+func Parse(content []byte, checks []string, isLanguage func(string) bool) (*Config, []Issue) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return &Config{}, yamlIssues(err)
+	}
+
+	config := &Config{}
+	var issues []Issue
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		issues = append(issues, yamlIssues(err)...)
+		// Type errors leave the other fields decoded
+		var typeError *yaml.TypeError
+		if !errors.As(err, &typeError) {
+			return &Config{}, issues
+		}
+	}
+
+	v := validator{root: &root, issues: issues}
+	if config.Language != "" && !isLanguage(config.Language) {
+		v.report(fmt.Sprintf("unsupported language %q", config.Language), "language")
+		config.Language = ""
+	}
+
+	known := make(map[string]bool, len(checks))
+	for _, check := range checks {
+		known[check] = true
+	}
+	config.Checks.Enable = v.filterNames(config.Checks.Enable, known, "checks", "enable")
+	config.Checks.Disable = v.filterNames(config.Checks.Disable, known, "checks", "disable")
+
+	config.Exclude = v.filterGlobs(config.Exclude, "exclude")
+	config.Layers.Application = v.filterGlobs(config.Layers.Application, "layers", "application")
+	config.Layers.Adapters = v.filterGlobs(config.Layers.Adapters, "layers", "adapters")
+	config.Layers.Other = v.filterGlobs(config.Layers.Other, "layers", "other")
+
+	for ruleName, severity := range config.Severity {
+		if normalized, ok := model.NormalizeSeverity(severity); ok {
+			config.Severity[ruleName] = normalized
+		} else {
+			v.report(fmt.Sprintf("unknown severity %q for %s, expected high, medium, low or info", severity, ruleName), "severity", ruleName)
+			delete(config.Severity, ruleName)
+		}
+	}
+
+	var suppressions []Suppression
+	for i, suppression := range config.Suppressions {
+		rule := model.SuppressionRule{
+			RuleName:       suppression.Rule,
+			PathGlob:       suppression.Path,
+			MessagePattern: suppression.Message,
+			Reason:         suppression.Reason,
+		}
+		if err := findings.ValidateRule(rule); err != nil {
+			v.report(err.Error(), "suppressions", i)
+			continue
+		}
+		if _, err := parseDate(suppression.Expires); err != nil {
+			v.report(fmt.Sprintf("invalid expiry date %q, expected YYYY-MM-DD", suppression.Expires), "suppressions", i, "expires")
+			continue
+		}
+		suppressions = append(suppressions, suppression)
+	}
+	config.Suppressions = suppressions
+
+	for name, policy := range config.Gates {
+		if !v.validPolicy(name, policy, known) {
+			delete(config.Gates, name)
+		}
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool { return v.issues[i].Line < v.issues[j].Line })
+	if err := config.compile(); err != nil {
+		v.report(err.Error())
+		return &Config{}, v.issues
+	}
+	return config, v.issues
+}
+
+// yamlIssues turns the errors of the YAML decoder into issues with their line
+func yamlIssues(err error) []Issue {
+	messages := []string{err.Error()}
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		messages = typeError.Errors
+	}
+
+	issues := make([]Issue, 0, len(messages))
+	for _, message := range messages {
+		issue := Issue{Message: strings.TrimPrefix(message, "yaml: ")}
+		if match := yamlErrorPattern.FindStringSubmatch(message); match != nil {
+			issue.Line, _ = strconv.Atoi(match[1])
+			issue.Message = match[2]
+		}
+		issue.Message = goTypePattern.ReplaceAllString(issue.Message, "")
+		issue.Message = unknownFieldPattern.ReplaceAllString(issue.Message, `unknown key "$1"`)
+		issues = append(issues, issue)
+	}
+	return issues
+}
+
+// validator collects issues, locating them in the YAML document
+type validator struct {
+	root   *yaml.Node
+	issues []Issue
+}
+
+// report records an issue at the node reached by the given mapping keys and sequence indexes
+func (v *validator) report(message string, location ...interface{}) {
+	v.issues = append(v.issues, Issue{Line: lineOf(v.root, location...), Message: message})
+}
+
+func (v *validator) filterNames(names []string, known map[string]bool, location ...interface{}) []string {
+	var valid []string
+	for i, name := range names {
+		if !known[name] {
+			v.report(fmt.Sprintf("unknown check %q", name), append(location, i)...)
+			continue
+		}
+		valid = append(valid, name)
+	}
+	return valid
+}
+
+// validPolicy reports the problems of a gate policy; a policy with any of them is left out
+// as a whole, since a gate that checks less than intended would pass wrongly
+func (v *validator) validPolicy(name string, policy quality.Policy, known map[string]bool) bool {
+	valid := true
+	if policy.Empty() {
+		v.report(fmt.Sprintf("gate %q has no conditions", name), "gates", name)
+		return false
+	}
+	for condition, limits := range map[string]map[string]int{"max_new": policy.MaxNew, "max_total": policy.MaxTotal} {
+		for severity, limit := range limits {
+			switch severity {
+			case model.SeverityHigh, model.SeverityMedium, model.SeverityLow, model.SeverityInfo:
+			default:
+				v.report(fmt.Sprintf("unknown severity %q in gate %q, expected high, medium, low or info", severity, name), "gates", name, condition, severity)
+				valid = false
+				continue
+			}
+			if limit < 0 {
+				v.report(fmt.Sprintf("negative finding limit %d in gate %q", limit, name), "gates", name, condition, severity)
+				valid = false
+			}
+		}
+	}
+	if policy.MinScore != nil && (*policy.MinScore < 0 || *policy.MinScore > 100) {
+		v.report(fmt.Sprintf("minimum score %v in gate %q is not between 0 and 100", *policy.MinScore, name), "gates", name, "min_score")
+		valid = false
+	}
+	for i, check := range policy.Compliant {
+		if !known[check] {
+			v.report(fmt.Sprintf("unknown check %q in gate %q", check, name), "gates", name, "compliant", i)
+			valid = false
+		}
+	}
+	return valid
+}
+
+func (v *validator) filterGlobs(patterns []string, location ...interface{}) []string {
+	var valid []string
+	for i, pattern := range patterns {
+		if _, err := utils.CompileGlob(pattern); err != nil || strings.TrimSpace(pattern) == "" {
+			v.report(fmt.Sprintf("invalid path glob %q", pattern), append(location, i)...)
+			continue
+		}
+		valid = append(valid, pattern)
+	}
+	return valid
+}
+
+// lineOf returns the line of the node reached by the location, or of the deepest node
+// found on the way
+func lineOf(root *yaml.Node, location ...interface{}) int {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, step := range location {
+		var next *yaml.Node
+		switch key := step.(type) {
+		case string:
+			if node.Kind == yaml.MappingNode {
+				for i := 0; i+1 < len(node.Content); i += 2 {
+					if node.Content[i].Value == key {
+						next = node.Content[i+1]
+						break
+					}
+				}
+			}
+		case int:
+			if node.Kind == yaml.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return node.Line
+}
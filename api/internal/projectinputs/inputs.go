@@ -0,0 +1,192 @@
+// internal/projectinputs/inputs.go
+
+package projectinputs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/discovery"
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/model"
+	"evraz_api/internal/prompts/prompts_storage/project_prompts"
+	"evraz_api/internal/prompts/types"
+)
+
+// maxCoverageFilesInPrompt limits the per-file coverage listed for the testing prompt
+const maxCoverageFilesInPrompt = 30
+
+// Summaries fits the project tree and file contents into a prompt. The analysis replaces
+// what does not fit with summaries; the prompt evaluation passes everything through.
+type Summaries interface {
+	Tree() string
+	Files(content string, files []model.ProjectFile) string
+	Contents(contents map[string]string, files []model.ProjectFile) map[string]string
+}
+
+// Builder builds the data of the project-level prompts from the files of a project, so
+// that the analysis and the prompt evaluation send the same inputs
+type Builder struct {
+	Files     []model.ProjectFile  // Files the checks look at, without the excluded ones
+	Uploaded  []model.ProjectFile  // Every file of the project; excluded key files are not missing
+	Coverage  []model.FileCoverage // Imported coverage; empty without a report
+	Summaries Summaries
+	Catalog   *i18n.Catalog
+	Language  string // Report language of the messages about missing inputs
+
+	suite *discovery.TestSuite
+	gaps  []discovery.MirroringGap
+}
+
+// Tests returns the test suite of the project and the source modules whose tests are
+// missing or do not mirror their location
+func (b *Builder) Tests() (discovery.TestSuite, []discovery.MirroringGap) {
+	if b.suite == nil {
+		suite := discovery.DiscoverTests(b.Files)
+		b.suite = &suite
+		b.gaps = discovery.CheckTestMirroring(b.Files, suite)
+	}
+	return *b.suite, b.gaps
+}
+
+// Data returns the data of a project-level prompt. When the project has none of the files
+// the prompt needs, missing describes them; the analysis records it as the verdict of the
+// check instead of calling the LLM.
+func (b *Builder) Data(promptName string) (data types.PromptData, missing string, err error) {
+	language := b.Language
+	switch promptName {
+	case "ProjectStructure":
+		return project_prompts.ProjectStructureData{ProjectTree: b.Summaries.Tree()}, "", nil
+
+	case "KeyFiles":
+		setupFiles := []string{"setup.py", "setup.cfg", "pyproject.toml", "README.md"}
+		candidates := discovery.Rank(b.Files, discovery.TopicSetupFiles, 4)
+		if len(candidates) == 0 {
+			missing = b.Catalog.T(language, "analysis.files_missing", b.Catalog.JoinList(language, setupFiles))
+		}
+		// Absent key files are listed too, so that the check can report them
+		setupFilesContent := b.Summaries.Contents(discovery.Contents(candidates), discovery.Files(candidates))
+		for _, name := range discovery.MissingFiles(b.Uploaded, setupFiles) {
+			setupFilesContent[name] = name + " is missing at the root of the project directory"
+		}
+		return project_prompts.KeyFilesData{SetupFilesContent: setupFilesContent}, missing, nil
+
+	case "ApplicationArchitecture":
+		return project_prompts.ApplicationArchitectureData{ProjectStructure: b.Summaries.Tree()}, "", nil
+
+	case "DependencyManagement":
+		candidates := discovery.Rank(b.Files, discovery.TopicDependencies, 2)
+		if len(candidates) == 0 {
+			missing = b.Catalog.T(language, "analysis.file_missing", "requirements.txt")
+		}
+		return project_prompts.DependencyManagementData{
+			DependenciesContent: b.Summaries.Files(discovery.Concat(candidates), discovery.Files(candidates)),
+		}, missing, nil
+
+	case "ProjectSettings":
+		settingsFiles := []string{"settings.py", "config.yaml", "pyproject.toml"}
+		candidates := discovery.Rank(b.Files, discovery.TopicSettings, 3)
+		if len(candidates) == 0 {
+			missing = b.Catalog.T(language, "analysis.files_missing", b.Catalog.JoinList(language, settingsFiles))
+		}
+		return project_prompts.ProjectSettingsData{
+			SettingsFilesContent: b.Summaries.Contents(discovery.Contents(candidates), discovery.Files(candidates)),
+		}, missing, nil
+
+	case "TestingStrategy":
+		suite, gaps := b.Tests()
+		return project_prompts.TestingStrategyData{
+			ProjectTree:       b.Summaries.Tree(),
+			TestsFilesContent: b.Summaries.Files(suite.Concat(b.Files), suite.Files(b.Files)),
+			TestSuiteSummary:  suite.Summary(),
+			MirroringGaps:     formatMirroringGaps(gaps),
+			Coverage:          formatCoverage(b.Files, b.Coverage),
+		}, "", nil
+
+	case "AdditionalTechnical":
+		transactionCandidates := discovery.Rank(b.Files, discovery.TopicTransactions, 3)
+		asyncCandidates := discovery.Rank(b.Files, discovery.TopicAsync, 3)
+
+		transactionCode := b.Summaries.Files(discovery.Concat(transactionCandidates), discovery.Files(transactionCandidates))
+		if len(transactionCandidates) == 0 {
+			transactionCode = b.Catalog.T(language, "analysis.no_relevant_code", b.Catalog.T(language, "topic.transactions"))
+		}
+		asyncCode := b.Summaries.Files(discovery.Concat(asyncCandidates), discovery.Files(asyncCandidates))
+		if len(asyncCandidates) == 0 {
+			asyncCode = b.Catalog.T(language, "analysis.no_relevant_code", b.Catalog.T(language, "topic.async"))
+		}
+		if len(transactionCandidates) == 0 && len(asyncCandidates) == 0 {
+			missing = b.Catalog.T(language, "analysis.no_relevant_code", b.Catalog.JoinList(language, []string{
+				b.Catalog.T(language, "topic.transactions"),
+				b.Catalog.T(language, "topic.async"),
+			}))
+		}
+		return project_prompts.AdditionalTechnicalData{
+			TransactionManagementCode: transactionCode,
+			AsynchronousCodeUsage:     asyncCode,
+		}, missing, nil
+
+	case "DateTimeHandling":
+		candidates := discovery.Rank(b.Files, discovery.TopicDateTime, 3)
+		if len(candidates) == 0 {
+			missing = b.Catalog.T(language, "analysis.no_relevant_code", b.Catalog.T(language, "topic.datetime"))
+		}
+		return project_prompts.DateTimeHandlingData{
+			DateTimeCodeSamples: b.Summaries.Files(discovery.Concat(candidates), discovery.Files(candidates)),
+		}, missing, nil
+
+	default:
+		return nil, "", fmt.Errorf("%s is not a project-level prompt", promptName)
+	}
+}
+
+// formatMirroringGaps lists the gaps for the testing prompt
+func formatMirroringGaps(gaps []discovery.MirroringGap) string {
+	if len(gaps) == 0 {
+		return "Every source module has a corresponding test file in a mirrored location"
+	}
+
+	var sb strings.Builder
+	for _, gap := range gaps {
+		if gap.TestPath == "" {
+			sb.WriteString(fmt.Sprintf("- %s: no tests\n", gap.Module.Path))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s: tested in %s, which does not mirror the module's location\n", gap.Module.Path, gap.TestPath))
+		}
+	}
+	return sb.String()
+}
+
+// formatCoverage describes the coverage for the testing prompt, least covered files first
+func formatCoverage(files []model.ProjectFile, coverage []model.FileCoverage) string {
+	if len(coverage) == 0 {
+		return "No coverage report was provided"
+	}
+
+	paths := make(map[uint]string, len(files))
+	for _, file := range files {
+		paths[file.ID] = file.Path
+	}
+
+	sorted := append([]model.FileCoverage(nil), coverage...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].LineRate() < sorted[j].LineRate() })
+
+	total := model.TotalCoverage(coverage)
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Total: lines %.1f%% (%d/%d), branches %.1f%% (%d/%d), %d files\n",
+		total.LineRate(), total.LinesCovered, total.LinesValid,
+		total.BranchRate(), total.BranchesCovered, total.BranchesValid, len(coverage)))
+	for i, c := range sorted {
+		if i == maxCoverageFilesInPrompt {
+			sb.WriteString(fmt.Sprintf("... %d more files\n", len(sorted)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("- %s: lines %.1f%%, branches %.1f%%", paths[c.ProjectFileID], c.LineRate(), c.BranchRate()))
+		if c.MissingLines != "" {
+			sb.WriteString(", missing lines: " + c.MissingLines)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,136 @@
+// internal/prompteval/corpus.go
+
+package prompteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+const (
+	SampleKindFile    = "file"
+	SampleKindProject = "project"
+)
+
+// Expectation is the label of a single sample for a single prompt
+type Expectation struct {
+	Compliant  bool     `json:"compliant"`
+	Categories []string `json:"categories"`
+}
+
+// Sample is a labelled Python file or project directory
+type Sample struct {
+	ID           string                 `json:"id"`
+	Kind         string                 `json:"kind"`
+	Path         string                 `json:"path"`
+	Expectations map[string]Expectation `json:"expectations"` // Key: prompt name
+}
+
+// Corpus is the set of labelled samples together with the issue category vocabulary
+type Corpus struct {
+	// Categories maps an issue category to keywords that identify it in an issue text
+	Categories map[string][]string `json:"categories"`
+	Samples    []Sample            `json:"samples"`
+
+	root string
+}
+
+// LoadCorpus reads a corpus manifest; sample paths are relative to the manifest directory
+func LoadCorpus(path string) (*Corpus, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corpus manifest: %w", err)
+	}
+
+	var corpus Corpus
+	if err := json.Unmarshal(raw, &corpus); err != nil {
+		return nil, fmt.Errorf("failed to parse corpus manifest: %w", err)
+	}
+	corpus.root = filepath.Dir(path)
+
+	for _, sample := range corpus.Samples {
+		if sample.Kind != SampleKindFile && sample.Kind != SampleKindProject {
+			return nil, fmt.Errorf("sample %s: unknown kind %q", sample.ID, sample.Kind)
+		}
+		for promptName, expectation := range sample.Expectations {
+			for _, category := range expectation.Categories {
+				if _, ok := corpus.Categories[category]; !ok {
+					return nil, fmt.Errorf("sample %s, prompt %s: unknown category %q", sample.ID, promptName, category)
+				}
+			}
+		}
+	}
+
+	return &corpus, nil
+}
+
+// SamplePath returns the absolute location of a sample on disk
+func (c *Corpus) SamplePath(sample Sample) string {
+	return filepath.Join(c.root, sample.Path)
+}
+
+// Classify maps the free-text issues returned by the model to corpus categories
+func (c *Corpus) Classify(issues []string) []string {
+	text := strings.ToLower(strings.Join(issues, "\n"))
+
+	var categories []string
+	for category, keywords := range c.Categories {
+		for _, keyword := range keywords {
+			if strings.Contains(text, strings.ToLower(keyword)) {
+				categories = append(categories, category)
+				break
+			}
+		}
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+// readProject loads every file below dir as the files of a project and renders a tree
+// listing for it
+func readProject(dir string) (string, []model.ProjectFile, error) {
+	var files []model.ProjectFile
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		files = append(files, model.ProjectFile{
+			Path:    relPath,
+			Name:    filepath.Base(relPath),
+			Content: string(content),
+		})
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	var tree strings.Builder
+	tree.WriteString(".\n")
+	for i := range files {
+		files[i].ID = uint(i + 1)
+		depth := strings.Count(files[i].Path, "/")
+		tree.WriteString(strings.Repeat("│   ", depth) + "├── " + files[i].Name + "\n")
+	}
+
+	return tree.String(), files, nil
+}
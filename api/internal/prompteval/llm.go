@@ -0,0 +1,125 @@
+// internal/prompteval/llm.go
+
+package prompteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/service"
+)
+
+// Reply is a model answer together with its token usage
+type Reply struct {
+	Reply            string `json:"reply"`
+	PromptTokens     int    `json:"prompt_tokens"`
+	CompletionTokens int    `json:"completion_tokens"`
+	TotalTokens      int    `json:"total_tokens"`
+}
+
+// LLM answers an evaluation prompt; key identifies the version/prompt/sample combination
+type LLM interface {
+	Complete(key, prompt string) (Reply, error)
+}
+
+// memoryGPTCallRepository keeps GPT calls in memory so the evaluation does not need a database
+type memoryGPTCallRepository struct {
+	mu    sync.Mutex
+	calls []model.GPTCall
+}
+
+func (repo *memoryGPTCallRepository) CreateOne(gptCall *model.GPTCall) (uint, error) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	repo.calls = append(repo.calls, *gptCall)
+	gptCall.ID = uint(len(repo.calls))
+	return gptCall.ID, nil
+}
+
+func (repo *memoryGPTCallRepository) get(id uint) (model.GPTCall, bool) {
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+
+	if id == 0 || int(id) > len(repo.calls) {
+		return model.GPTCall{}, false
+	}
+	return repo.calls[id-1], true
+}
+
+// LiveLLM calls the configured Mistral endpoint and optionally records the replies
+type LiveLLM struct {
+	service  *service.MistralService
+	calls    *memoryGPTCallRepository
+	mu       sync.Mutex
+	recorded map[string]Reply
+}
+
+func NewLiveLLM() *LiveLLM {
+	calls := &memoryGPTCallRepository{}
+	return &LiveLLM{
+		service:  service.NewMistralService(calls),
+		calls:    calls,
+		recorded: make(map[string]Reply),
+	}
+}
+
+func (l *LiveLLM) Complete(key, prompt string) (Reply, error) {
+	content, gptCallID, err := l.service.CallMistral(prompt, false, service.Hack, "prompteval", 0)
+	if err != nil {
+		return Reply{}, err
+	}
+
+	reply := Reply{Reply: content}
+	if call, ok := l.calls.get(gptCallID); ok {
+		reply.PromptTokens = call.PromptTokens
+		reply.CompletionTokens = call.CompletionTokens
+		reply.TotalTokens = call.TotalTokens
+	}
+
+	l.mu.Lock()
+	l.recorded[key] = reply
+	l.mu.Unlock()
+	return reply, nil
+}
+
+// SaveRecording writes all replies received so far in the format read by LoadReplayLLM
+func (l *LiveLLM) SaveRecording(path string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	raw, err := json.MarshalIndent(l.recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, raw, 0644)
+}
+
+// ReplayLLM answers from previously recorded replies
+type ReplayLLM struct {
+	replies map[string]Reply
+}
+
+func LoadReplayLLM(path string) (*ReplayLLM, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded replies: %w", err)
+	}
+
+	replies := make(map[string]Reply)
+	if err := json.Unmarshal(raw, &replies); err != nil {
+		return nil, fmt.Errorf("failed to parse recorded replies: %w", err)
+	}
+	return &ReplayLLM{replies: replies}, nil
+}
+
+func (r *ReplayLLM) Complete(key, prompt string) (Reply, error) {
+	reply, ok := r.replies[key]
+	if !ok {
+		return Reply{}, fmt.Errorf("no recorded reply for %s", key)
+	}
+	return reply, nil
+}
@@ -0,0 +1,152 @@
+// internal/prompteval/report.go
+
+package prompteval
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// PromptMetrics aggregates the results of one prompt over the corpus.
+// Non-compliance is the positive class for the compliance counters.
+type PromptMetrics struct {
+	Prompt        string `json:"prompt"`
+	Runs          int    `json:"runs"`
+	ParseFailures int    `json:"parse_failures"`
+
+	ComplianceTP int `json:"compliance_tp"`
+	ComplianceFP int `json:"compliance_fp"`
+	ComplianceFN int `json:"compliance_fn"`
+
+	CategoryTP int `json:"category_tp"`
+	CategoryFP int `json:"category_fp"`
+	CategoryFN int `json:"category_fn"`
+
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// score compares a parsed reply with the sample's expectation
+func (m *PromptMetrics) score(expectation Expectation, compliance bool, categories []string) {
+	switch {
+	case !compliance && !expectation.Compliant:
+		m.ComplianceTP++
+	case !compliance && expectation.Compliant:
+		m.ComplianceFP++
+	case compliance && !expectation.Compliant:
+		m.ComplianceFN++
+	}
+
+	expected := make(map[string]bool, len(expectation.Categories))
+	for _, category := range expectation.Categories {
+		expected[category] = true
+	}
+	for _, category := range categories {
+		if expected[category] {
+			m.CategoryTP++
+			delete(expected, category)
+		} else {
+			m.CategoryFP++
+		}
+	}
+	m.CategoryFN += len(expected)
+}
+
+func ratio(numerator, denominator int) float64 {
+	if denominator == 0 {
+		return 0
+	}
+	return float64(numerator) / float64(denominator)
+}
+
+func (m PromptMetrics) CompliancePrecision() float64 {
+	return ratio(m.ComplianceTP, m.ComplianceTP+m.ComplianceFP)
+}
+
+func (m PromptMetrics) ComplianceRecall() float64 {
+	return ratio(m.ComplianceTP, m.ComplianceTP+m.ComplianceFN)
+}
+
+func (m PromptMetrics) CategoryPrecision() float64 {
+	return ratio(m.CategoryTP, m.CategoryTP+m.CategoryFP)
+}
+
+func (m PromptMetrics) CategoryRecall() float64 {
+	return ratio(m.CategoryTP, m.CategoryTP+m.CategoryFN)
+}
+
+func (m PromptMetrics) ParseFailureRate() float64 {
+	return ratio(m.ParseFailures, m.Runs)
+}
+
+// VersionReport holds the metrics of every evaluated prompt for one version
+type VersionReport struct {
+	Version string          `json:"version"`
+	Prompts []PromptMetrics `json:"prompts"`
+}
+
+func (r *VersionReport) find(promptName string) (PromptMetrics, bool) {
+	for _, m := range r.Prompts {
+		if m.Prompt == promptName {
+			return m, true
+		}
+	}
+	return PromptMetrics{}, false
+}
+
+// WriteReport prints a per-prompt table for a single version
+func WriteReport(w io.Writer, report *VersionReport, pricePer1K float64) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Version: %s\n", report.Version)
+	fmt.Fprintln(tw, "PROMPT\tRUNS\tCOMPL P\tCOMPL R\tCAT P\tCAT R\tPARSE FAIL\tTOKENS\tCOST")
+	for _, m := range report.Prompts {
+		fmt.Fprintf(tw, "%s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\t%.0f%%\t%d\t%.4f\n",
+			m.Prompt, m.Runs,
+			m.CompliancePrecision(), m.ComplianceRecall(),
+			m.CategoryPrecision(), m.CategoryRecall(),
+			m.ParseFailureRate()*100, m.TotalTokens,
+			float64(m.TotalTokens)/1000*pricePer1K)
+	}
+	tw.Flush()
+}
+
+// WriteComparison prints two versions side by side with the delta of each metric (b - a)
+func WriteComparison(w io.Writer, a, b *VersionReport, pricePer1K float64) {
+	promptNames := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, report := range []*VersionReport{a, b} {
+		for _, m := range report.Prompts {
+			if !seen[m.Prompt] {
+				seen[m.Prompt] = true
+				promptNames = append(promptNames, m.Prompt)
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "A: %s\tB: %s\n", a.Version, b.Version)
+	fmt.Fprintln(tw, "PROMPT\tMETRIC\tA\tB\tDELTA")
+	for _, promptName := range promptNames {
+		ma, _ := a.find(promptName)
+		mb, _ := b.find(promptName)
+
+		rows := []struct {
+			name string
+			a, b float64
+		}{
+			{"compliance precision", ma.CompliancePrecision(), mb.CompliancePrecision()},
+			{"compliance recall", ma.ComplianceRecall(), mb.ComplianceRecall()},
+			{"category precision", ma.CategoryPrecision(), mb.CategoryPrecision()},
+			{"category recall", ma.CategoryRecall(), mb.CategoryRecall()},
+			{"parse failure rate", ma.ParseFailureRate(), mb.ParseFailureRate()},
+			{"total tokens", float64(ma.TotalTokens), float64(mb.TotalTokens)},
+			{"cost", float64(ma.TotalTokens) / 1000 * pricePer1K, float64(mb.TotalTokens) / 1000 * pricePer1K},
+		}
+		for _, row := range rows {
+			fmt.Fprintf(tw, "%s\t%s\t%.4f\t%.4f\t%+.4f\n", promptName, row.name, row.a, row.b, row.b-row.a)
+		}
+	}
+	tw.Flush()
+}
@@ -0,0 +1,203 @@
+// internal/prompteval/runner.go
+
+package prompteval
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"evraz_api/internal/dto/llm_responses"
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/model"
+	"evraz_api/internal/projectinputs"
+	"evraz_api/internal/prompts"
+	"evraz_api/internal/prompts/prompts_storage/file_prompts"
+	"evraz_api/internal/prompts/types"
+	"evraz_api/internal/utils"
+)
+
+// Runner evaluates prompt versions against a corpus
+type Runner struct {
+	Corpus            *Corpus
+	Prompts           *prompts.Prompts
+	PromptConstructor *prompts.PromptConstructor
+	LLM               LLM
+	Catalog           *i18n.Catalog
+	Language          string // Language code such as ru, or the language name passed to prompts
+}
+
+// reportLanguages are the languages whose messages project samples can be given
+var reportLanguages = []string{"ru", "en"}
+
+func NewRunner(corpus *Corpus, llm LLM, language string) *Runner {
+	return &Runner{
+		Corpus:            corpus,
+		Prompts:           prompts.NewPrompts(),
+		PromptConstructor: prompts.NewPromptConstructor(),
+		LLM:               llm,
+		Catalog:           i18n.NewCatalog(reportLanguages, reportLanguages[0]),
+		Language:          language,
+	}
+}
+
+// ReplyKey identifies a recorded reply
+func ReplyKey(versionName, promptName, sampleID string) string {
+	return versionName + "/" + promptName + "/" + sampleID
+}
+
+// Run evaluates every labelled prompt/sample pair with the given version
+func (r *Runner) Run(version *Version) (*VersionReport, error) {
+	metrics := make(map[string]*PromptMetrics)
+	llmLanguage, reportLanguage := r.languages()
+
+	for _, sample := range r.Corpus.Samples {
+		var inputs *projectinputs.Builder
+		if sample.Kind == SampleKindProject {
+			tree, files, err := readProject(r.Corpus.SamplePath(sample))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read project sample %s: %w", sample.ID, err)
+			}
+			inputs = &projectinputs.Builder{
+				Files:     files,
+				Uploaded:  files,
+				Summaries: fullContents{tree: tree},
+				Catalog:   r.Catalog,
+				Language:  reportLanguage,
+			}
+		}
+
+		promptNames := make([]string, 0, len(sample.Expectations))
+		for promptName := range sample.Expectations {
+			promptNames = append(promptNames, promptName)
+		}
+		sort.Strings(promptNames)
+
+		for _, promptName := range promptNames {
+			expectation := sample.Expectations[promptName]
+
+			prompt, ok := r.Prompts.ByName(promptName)
+			if !ok {
+				return nil, fmt.Errorf("sample %s: unknown prompt %s", sample.ID, promptName)
+			}
+			prompt = version.Apply(promptName, prompt)
+
+			var data types.PromptData
+			var missing string
+			var err error
+			if sample.Kind == SampleKindFile {
+				data, err = r.fileData(promptName, sample)
+			} else {
+				data, missing, err = inputs.Data(promptName)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("sample %s: %w", sample.ID, err)
+			}
+
+			m, ok := metrics[promptName]
+			if !ok {
+				m = &PromptMetrics{Prompt: promptName}
+				metrics[promptName] = m
+			}
+
+			// Like the analysis, a project without the inputs of a check fails it without
+			// calling the LLM
+			if missing != "" {
+				m.Runs++
+				m.score(expectation, false, r.Corpus.Classify([]string{missing}))
+				continue
+			}
+
+			finalPrompt, err := r.PromptConstructor.GetPrompt(prompt, data, llmLanguage, true)
+			if err != nil {
+				return nil, fmt.Errorf("failed to construct prompt %s for %s: %w", promptName, sample.ID, err)
+			}
+
+			reply, err := r.LLM.Complete(ReplyKey(version.Name, promptName, sample.ID), finalPrompt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get reply for %s on %s: %w", promptName, sample.ID, err)
+			}
+
+			m.Runs++
+			m.PromptTokens += reply.PromptTokens
+			m.CompletionTokens += reply.CompletionTokens
+			m.TotalTokens += reply.TotalTokens
+
+			var analysisDTO llm_responses.FileAnalysisResponse
+			if err := utils.ExtractJSON(reply.Reply, &analysisDTO); err != nil {
+				m.ParseFailures++
+				continue
+			}
+			m.score(expectation, analysisDTO.Compliance, r.Corpus.Classify(analysisDTO.Issues))
+		}
+	}
+
+	report := &VersionReport{Version: version.Name}
+	for _, m := range metrics {
+		report.Prompts = append(report.Prompts, *m)
+	}
+	sort.Slice(report.Prompts, func(i, j int) bool {
+		return report.Prompts[i].Prompt < report.Prompts[j].Prompt
+	})
+	return report, nil
+}
+
+// languages returns the language passed to prompts and the language of the messages about
+// missing project inputs
+func (r *Runner) languages() (llmLanguage, reportLanguage string) {
+	if r.Catalog.IsSupported(r.Language) {
+		return r.Catalog.LLMLanguage(r.Language), r.Catalog.Resolve(r.Language)
+	}
+	for _, language := range reportLanguages {
+		if r.Catalog.LLMLanguage(language) == r.Language {
+			return r.Language, language
+		}
+	}
+	return r.Language, r.Catalog.DefaultLanguage()
+}
+
+// fileData builds the data of a file-level prompt from a single file sample
+func (r *Runner) fileData(promptName string, sample Sample) (types.PromptData, error) {
+	content, err := os.ReadFile(r.Corpus.SamplePath(sample))
+	if err != nil {
+		return nil, err
+	}
+	filePath := filepath.ToSlash(sample.Path)
+	fileContent := string(content)
+
+	switch promptName {
+	case "ApplicationLayerCode":
+		return file_prompts.ApplicationLayerCodeData{FilePath: filePath, FileContent: fileContent}, nil
+	case "AdaptersLayerCode":
+		return file_prompts.AdaptersLayerCodeData{FilePath: filePath, FileContent: fileContent}, nil
+	case "CodingStandards":
+		return file_prompts.CodingStandardsData{FilePath: filePath, FileContent: fileContent}, nil
+	case "ErrorHandlingAndLogging":
+		return file_prompts.ErrorHandlingAndLoggingData{FilePath: filePath, FileContent: fileContent}, nil
+	case "AdditionalTechnicalFile":
+		return file_prompts.AdditionalTechnicalFileData{FilePath: filePath, FileContent: fileContent}, nil
+	case "DateTimeHandlingFile":
+		return file_prompts.DateTimeHandlingFileData{FilePath: filePath, FileContent: fileContent}, nil
+	default:
+		return nil, fmt.Errorf("%s is not a file-level prompt", promptName)
+	}
+}
+
+// fullContents passes the tree and file contents of a project sample through unchanged;
+// samples are small enough to fit in a prompt
+type fullContents struct {
+	tree string
+}
+
+func (c fullContents) Tree() string {
+	return c.tree
+}
+
+func (c fullContents) Files(content string, files []model.ProjectFile) string {
+	return content
+}
+
+func (c fullContents) Contents(contents map[string]string, files []model.ProjectFile) map[string]string {
+	return contents
+}
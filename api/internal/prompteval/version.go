@@ -0,0 +1,66 @@
+// internal/prompteval/version.go
+
+package prompteval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"evraz_api/internal/prompts/types"
+)
+
+// CurrentVersionName names the prompts as they are compiled into the service
+const CurrentVersionName = "current"
+
+// PromptOverride replaces parts of a prompt; empty fields keep the compiled-in value
+type PromptOverride struct {
+	BasePrompt   string `json:"base_prompt"`
+	BaseTaskDesc string `json:"base_task_desc"`
+	Template     string `json:"template"`
+}
+
+// Version is a named set of prompt rewordings to evaluate
+type Version struct {
+	Name    string                    `json:"name"`
+	Prompts map[string]PromptOverride `json:"prompts"` // Key: prompt name
+}
+
+// LoadVersion reads a version file; an empty path means the compiled-in prompts
+func LoadVersion(path string) (*Version, error) {
+	if path == "" {
+		return &Version{Name: CurrentVersionName}, nil
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt version: %w", err)
+	}
+
+	var version Version
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return nil, fmt.Errorf("failed to parse prompt version: %w", err)
+	}
+	if version.Name == "" {
+		version.Name = path
+	}
+	return &version, nil
+}
+
+// Apply returns the prompt with this version's overrides applied
+func (v *Version) Apply(name string, prompt types.Prompt) types.Prompt {
+	override, ok := v.Prompts[name]
+	if !ok {
+		return prompt
+	}
+	if override.BasePrompt != "" {
+		prompt.BasePrompt = override.BasePrompt
+	}
+	if override.BaseTaskDesc != "" {
+		prompt.BaseTaskDesc = override.BaseTaskDesc
+	}
+	if override.Template != "" {
+		prompt.Template = override.Template
+	}
+	return prompt
+}
@@ -3,16 +3,54 @@
 package prompts
 
 import (
+	"bytes"
+	"encoding/json"
 	"evraz_api/internal/prompts/types"
 	"fmt"
+	"strings"
+	"sync"
+	"text/template"
 )
 
+// DefaultTemplate reproduces the original prompt layout: language line, base prompt,
+// the list of passed data, task description, data contents, the language line again
+// and the JSON keys (followed by an example when the prompt defines one).
+const DefaultTemplate = `{{- if .Language}}Fully respond in {{.Language}}.
+{{end}}{{.BasePrompt}}
+
+You will receive:
+{{range .PassedData}}{{.Name}} - {{.Description}}
+{{end}}{{.BaseTaskDesc}}
+
+{{range .PassedData}}{{.Name}} - {{.Content}}
+
+{{end}}{{if and .Language .RepeatLanguage}}Fully respond in {{.Language}}.
+{{end}}{{if .JSONStruct}}Your response should be a structured JSON with the following keys:
+{{range .JSONStruct}}{{.Key}}: {{.Description}}
+{{end}}{{jsonExample .JSONStruct}}{{end}}`
+
+// TemplateData is the value passed to a prompt template
+type TemplateData struct {
+	Language       string
+	RepeatLanguage bool
+	BasePrompt     string
+	BaseTaskDesc   string
+	PassedData     []types.PassedData
+	JSONStruct     []types.JSONStruct
+	Data           types.PromptData // Typed prompt data, e.g. file_prompts.CodingStandardsData
+}
+
 // PromptConstructor is used to construct prompts with specific content
-type PromptConstructor struct{}
+type PromptConstructor struct {
+	mu        sync.Mutex
+	templates map[string]*template.Template
+}
 
 // NewPromptConstructor initializes and returns a PromptConstructor
 func NewPromptConstructor() *PromptConstructor {
-	return &PromptConstructor{}
+	return &PromptConstructor{
+		templates: make(map[string]*template.Template),
+	}
 }
 
 // PromptData is an interface that defines a method to transform data into PassedData
@@ -20,48 +58,126 @@ type PromptData interface {
 	ToPassedData() []types.PassedData
 }
 
+// TemplateFuncs returns the helper functions available inside prompt templates
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"truncate":    truncate,
+		"numberLines": numberLines,
+		"codeFence":   codeFence,
+		"jsonExample": jsonExample,
+	}
+}
+
 // GetPrompt constructs a prompt based on the provided prompt project and data
 func (pc *PromptConstructor) GetPrompt(prompt types.Prompt, data types.PromptData, language string, repeatLanguage bool) (string, error) {
 	prompt.Language = language
-	passedData := data.ToPassedData()
-
-	// Construct the list of passed data
-	passedDataList := "You will receive:\n"
-	passedDataContentStr := ""
-	for _, data := range passedData {
-		passedDataList += fmt.Sprintf("%s - %s\n", data.Name, data.Description)
-		passedDataContentStr += fmt.Sprintf("%s - %s\n\n", data.Name, data.Content)
+
+	tmplText := prompt.Template
+	if tmplText == "" {
+		tmplText = DefaultTemplate
 	}
 
-	// Construct the language instruction if applicable
-	languageInstruction := ""
-	if prompt.Language != "" {
-		languageInstruction = fmt.Sprintf("Fully respond in %s.\n", prompt.Language)
+	tmpl, err := pc.parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
 	}
 
-	// Construct the JSON instruction if applicable
-	jsonInstruction := ""
-	if len(prompt.JSONStruct) > 0 {
-		jsonInstruction = "Your response should be a structured JSON with the following keys:\n"
-		for _, js := range prompt.JSONStruct {
-			jsonInstruction += fmt.Sprintf("%s: %s\n", js.Key, js.Description)
-		}
+	templateData := TemplateData{
+		Language:       prompt.Language,
+		RepeatLanguage: repeatLanguage,
+		BasePrompt:     prompt.BasePrompt,
+		BaseTaskDesc:   prompt.BaseTaskDesc,
+		PassedData:     data.ToPassedData(),
+		JSONStruct:     prompt.JSONStruct,
+		Data:           data,
 	}
 
-	// Construct the final prompt with optional language instruction
-	finalPrompt := ""
-	if languageInstruction != "" {
-		finalPrompt = languageInstruction // Always include at the start
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
 	}
 
-	finalPrompt += prompt.BasePrompt + "\n\n" + passedDataList + prompt.BaseTaskDesc + "\n\n" + passedDataContentStr
+	return buf.String(), nil
+}
 
-	if languageInstruction != "" && repeatLanguage {
-		finalPrompt += languageInstruction // Add again at the end if repeatLanguage is true
+// parse returns a cached template for the given text, parsing it on first use
+func (pc *PromptConstructor) parse(text string) (*template.Template, error) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if tmpl, ok := pc.templates[text]; ok {
+		return tmpl, nil
 	}
 
-	// Append JSON structure instruction if available
-	finalPrompt += jsonInstruction
+	tmpl, err := template.New("prompt").Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	pc.templates[text] = tmpl
+	return tmpl, nil
+}
 
-	return finalPrompt, nil
+// truncate shortens s to at most n runes, marking the cut
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "\n... (truncated)"
+}
+
+// numberLines prefixes every line of s with its 1-based line number
+func numberLines(s string) string {
+	lines := strings.Split(s, "\n")
+	width := len(fmt.Sprintf("%d", len(lines)))
+
+	var sb strings.Builder
+	for i, line := range lines {
+		sb.WriteString(fmt.Sprintf("%*d | %s", width, i+1, line))
+		if i < len(lines)-1 {
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// codeFence wraps s in a markdown code block for the given language
+func codeFence(language, s string) string {
+	return "```" + language + "\n" + strings.TrimRight(s, "\n") + "\n```"
+}
+
+// jsonExample renders an example response object from the JSONStruct examples.
+// It returns an empty string when none of the keys define an example.
+func jsonExample(structs []types.JSONStruct) string {
+	hasExample := false
+	for _, js := range structs {
+		if js.Example != "" {
+			hasExample = true
+			break
+		}
+	}
+	if !hasExample {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Example:\n{\n")
+	written := 0
+	for _, js := range structs {
+		if js.Example == "" {
+			continue
+		}
+		value := js.Example
+		if !json.Valid([]byte(value)) {
+			quoted, _ := json.Marshal(value)
+			value = string(quoted)
+		}
+		if written > 0 {
+			sb.WriteString(",\n")
+		}
+		sb.WriteString(fmt.Sprintf("  %q: %s", js.Key, value))
+		written++
+	}
+	sb.WriteString("\n}\n")
+	return sb.String()
 }
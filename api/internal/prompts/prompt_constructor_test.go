@@ -0,0 +1,119 @@
+// internal/prompts/prompt_constructor_test.go
+
+package prompts
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"evraz_api/internal/prompts/prompts_storage/file_prompts"
+	"evraz_api/internal/prompts/prompts_storage/project_prompts"
+	"evraz_api/internal/prompts/types"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the rendered prompts")
+
+// languages are the response languages the service passes to prompts
+var languages = map[string]string{
+	"ru": "Russian (русский)",
+	"en": "English",
+}
+
+const sampleFile = `from datetime import datetime
+
+
+def now():
+    return datetime.now()
+`
+
+func TestGetPromptDefaultLayout(t *testing.T) {
+	cases := []struct {
+		name   string
+		prompt types.Prompt
+		data   types.PromptData
+	}{
+		{
+			name:   "project_dependency_management",
+			prompt: project_prompts.DependencyManagementPrompt,
+			data:   project_prompts.DependencyManagementData{DependenciesContent: "fastapi==0.110.0\nevraz-classic-app-layer==1.2.0"},
+		},
+		{
+			name:   "file_coding_standards",
+			prompt: file_prompts.CodingStandardsPrompt,
+			data:   file_prompts.CodingStandardsData{FilePath: "app/clock.py", FileContent: sampleFile},
+		},
+	}
+
+	constructor := NewPromptConstructor()
+	for _, tc := range cases {
+		for code, language := range languages {
+			t.Run(tc.name+"_"+code, func(t *testing.T) {
+				rendered, err := constructor.GetPrompt(tc.prompt, tc.data, language, true)
+				if err != nil {
+					t.Fatalf("GetPrompt: %v", err)
+				}
+				checkGolden(t, tc.name+"_"+code, rendered)
+			})
+		}
+	}
+}
+
+func TestGetPromptTemplateOverride(t *testing.T) {
+	prompt := file_prompts.CodingStandardsPrompt
+	prompt.Template = `{{.BasePrompt}}
+Respond in {{.Language}}.
+
+{{with .Data}}{{.FilePath}}:
+{{codeFence "python" (numberLines .FileContent)}}
+{{end}}
+{{truncate 40 .BaseTaskDesc}}
+`
+	data := file_prompts.CodingStandardsData{FilePath: "app/clock.py", FileContent: sampleFile}
+
+	rendered, err := NewPromptConstructor().GetPrompt(prompt, data, languages["en"], false)
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	checkGolden(t, "template_override", rendered)
+}
+
+func TestGetPromptJSONExample(t *testing.T) {
+	prompt := types.Prompt{
+		BasePrompt:   "Review the file.",
+		BaseTaskDesc: "List the problems of the file.",
+		JSONStruct: []types.JSONStruct{
+			{Key: "compliance", Description: "(bool) Whether the file meets the requirements", Example: "false"},
+			{Key: "issues", Description: "(list of str) Problems found", Example: `["Uses naive datetime.now()"]`},
+			{Key: "summary", Description: "(str) One sentence summary", Example: "Times are not timezone-aware"},
+			{Key: "recommendations", Description: "(list of str) Suggestions for improvement"},
+		},
+	}
+	data := file_prompts.CodingStandardsData{FilePath: "app/clock.py", FileContent: sampleFile}
+
+	rendered, err := NewPromptConstructor().GetPrompt(prompt, data, languages["ru"], false)
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	checkGolden(t, "json_example", rendered)
+}
+
+// checkGolden compares the rendered prompt with testdata/<name>.golden; -update rewrites it
+func checkGolden(t *testing.T, name, rendered string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(rendered), 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s (run with -update to create it): %v", path, err)
+	}
+	if rendered != string(want) {
+		t.Errorf("rendered prompt differs from %s (run with -update to accept it)\n--- got ---\n%s\n--- want ---\n%s", path, rendered, want)
+	}
+}
@@ -4,7 +4,6 @@ package prompts
 
 import (
 	"evraz_api/internal/prompts/prompts_storage/file_prompts"
-	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
 	"evraz_api/internal/prompts/prompts_storage/project_prompts"
 	"evraz_api/internal/prompts/types"
 )
@@ -31,9 +30,6 @@ type Prompts struct {
 	ErrorHandlingAndLogging types.Prompt
 	AdditionalTechnicalFile types.Prompt
 	DateTimeHandlingFile    types.Prompt
-
-	// Helper Prompts
-	ExtractTests types.Prompt
 }
 
 func NewPrompts() *Prompts {
@@ -59,8 +55,63 @@ func NewPrompts() *Prompts {
 		ErrorHandlingAndLogging: file_prompts.ErrorHandlingAndLoggingPrompt,
 		AdditionalTechnicalFile: file_prompts.AdditionalTechnicalFilePrompt,
 		DateTimeHandlingFile:    file_prompts.DateTimeHandlingFilePrompt,
+	}
+}
+
+// ProjectPromptNames lists the project-level checks in report order
+var ProjectPromptNames = []string{
+	"ProjectStructure",
+	"KeyFiles",
+	"ApplicationArchitecture",
+	"DependencyManagement",
+	"ProjectSettings",
+	"TestingStrategy",
+	"AdditionalTechnical",
+	"DateTimeHandling",
+}
+
+// FilePromptNames lists the file-level checks in report order
+var FilePromptNames = []string{
+	"ApplicationLayerCode",
+	"AdaptersLayerCode",
+	"CodingStandards",
+	"ErrorHandlingAndLogging",
+	"AdditionalTechnicalFile",
+	"DateTimeHandlingFile",
+}
 
-		// Helper Prompts
-		ExtractTests: helper_prompts.ExtractTestsPrompt,
+// ByName returns the project- or file-level prompt registered under the given name
+func (p *Prompts) ByName(name string) (types.Prompt, bool) {
+	switch name {
+	case "ProjectStructure":
+		return p.ProjectStructure, true
+	case "KeyFiles":
+		return p.KeyFiles, true
+	case "ApplicationArchitecture":
+		return p.ApplicationArchitecture, true
+	case "DependencyManagement":
+		return p.DependencyManagement, true
+	case "ProjectSettings":
+		return p.ProjectSettings, true
+	case "TestingStrategy":
+		return p.TestingStrategy, true
+	case "AdditionalTechnical":
+		return p.AdditionalTechnical, true
+	case "DateTimeHandling":
+		return p.DateTimeHandling, true
+	case "ApplicationLayerCode":
+		return p.ApplicationLayerCode, true
+	case "AdaptersLayerCode":
+		return p.AdaptersLayerCode, true
+	case "CodingStandards":
+		return p.CodingStandards, true
+	case "ErrorHandlingAndLogging":
+		return p.ErrorHandlingAndLogging, true
+	case "AdditionalTechnicalFile":
+		return p.AdditionalTechnicalFile, true
+	case "DateTimeHandlingFile":
+		return p.DateTimeHandlingFile, true
+	default:
+		return types.Prompt{}, false
 	}
 }
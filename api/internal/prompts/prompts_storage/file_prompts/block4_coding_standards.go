@@ -7,10 +7,11 @@ import "evraz_api/internal/prompts/types"
 type CodingStandardsData struct {
 	FilePath    string
 	FileContent string
+	KnownIssues string // Issues already reported by external tools
 }
 
 func (d CodingStandardsData) ToPassedData() []types.PassedData {
-	return []types.PassedData{
+	return append([]types.PassedData{
 		{
 			Name:        "File Path",
 			Description: "Path of the source code file",
@@ -21,7 +22,7 @@ func (d CodingStandardsData) ToPassedData() []types.PassedData {
 			Description: "Contents of the source code file",
 			Content:     d.FileContent,
 		},
-	}
+	}, knownIssuesData(d.KnownIssues)...)
 }
 
 var CodingStandardsPrompt = types.Prompt{
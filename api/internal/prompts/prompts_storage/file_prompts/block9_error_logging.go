@@ -7,10 +7,11 @@ import "evraz_api/internal/prompts/types"
 type ErrorHandlingAndLoggingData struct {
 	FilePath    string
 	FileContent string
+	KnownIssues string // Issues already reported by external tools
 }
 
 func (d ErrorHandlingAndLoggingData) ToPassedData() []types.PassedData {
-	return []types.PassedData{
+	return append([]types.PassedData{
 		{
 			Name:        "File Path",
 			Description: "Path of the source code file implementing error handling and logging",
@@ -21,7 +22,7 @@ func (d ErrorHandlingAndLoggingData) ToPassedData() []types.PassedData {
 			Description: "Contents of the source code file",
 			Content:     d.FileContent,
 		},
-	}
+	}, knownIssuesData(d.KnownIssues)...)
 }
 
 var ErrorHandlingAndLoggingPrompt = types.Prompt{
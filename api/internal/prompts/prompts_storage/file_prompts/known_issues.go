@@ -0,0 +1,20 @@
+// internal/prompts/prompts_storage/file_prompts/known_issues.go
+
+package file_prompts
+
+import "evraz_api/internal/prompts/types"
+
+// knownIssuesData passes the issues linters already reported for the file, so the model
+// does not spend its answer repeating them
+func knownIssuesData(knownIssues string) []types.PassedData {
+	if knownIssues == "" {
+		return nil
+	}
+	return []types.PassedData{
+		{
+			Name:        "Known Issues",
+			Description: "Issues already reported by linters for this file. Do not report them again; focus on problems they do not cover",
+			Content:     knownIssues,
+		},
+	}
+}
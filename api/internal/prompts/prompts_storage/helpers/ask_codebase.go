@@ -0,0 +1,42 @@
+// internal/prompts/prompts_storage/helpers/ask_codebase.go
+
+package helper_prompts
+
+import (
+	"evraz_api/internal/prompts/types"
+)
+
+type AskCodebaseData struct {
+	History  string // Earlier questions and answers of the conversation
+	Context  string // Retrieved code chunks, each introduced by its number, path and lines
+	Question string
+}
+
+func (d AskCodebaseData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "Conversation History",
+			Description: "Earlier questions about the project and their answers",
+			Content:     d.History,
+		},
+		{
+			Name:        "Code Context",
+			Description: "Project code fragments relevant to the question, each with a number, file path and line range",
+			Content:     d.Context,
+		},
+		{
+			Name:        "Question",
+			Description: "Question of the reviewer about the project",
+			Content:     d.Question,
+		},
+	}
+}
+
+var AskCodebasePrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant specialized in code analysis, answer a reviewer's question about a Python project using the code fragments retrieved from it.",
+	BaseTaskDesc: "Answer the question based only on the code context.\n\nGuidelines:\n\nRefer to files by path and line numbers.\nIf the context does not contain the answer, say so instead of guessing.\nCite the numbers of the fragments the answer relies on.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "answer", Description: "(str) Answer to the question"},
+		{Key: "citations", Description: "(list of int) Numbers of the code fragments the answer relies on"},
+	},
+}
@@ -0,0 +1,41 @@
+// internal/prompts/prompts_storage/helpers/critic.go
+
+package helper_prompts
+
+import (
+	"evraz_api/internal/prompts/types"
+)
+
+type CriticData struct {
+	FilePath    string
+	FileContent string
+	Findings    string // Numbered list of candidate findings, "<id>. [<rule>] <message>"
+}
+
+func (d CriticData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "File Path",
+			Description: "Path of the reviewed source code file",
+			Content:     d.FilePath,
+		},
+		{
+			Name:        "File Content",
+			Description: "Contents of the reviewed source code file",
+			Content:     d.FileContent,
+		},
+		{
+			Name:        "Candidate Findings",
+			Description: "Issues reported by earlier review steps, each with an id and the check that raised it",
+			Content:     d.Findings,
+		},
+	}
+}
+
+var CriticPrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant acting as a strict code review critic, verify the issues other reviewers reported for the following source code file.",
+	BaseTaskDesc: "Check every candidate finding against the file content and decide whether the code actually supports it.\n\nGuidelines:\n\nA finding is supported only when the code shows the problem; claims about missing elements (docstrings, logging, type hints, error handling) must be checked against the code.\nGeneric advice that does not point at anything in the file is not supported.\nConfidence is a number from 0 to 1 expressing how sure you are of the verdict that the finding is supported.\nReturn a verdict for every finding id exactly once.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "verdicts", Description: "(list of objects) One object per finding with id (int), supported (bool), confidence (float from 0 to 1) and reason (str)"},
+	},
+}
@@ -0,0 +1,42 @@
+// internal/prompts/prompts_storage/helpers/patch_review.go
+
+package helper_prompts
+
+import (
+	"evraz_api/internal/prompts/types"
+)
+
+type PatchReviewData struct {
+	FilePath string
+	Changes  string // Changed hunks with context, see patch.Excerpt
+}
+
+func (d PatchReviewData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "File Path",
+			Description: "Path of the changed source code file",
+			Content:     d.FilePath,
+		},
+		{
+			Name:        "Changed Code",
+			Description: "Changed parts of the file with surrounding context; every line starts with its number in the new version of the file, lines marked with + were added or modified and ... stands for unchanged code that is left out",
+			Content:     d.Changes,
+		},
+	}
+}
+
+// patchReviewTask is appended to the task of a file-level check reviewing a merge request
+const patchReviewTask = "\n\nYou are reviewing a merge request, not the whole file.\nReport only problems in lines marked with +; the other lines are context.\nEvery issue must give the number of the added line it is about.\nCompliance refers to the changed code only."
+
+// PatchReviewPrompt turns a file-level check into a review of a merge request: the check
+// keeps its guidelines but only looks at the added lines and anchors every issue to one
+func PatchReviewPrompt(check types.Prompt) types.Prompt {
+	check.BaseTaskDesc += patchReviewTask
+	check.JSONStruct = []types.JSONStruct{
+		{Key: "compliance", Description: "(bool) Whether the changed code meets the guidelines"},
+		{Key: "issues", Description: "(list of objects) Problems in the added lines, each with line (int, line number in the new version of the file), severity (str, one of high, medium, low or info) and message (str)"},
+		{Key: "recommendations", Description: "(list of str) Suggestions for improvement of the changed code"},
+	}
+	return check
+}
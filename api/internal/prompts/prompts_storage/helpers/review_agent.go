@@ -0,0 +1,15 @@
+// internal/prompts/prompts_storage/helpers/review_agent.go
+
+package helper_prompts
+
+// ReviewAgentSystemPrompt introduces the file tools to the review agent. It is formatted
+// with the number of turns the agent has and the project tree.
+const ReviewAgentSystemPrompt = `You are an AI assistant reviewing a Python project. The files quoted in the task may not be enough to answer it: use the list_dir, read_file and grep tools to look at any other project file you need before answering.
+
+You have at most %d turns. Request only the files relevant to the task, and stop calling tools as soon as you can answer. The final answer must be the JSON object the task asks for, with no tool calls.
+
+Project tree:
+%s`
+
+// ReviewAgentFinalMessage asks the agent to answer once its budget is spent
+const ReviewAgentFinalMessage = "The tool budget is exhausted. Answer the task now with the requested JSON object, based on what you have seen."
@@ -0,0 +1,66 @@
+// internal/prompts/prompts_storage/helpers/summaries.go
+
+package helper_prompts
+
+import (
+	"evraz_api/internal/prompts/types"
+)
+
+type FileSummaryData struct {
+	Files string // Files of a batch, each introduced by its id and path
+}
+
+func (d FileSummaryData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "Files",
+			Description: "Source code files, each introduced by its id and path",
+			Content:     d.Files,
+		},
+	}
+}
+
+var FileSummaryPrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant specialized in code analysis, summarize the following files of a Python project so that a reviewer can understand the project without reading them.",
+	BaseTaskDesc: "Describe every file in two or three sentences.\n\nGuidelines:\n\nPurpose is what the file is responsible for.\nContext names the main classes and functions it defines, the project modules and libraries it relies on, and notable practices (error handling, logging, transactions, async code, date and time handling).\nDo not invent names that are not in the file.\nReturn an overview for every file id exactly once.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "description", Description: "(str) One sentence about the files as a whole"},
+		{Key: "files_overview", Description: "(list of objects) One object per file with id (int), purpose (str) and context (str)"},
+	},
+}
+
+type SummaryRollupData struct {
+	Scope     string // What is summarized, e.g. "package app/core"
+	Summaries string // Summaries of the parts, each introduced by its path
+}
+
+func (d SummaryRollupData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "Scope",
+			Description: "Part of the project being summarized",
+			Content:     d.Scope,
+		},
+		{
+			Name:        "Summaries",
+			Description: "Summaries of the files or packages it consists of",
+			Content:     d.Summaries,
+		},
+	}
+}
+
+var PackageSummaryPrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant specialized in code analysis, summarize a package of a Python project from the summaries of its files.",
+	BaseTaskDesc: "Write one paragraph describing the responsibility of the package, its main modules and how they work together, and the libraries and practices it relies on. Only use what the summaries state.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "summary", Description: "(str) Summary of the package"},
+	},
+}
+
+var ProjectSummaryPrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant specialized in code analysis, summarize a Python project from the summaries of its packages.",
+	BaseTaskDesc: "Write a few paragraphs describing what the project does, its architecture and layers, how the packages depend on each other, and the frameworks, libraries and practices it uses. Only use what the summaries state.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "summary", Description: "(str) Summary of the project"},
+	},
+}
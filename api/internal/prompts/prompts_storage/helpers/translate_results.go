@@ -0,0 +1,35 @@
+// internal/prompts/prompts_storage/helpers/translate_results.go
+
+package helper_prompts
+
+import (
+	"evraz_api/internal/prompts/types"
+)
+
+type TranslateResultsData struct {
+	TargetLanguage string
+	Items          string // JSON array of {"id", "issues", "recommendations"}
+}
+
+func (d TranslateResultsData) ToPassedData() []types.PassedData {
+	return []types.PassedData{
+		{
+			Name:        "Target Language",
+			Description: "Language to translate the texts into",
+			Content:     d.TargetLanguage,
+		},
+		{
+			Name:        "Items",
+			Description: "JSON array of code review results, each with an id, issues and recommendations",
+			Content:     d.Items,
+		},
+	}
+}
+
+var TranslateResultsPrompt = types.Prompt{
+	BasePrompt:   "As an AI assistant specialized in technical translation, translate the following code review results.",
+	BaseTaskDesc: "Translate the issues and recommendations of every item into the target language.\n\nGuidelines:\n\nKeep the meaning, tone and level of detail of the original text.\nDo not translate code identifiers, file paths, package names or quoted code.\nKeep the id of every item unchanged and return every item exactly once.",
+	JSONStruct: []types.JSONStruct{
+		{Key: "translations", Description: "(list of objects) Translated items, each with id (str), issues (str) and recommendations (str)"},
+	},
+}
@@ -9,18 +9,24 @@ import (
 type TestingStrategyData struct {
 	ProjectTree       string
 	TestsFilesContent string
+	TestSuiteSummary  string
+	MirroringGaps     string
+	Coverage          string
 }
 
 func (t TestingStrategyData) ToPassedData() []types.PassedData {
 	return []types.PassedData{
 		{Name: "Project Tree", Description: "Structure of the project", Content: t.ProjectTree},
+		{Name: "Test Suite Summary", Description: "Test configuration, test files and test function counts discovered in the project", Content: t.TestSuiteSummary},
+		{Name: "Test Structure Mirroring", Description: "Source modules without tests or with tests outside the mirrored location", Content: t.MirroringGaps},
+		{Name: "Coverage", Description: "Line and branch coverage measured by the project's coverage report", Content: t.Coverage},
 		{Name: "Tests Files Content", Description: "Files related to testing", Content: t.TestsFilesContent},
 	}
 }
 
 var TestingStrategyPrompt = types.Prompt{
 	BasePrompt:   "As an AI assistant specialized in software testing, please review the project's testing strategy and structure.",
-	BaseTaskDesc: "Evaluate the project's testing strategy and structure.\n\nGuidelines:\n\nUnit Tests: Prioritized, with adapters mocked.\nIntegration Tests: Use SQLite in-memory databases.\nTest Structure: Mirrors project structure; test files correspond to modules/classes.\nTesting Practices: Tests cover various scenarios, including edge cases.",
+	BaseTaskDesc: "Evaluate the project's testing strategy and structure.\n\nGuidelines:\n\nUnit Tests: Prioritized, with adapters mocked.\nIntegration Tests: Use SQLite in-memory databases.\nTest Structure: Mirrors project structure; test files correspond to modules/classes.\nTesting Practices: Tests cover various scenarios, including edge cases.\n\nBase conclusions about coverage on the provided coverage numbers when they are available.",
 	JSONStruct: []types.JSONStruct{
 		{Key: "compliance", Description: "(bool) Whether the testing strategy meets the requirements"},
 		{Key: "issues", Description: "(list of str) List of any issues found"},
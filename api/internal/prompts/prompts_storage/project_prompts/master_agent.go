@@ -7,21 +7,41 @@ import (
 )
 
 type ProjectMasterData struct {
-	FileContent string
+	ProjectTree       string
+	ProjectLanguage   string
+	KeyFiles          []string
+	DependencySummary string
 }
 
 func (t ProjectMasterData) ToPassedData() []types.PassedData {
+	keyFiles := "No key files found"
+	if len(t.KeyFiles) > 0 {
+		keyFiles = ""
+		for _, path := range t.KeyFiles {
+			keyFiles += "- " + path + "\n"
+		}
+	}
+
 	return []types.PassedData{
-		{Name: "File content", Description: "Full file content", Content: t.FileContent},
+		{Name: "Project Tree", Description: "Structure of the project", Content: t.ProjectTree},
+		{Name: "Project Language", Description: "Detected main programming language", Content: t.ProjectLanguage},
+		{Name: "Key Files", Description: "Configuration, documentation and infrastructure files found in the project", Content: keyFiles},
+		{Name: "Dependency Summary", Description: "Declared dependencies of the project", Content: t.DependencySummary},
 	}
 }
 
 var ProjectMasterPrompt = types.Prompt{
-	BasePrompt: "As an AI assistant overseeing the project analysis, review the provided file, and determine which review prompts are applicable based on the provided project metadata.",
-	BaseTaskDesc: `Your goal is to return a JSON array containing the IDs or names of the applicable prompts for the project-level analysis. Here is a list of possible prompts:
-
-	`,
+	BasePrompt: "As an AI assistant overseeing the project analysis, review the provided project metadata and determine which project-level review checks are applicable to this project.",
+	BaseTaskDesc: "Decide for every check below whether it is applicable. A check is not applicable only when the project clearly has nothing for it to review (for example, no date or time handling at all); missing files that the guidelines require do not make a check inapplicable.\n\nChecks:\n\n" +
+		"ProjectStructure: Monorepository layout, root files and directories.\n" +
+		"KeyFiles: setup.py/setup.cfg, pyproject.toml and README.md of the backend.\n" +
+		"ApplicationArchitecture: Hexagonal (Ports and Adapters) architecture.\n" +
+		"DependencyManagement: Declared dependencies and their versions.\n" +
+		"ProjectSettings: Settings passed via environment variables and Pydantic BaseSettings.\n" +
+		"TestingStrategy: Unit and integration tests and their structure.\n" +
+		"AdditionalTechnical: Database transactions, asynchronous code, data science dependencies, monitoring.\n" +
+		"DateTimeHandling: Storage and calculation of dates and times in UTC, timezone handling.",
 	JSONStruct: []types.JSONStruct{
-		{Key: "applicable_prompts", Description: "Array of prompt IDs that should be applied"},
+		{Key: "checks", Description: "(list of objects) One object per check with name (str), applicable (bool) and justification (str)"},
 	},
 }
@@ -23,6 +23,7 @@ type Prompt struct {
 	PassedData   []PassedData
 	JSONStruct   []JSONStruct
 	Language     string
+	Template     string // Optional text/template override; the default layout is used when empty
 }
 
 // PromptData is an interface that all prompt data types implement
@@ -0,0 +1,178 @@
+// internal/quality/gate.go
+
+package quality
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+// DefaultPolicy is the policy a gate applies when none is named
+const DefaultPolicy = "default"
+
+// Policy is a named set of conditions a project must meet to pass a quality gate.
+// Projects define their own in the gates section of the configuration file:
+//
+//	gates:
+//	  release:
+//	    max_new: {high: 0, medium: 5}
+//	    min_score: 70
+//	    compliant: [DependencyManagement]
+type Policy struct {
+	MaxNew    map[string]int `yaml:"max_new" json:"max_new,omitempty"`     // Most new findings allowed by severity
+	MaxTotal  map[string]int `yaml:"max_total" json:"max_total,omitempty"` // Most findings allowed by severity
+	MinScore  *float64       `yaml:"min_score" json:"min_score,omitempty"`
+	Compliant []string       `yaml:"compliant" json:"compliant,omitempty"` // Checks every verdict of which must be met
+}
+
+// BuiltinPolicies are the policies every project can use; a policy of the configuration
+// file with the same name replaces the built-in one
+func BuiltinPolicies() map[string]Policy {
+	score := 70.0
+	return map[string]Policy{
+		DefaultPolicy: {MaxNew: map[string]int{model.SeverityHigh: 0}},
+		"no-new-high": {MaxNew: map[string]int{model.SeverityHigh: 0}},
+		"score-70":    {MinScore: &score},
+		"dependencies-compliant": {
+			Compliant: []string{"DependencyManagement"},
+		},
+		"strict": {
+			MaxNew:    map[string]int{model.SeverityHigh: 0, model.SeverityMedium: 0},
+			MinScore:  &score,
+			Compliant: []string{"DependencyManagement", "ApplicationArchitecture"},
+		},
+	}
+}
+
+// Empty reports whether the policy has no conditions
+func (p Policy) Empty() bool {
+	return len(p.MaxNew) == 0 && len(p.MaxTotal) == 0 && p.MinScore == nil && len(p.Compliant) == 0
+}
+
+// GateInput is what a gate looks at: the snapshot of the latest run and the findings
+// that are new since the run before it
+type GateInput struct {
+	Score      *float64 // Nil when the run was not scored
+	Findings   []model.AnalysisRunFinding
+	New        []model.AnalysisRunFinding
+	Compliance []model.AnalysisRunCompliance
+}
+
+// Violation is a condition of a policy the project does not meet
+type Violation struct {
+	Policy    string
+	Condition string // max_new.<severity>, max_total.<severity>, min_score or compliant.<check>
+	Expected  string
+	Actual    string
+	Message   string
+	Findings  []model.AnalysisRunFinding // Findings that break a finding limit
+}
+
+// Evaluate checks the conditions of the policy in a stable order and returns the ones
+// that are not met
+func (p Policy) Evaluate(name string, input GateInput) []Violation {
+	var violations []Violation
+
+	countLimits := func(condition, what string, limits map[string]int, findings []model.AnalysisRunFinding) {
+		for _, severity := range sortedKeys(limits) {
+			var matched []model.AnalysisRunFinding
+			for _, finding := range findings {
+				if finding.Severity == severity {
+					matched = append(matched, finding)
+				}
+			}
+			if len(matched) <= limits[severity] {
+				continue
+			}
+			violations = append(violations, Violation{
+				Policy:    name,
+				Condition: condition + "." + severity,
+				Expected:  fmt.Sprintf("<= %d", limits[severity]),
+				Actual:    fmt.Sprint(len(matched)),
+				Message:   fmt.Sprintf("%d %s %s-severity findings, at most %d allowed", len(matched), what, severity, limits[severity]),
+				Findings:  matched,
+			})
+		}
+	}
+	countLimits("max_new", "new", p.MaxNew, input.New)
+	countLimits("max_total", "open", p.MaxTotal, input.Findings)
+
+	if p.MinScore != nil {
+		switch {
+		case input.Score == nil:
+			violations = append(violations, Violation{
+				Policy:    name,
+				Condition: "min_score",
+				Expected:  fmt.Sprintf(">= %.1f", *p.MinScore),
+				Actual:    "none",
+				Message:   "the run has no quality score",
+			})
+		case *input.Score < *p.MinScore:
+			violations = append(violations, Violation{
+				Policy:    name,
+				Condition: "min_score",
+				Expected:  fmt.Sprintf(">= %.1f", *p.MinScore),
+				Actual:    fmt.Sprintf("%.1f", *input.Score),
+				Message:   fmt.Sprintf("quality score %.1f is below %.1f", *input.Score, *p.MinScore),
+			})
+		}
+	}
+
+	for _, check := range p.Compliant {
+		verdicts, failed := 0, []string{}
+		for _, verdict := range input.Compliance {
+			if verdict.PromptName != check {
+				continue
+			}
+			verdicts++
+			if verdict.Compliance != "true" {
+				location := verdict.Path
+				if location == "" {
+					location = "project"
+				}
+				failed = append(failed, location)
+			}
+		}
+		violation := Violation{Policy: name, Condition: "compliant." + check, Expected: "true"}
+		switch {
+		case verdicts == 0:
+			violation.Actual = "none"
+			violation.Message = fmt.Sprintf("%s has no verdict in the run", check)
+		case len(failed) > 0:
+			sort.Strings(failed)
+			violation.Actual = "false"
+			violation.Message = fmt.Sprintf("%s is not met: %s", check, strings.Join(failed, ", "))
+		default:
+			continue
+		}
+		violations = append(violations, violation)
+	}
+
+	return violations
+}
+
+func sortedKeys(limits map[string]int) []string {
+	keys := make([]string, 0, len(limits))
+	for key := range limits {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return severityRank(keys[i]) < severityRank(keys[j]) })
+	return keys
+}
+
+// severityRank orders severities from the most severe
+func severityRank(severity string) int {
+	switch severity {
+	case model.SeverityHigh:
+		return 0
+	case model.SeverityMedium:
+		return 1
+	case model.SeverityLow:
+		return 2
+	default:
+		return 3
+	}
+}
@@ -0,0 +1,187 @@
+// internal/quality/score.go
+
+package quality
+
+import (
+	"math"
+
+	"evraz_api/internal/model"
+)
+
+// Categories of the score breakdown
+const (
+	CategoryStructure       = "structure"
+	CategoryDependencies    = "dependencies"
+	CategoryArchitecture    = "architecture"
+	CategoryTesting         = "testing"
+	CategoryCodingStandards = "coding_standards"
+	CategoryErrorHandling   = "error_handling"
+	CategoryDateTime        = "datetime"
+)
+
+// Categories lists the categories in report order
+var Categories = []string{
+	CategoryStructure,
+	CategoryDependencies,
+	CategoryArchitecture,
+	CategoryTesting,
+	CategoryCodingStandards,
+	CategoryErrorHandling,
+	CategoryDateTime,
+}
+
+// ruleCategories maps checks and computed rules to their category; anything else, such
+// as linter rules, counts as coding standards
+var ruleCategories = map[string]string{
+	"ProjectStructure":        CategoryStructure,
+	"KeyFiles":                CategoryStructure,
+	"ProjectSettings":         CategoryStructure,
+	"ProjectConfig":           CategoryStructure,
+	"DependencyManagement":    CategoryDependencies,
+	"ApplicationArchitecture": CategoryArchitecture,
+	"ApplicationLayerCode":    CategoryArchitecture,
+	"AdaptersLayerCode":       CategoryArchitecture,
+	"AdditionalTechnical":     CategoryArchitecture,
+	"AdditionalTechnicalFile": CategoryArchitecture,
+	"TestingStrategy":         CategoryTesting,
+	"TestStructureMirroring":  CategoryTesting,
+	"CodingStandards":         CategoryCodingStandards,
+	"ErrorHandlingAndLogging": CategoryErrorHandling,
+	"DateTimeHandling":        CategoryDateTime,
+	"DateTimeHandlingFile":    CategoryDateTime,
+}
+
+// severityWeights is the penalty of a finding by severity
+var severityWeights = map[string]float64{
+	model.SeverityHigh:   10,
+	model.SeverityMedium: 4,
+	model.SeverityLow:    1,
+	model.SeverityInfo:   0,
+}
+
+// ruleWeights scales the penalty of the findings of a rule: architecture problems are
+// the costliest to fix, a missing mirrored test file the cheapest
+var ruleWeights = map[string]float64{
+	"ApplicationArchitecture": 1.5,
+	"ApplicationLayerCode":    1.5,
+	"AdaptersLayerCode":       1.5,
+	"TestStructureMirroring":  0.5,
+}
+
+const (
+	// externalWeight scales linter findings, which are many and mostly cosmetic
+	externalWeight = 0.5
+	// failedCheckPenalty is the penalty of a project-level check that is not met
+	failedCheckPenalty = 8
+	// minCodeLines keeps small projects from being judged by a handful of findings
+	minCodeLines = 500
+	// halfScoreDensity is the penalty per thousand lines of code that halves the score;
+	// a category gets a seventh of it, so that one category can drop on its own
+	halfScoreDensity         = 20.0
+	categoryHalfScoreDensity = halfScoreDensity / 7
+)
+
+// Score is the quality of a project as seen by one analysis run
+type Score struct {
+	Score      float64 // 0 to 100
+	Grade      string
+	CodeLines  int
+	Categories []CategoryScore // In the order of Categories
+}
+
+// CategoryScore is the part of the score of one category
+type CategoryScore struct {
+	Category     string
+	Score        float64
+	Penalty      float64
+	Findings     int
+	FailedChecks int
+}
+
+// CategoryOf returns the category of a rule
+func CategoryOf(ruleName string) string {
+	if category, ok := ruleCategories[ruleName]; ok {
+		return category
+	}
+	return CategoryCodingStandards
+}
+
+// Compute scores the findings and verdicts of a run. Every finding costs a penalty
+// weighted by its severity and rule, every project-level check that is not met costs a
+// fixed penalty, and the penalty per thousand lines of code maps to a score between 0 and
+// 100 that halves at halfScoreDensity.
+func Compute(findings []model.AnalysisRunFinding, compliance []model.AnalysisRunCompliance, codeLines int) Score {
+	byCategory := make(map[string]*CategoryScore, len(Categories))
+	for _, category := range Categories {
+		byCategory[category] = &CategoryScore{Category: category}
+	}
+
+	for _, finding := range findings {
+		weight := ruleWeight(finding.RuleName)
+		if finding.Source == model.FindingSourceExternal {
+			weight = externalWeight
+		}
+		category := byCategory[CategoryOf(finding.RuleName)]
+		category.Penalty += severityWeights[finding.Severity] * weight
+		category.Findings++
+	}
+	for _, verdict := range compliance {
+		// File-level verdicts are already reflected by the findings of the file
+		if verdict.Path != "" || verdict.Compliance != "false" {
+			continue
+		}
+		category := byCategory[CategoryOf(verdict.PromptName)]
+		category.Penalty += failedCheckPenalty * ruleWeight(verdict.PromptName)
+		category.FailedChecks++
+	}
+
+	kloc := float64(codeLines)
+	if kloc < minCodeLines {
+		kloc = minCodeLines
+	}
+	kloc /= 1000
+
+	score := Score{CodeLines: codeLines}
+	total := 0.0
+	for _, name := range Categories {
+		category := byCategory[name]
+		total += category.Penalty
+		category.Penalty = round(category.Penalty)
+		category.Score = scoreOf(category.Penalty/kloc, categoryHalfScoreDensity)
+		score.Categories = append(score.Categories, *category)
+	}
+	score.Score = scoreOf(total/kloc, halfScoreDensity)
+	score.Grade = Grade(score.Score)
+	return score
+}
+
+// Grade turns a score into a letter from A to E
+func Grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 75:
+		return "B"
+	case score >= 60:
+		return "C"
+	case score >= 45:
+		return "D"
+	default:
+		return "E"
+	}
+}
+
+func ruleWeight(ruleName string) float64 {
+	if weight, ok := ruleWeights[ruleName]; ok {
+		return weight
+	}
+	return 1
+}
+
+func scoreOf(density, halfDensity float64) float64 {
+	return round(100 / (1 + density/halfDensity))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
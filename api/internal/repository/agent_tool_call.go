@@ -0,0 +1,35 @@
+// internal/repository/agent_tool_call.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AgentToolCallRepository interface {
+	CreateOne(call *model.AgentToolCall) error
+	GetManyByProjectID(projectID uint) ([]model.AgentToolCall, error)
+}
+
+type GormAgentToolCallRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAgentToolCallRepository(db *gorm.DB) *GormAgentToolCallRepository {
+	return &GormAgentToolCallRepository{db: db}
+}
+
+func (repo *GormAgentToolCallRepository) CreateOne(call *model.AgentToolCall) error {
+	return repo.db.Create(call).Error
+}
+
+// GetManyByProjectID returns the project's tool calls in the order they were made
+func (repo *GormAgentToolCallRepository) GetManyByProjectID(projectID uint) ([]model.AgentToolCall, error) {
+	var calls []model.AgentToolCall
+	if err := repo.db.Where("project_id = ?", projectID).Order("id").Find(&calls).Error; err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
@@ -0,0 +1,155 @@
+// internal/repository/analysis_run.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AnalysisRunRepository interface {
+	CreateOne(run *model.AnalysisRun) error
+	UpdateOne(run *model.AnalysisRun) error
+	CreatePromptStats(stats []model.AnalysisRunPromptStat) error
+	GetManyByProjectID(projectID uint) ([]model.AnalysisRun, error)
+	GetManyByProjectIDs(projectIDs []uint) ([]model.AnalysisRun, error)
+	GetOneByID(id uint) (*model.AnalysisRun, error)
+	CreateSnapshot(findings []model.AnalysisRunFinding, compliance []model.AnalysisRunCompliance) error
+	GetSnapshotFindings(runID uint) ([]model.AnalysisRunFinding, error)
+	GetSnapshotCompliance(runID uint) ([]model.AnalysisRunCompliance, error)
+	SaveScore(run *model.AnalysisRun, categories []model.AnalysisRunCategoryScore) error
+	GetLatestScored(projectIDs []uint) ([]model.AnalysisRun, error)
+}
+
+type GormAnalysisRunRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAnalysisRunRepository(db *gorm.DB) *GormAnalysisRunRepository {
+	return &GormAnalysisRunRepository{db: db}
+}
+
+func (repo *GormAnalysisRunRepository) CreateOne(run *model.AnalysisRun) error {
+	return repo.db.Create(run).Error
+}
+
+func (repo *GormAnalysisRunRepository) UpdateOne(run *model.AnalysisRun) error {
+	return repo.db.Omit("PromptStats", "CategoryScores").Save(run).Error
+}
+
+func (repo *GormAnalysisRunRepository) CreatePromptStats(stats []model.AnalysisRunPromptStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return repo.db.Create(&stats).Error
+}
+
+// GetManyByProjectID returns the project's runs, newest first, with their prompt statistics
+// and category scores
+func (repo *GormAnalysisRunRepository) GetManyByProjectID(projectID uint) ([]model.AnalysisRun, error) {
+	var runs []model.AnalysisRun
+	err := repo.db.
+		Preload("PromptStats", func(db *gorm.DB) *gorm.DB { return db.Order("prompt_name") }).
+		Preload("CategoryScores", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("project_id = ?", projectID).
+		Order("id DESC").
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// GetManyByProjectIDs returns the runs of all given projects, newest first
+func (repo *GormAnalysisRunRepository) GetManyByProjectIDs(projectIDs []uint) ([]model.AnalysisRun, error) {
+	var runs []model.AnalysisRun
+	if len(projectIDs) == 0 {
+		return runs, nil
+	}
+	if err := repo.db.Where("project_id IN ?", projectIDs).Order("id DESC").Find(&runs).Error; err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+func (repo *GormAnalysisRunRepository) GetOneByID(id uint) (*model.AnalysisRun, error) {
+	var run model.AnalysisRun
+	if err := repo.db.First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CreateSnapshot stores the findings and compliance verdicts of a finished run
+func (repo *GormAnalysisRunRepository) CreateSnapshot(findings []model.AnalysisRunFinding, compliance []model.AnalysisRunCompliance) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		if len(findings) > 0 {
+			if err := tx.CreateInBatches(&findings, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(compliance) > 0 {
+			if err := tx.CreateInBatches(&compliance, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (repo *GormAnalysisRunRepository) GetSnapshotFindings(runID uint) ([]model.AnalysisRunFinding, error) {
+	var findings []model.AnalysisRunFinding
+	if err := repo.db.Where("analysis_run_id = ?", runID).Order("path, line, id").Find(&findings).Error; err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func (repo *GormAnalysisRunRepository) GetSnapshotCompliance(runID uint) ([]model.AnalysisRunCompliance, error) {
+	var compliance []model.AnalysisRunCompliance
+	if err := repo.db.Where("analysis_run_id = ?", runID).Order("path, prompt_name").Find(&compliance).Error; err != nil {
+		return nil, err
+	}
+	return compliance, nil
+}
+
+// SaveScore stores the quality score of a run with its breakdown by category
+func (repo *GormAnalysisRunRepository) SaveScore(run *model.AnalysisRun, categories []model.AnalysisRunCategoryScore) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.AnalysisRun{}).
+			Where("id = ?", run.ID).
+			Select("score", "grade", "code_lines").
+			Updates(run).Error
+		if err != nil {
+			return err
+		}
+		if len(categories) > 0 {
+			if err := tx.Create(&categories).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetLatestScored returns the newest scored run of each of the projects, with its
+// category scores
+func (repo *GormAnalysisRunRepository) GetLatestScored(projectIDs []uint) ([]model.AnalysisRun, error) {
+	var runs []model.AnalysisRun
+	if len(projectIDs) == 0 {
+		return runs, nil
+	}
+	latest := repo.db.Model(&model.AnalysisRun{}).
+		Select("MAX(id)").
+		Where("project_id IN ? AND score IS NOT NULL", projectIDs).
+		Group("project_id")
+	err := repo.db.
+		Preload("CategoryScores", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("id IN (?)", latest).
+		Find(&runs).Error
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
@@ -0,0 +1,53 @@
+// internal/repository/analysis_translation.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type AnalysisTranslationRepository interface {
+	CreateOne(translation *model.AnalysisTranslation) error
+	GetManyByProjectResultIDs(resultIDs []uint, language string) ([]model.AnalysisTranslation, error)
+	GetManyByFileResultIDs(resultIDs []uint, language string) ([]model.AnalysisTranslation, error)
+}
+
+type GormAnalysisTranslationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAnalysisTranslationRepository(db *gorm.DB) *GormAnalysisTranslationRepository {
+	return &GormAnalysisTranslationRepository{db: db}
+}
+
+func (repo *GormAnalysisTranslationRepository) CreateOne(translation *model.AnalysisTranslation) error {
+	return repo.db.Create(translation).Error
+}
+
+func (repo *GormAnalysisTranslationRepository) GetManyByProjectResultIDs(resultIDs []uint, language string) ([]model.AnalysisTranslation, error) {
+	var translations []model.AnalysisTranslation
+	if len(resultIDs) == 0 {
+		return translations, nil
+	}
+	if err := repo.db.
+		Where("project_analysis_result_id IN ? AND language = ?", resultIDs, language).
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
+
+func (repo *GormAnalysisTranslationRepository) GetManyByFileResultIDs(resultIDs []uint, language string) ([]model.AnalysisTranslation, error) {
+	var translations []model.AnalysisTranslation
+	if len(resultIDs) == 0 {
+		return translations, nil
+	}
+	if err := repo.db.
+		Where("file_analysis_result_id IN ? AND language = ?", resultIDs, language).
+		Find(&translations).Error; err != nil {
+		return nil, err
+	}
+	return translations, nil
+}
@@ -0,0 +1,37 @@
+// internal/repository/code_chunk.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type CodeChunkRepository interface {
+	CreateMany(chunks []model.CodeChunk) error
+	GetManyByProjectID(projectID uint) ([]model.CodeChunk, error)
+}
+
+type GormCodeChunkRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCodeChunkRepository(db *gorm.DB) *GormCodeChunkRepository {
+	return &GormCodeChunkRepository{db: db}
+}
+
+func (repo *GormCodeChunkRepository) CreateMany(chunks []model.CodeChunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+	return repo.db.CreateInBatches(&chunks, 200).Error
+}
+
+func (repo *GormCodeChunkRepository) GetManyByProjectID(projectID uint) ([]model.CodeChunk, error) {
+	var chunks []model.CodeChunk
+	if err := repo.db.Where("project_id = ?", projectID).Order("id").Find(&chunks).Error; err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
@@ -12,6 +12,7 @@ import (
 type FileAnalysisRepository interface {
 	CreateOne(analysis *model.FileAnalysisResult) error
 	GetManyByFileID(projectFileID uint) ([]model.FileAnalysisResult, error)
+	CreateMany(analyses []model.FileAnalysisResult) error
 	// Add more methods as needed
 }
 
@@ -41,3 +42,10 @@ func (repo *GormFileAnalysisRepository) GetManyByFileID(projectFileID uint) ([]m
 	}
 	return results, nil
 }
+
+func (repo *GormFileAnalysisRepository) CreateMany(analyses []model.FileAnalysisResult) error {
+	if len(analyses) == 0 {
+		return nil
+	}
+	return repo.db.Create(&analyses).Error
+}
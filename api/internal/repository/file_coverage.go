@@ -0,0 +1,42 @@
+// internal/repository/file_coverage.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type FileCoverageRepository interface {
+	CreateMany(coverage []model.FileCoverage) error
+	GetManyByProjectID(projectID uint) ([]model.FileCoverage, error)
+	DeleteByProjectID(projectID uint) error
+}
+
+type GormFileCoverageRepository struct {
+	db *gorm.DB
+}
+
+func NewGormFileCoverageRepository(db *gorm.DB) *GormFileCoverageRepository {
+	return &GormFileCoverageRepository{db: db}
+}
+
+func (repo *GormFileCoverageRepository) CreateMany(coverage []model.FileCoverage) error {
+	if len(coverage) == 0 {
+		return nil
+	}
+	return repo.db.Create(&coverage).Error
+}
+
+func (repo *GormFileCoverageRepository) GetManyByProjectID(projectID uint) ([]model.FileCoverage, error) {
+	var coverage []model.FileCoverage
+	if err := repo.db.Where("project_id = ?", projectID).Order("id").Find(&coverage).Error; err != nil {
+		return nil, err
+	}
+	return coverage, nil
+}
+
+func (repo *GormFileCoverageRepository) DeleteByProjectID(projectID uint) error {
+	return repo.db.Where("project_id = ?", projectID).Delete(&model.FileCoverage{}).Error
+}
@@ -0,0 +1,129 @@
+// internal/repository/finding.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type FindingRepository interface {
+	CreateOne(finding *model.Finding) error
+	CreateMany(findings []model.Finding) error
+	GetOneByID(id uint) (*model.Finding, error)
+	GetManyByProjectID(projectID uint) ([]model.Finding, error)
+	GetManyByProjectFileID(projectFileID uint) ([]model.Finding, error)
+	GetManyByAnalysisRunID(runID uint) ([]model.Finding, error)
+	GetTriagedByProjectIDs(projectIDs []uint) ([]model.Finding, error)
+	DeleteByProjectIDAndRule(projectID uint, source, ruleName string) error
+	DeleteByProjectIDAndTool(projectID uint, source, tool string) error
+	DeleteByProjectFileIDAndRule(projectFileID uint, source, ruleName string) error
+	UpdateColumns(findings []model.Finding, columns ...string) error
+}
+
+type GormFindingRepository struct {
+	db *gorm.DB
+}
+
+func NewGormFindingRepository(db *gorm.DB) *GormFindingRepository {
+	return &GormFindingRepository{db: db}
+}
+
+func (repo *GormFindingRepository) CreateOne(finding *model.Finding) error {
+	return repo.db.Create(finding).Error
+}
+
+func (repo *GormFindingRepository) CreateMany(findings []model.Finding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	return repo.db.Create(&findings).Error
+}
+
+func (repo *GormFindingRepository) GetOneByID(id uint) (*model.Finding, error) {
+	var finding model.Finding
+	if err := repo.db.First(&finding, id).Error; err != nil {
+		return nil, err
+	}
+	return &finding, nil
+}
+
+func (repo *GormFindingRepository) GetManyByProjectID(projectID uint) ([]model.Finding, error) {
+	var findings []model.Finding
+	if err := repo.db.Where("project_id = ?", projectID).Order("id").Find(&findings).Error; err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func (repo *GormFindingRepository) GetManyByProjectFileID(projectFileID uint) ([]model.Finding, error) {
+	var findings []model.Finding
+	if err := repo.db.Where("project_file_id = ?", projectFileID).Order("line, id").Find(&findings).Error; err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+func (repo *GormFindingRepository) GetManyByAnalysisRunID(runID uint) ([]model.Finding, error) {
+	var findings []model.Finding
+	if err := repo.db.Where("analysis_run_id = ?", runID).Order("id").Find(&findings).Error; err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// GetTriagedByProjectIDs returns the fingerprinted findings of the projects a user triaged,
+// oldest triage first. Findings replaced by a later run are included, since re-runs
+// delete the findings they replace.
+func (repo *GormFindingRepository) GetTriagedByProjectIDs(projectIDs []uint) ([]model.Finding, error) {
+	var findings []model.Finding
+	if len(projectIDs) == 0 {
+		return findings, nil
+	}
+	err := repo.db.Unscoped().
+		Where("project_id IN ? AND triaged_at IS NOT NULL AND fingerprint <> ''", projectIDs).
+		Order("triaged_at, id").
+		Find(&findings).Error
+	if err != nil {
+		return nil, err
+	}
+	return findings, nil
+}
+
+// DeleteByProjectIDAndRule removes the findings a rule produced earlier, before it is re-run
+func (repo *GormFindingRepository) DeleteByProjectIDAndRule(projectID uint, source, ruleName string) error {
+	return repo.db.
+		Where("project_id = ? AND source = ? AND rule_name = ?", projectID, source, ruleName).
+		Delete(&model.Finding{}).Error
+}
+
+// DeleteByProjectIDAndTool removes the findings imported from an earlier report of a tool
+func (repo *GormFindingRepository) DeleteByProjectIDAndTool(projectID uint, source, tool string) error {
+	return repo.db.
+		Where("project_id = ? AND source = ? AND tool = ?", projectID, source, tool).
+		Delete(&model.Finding{}).Error
+}
+
+// DeleteByProjectFileIDAndRule removes the findings a rule produced earlier for a file
+func (repo *GormFindingRepository) DeleteByProjectFileIDAndRule(projectFileID uint, source, ruleName string) error {
+	return repo.db.
+		Where("project_file_id = ? AND source = ? AND rule_name = ?", projectFileID, source, ruleName).
+		Delete(&model.Finding{}).Error
+}
+
+// UpdateColumns stores the given columns of the findings, including zero values
+func (repo *GormFindingRepository) UpdateColumns(findings []model.Finding, columns ...string) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		for _, finding := range findings {
+			err := tx.Model(&model.Finding{}).
+				Where("id = ?", finding.ID).
+				Select(columns).
+				Updates(&finding).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,53 @@
+// internal/repository/finding_triage.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type FindingTriageRepository interface {
+	CreateComment(comment *model.FindingComment) error
+	GetCommentsByFindingID(findingID uint) ([]model.FindingComment, error)
+	CreateEvents(events []model.FindingEvent) error
+	GetEventsByFindingID(findingID uint) ([]model.FindingEvent, error)
+}
+
+type GormFindingTriageRepository struct {
+	db *gorm.DB
+}
+
+func NewGormFindingTriageRepository(db *gorm.DB) *GormFindingTriageRepository {
+	return &GormFindingTriageRepository{db: db}
+}
+
+func (repo *GormFindingTriageRepository) CreateComment(comment *model.FindingComment) error {
+	return repo.db.Create(comment).Error
+}
+
+// GetCommentsByFindingID returns the discussion of a finding in order
+func (repo *GormFindingTriageRepository) GetCommentsByFindingID(findingID uint) ([]model.FindingComment, error) {
+	var comments []model.FindingComment
+	if err := repo.db.Where("finding_id = ?", findingID).Order("id").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+func (repo *GormFindingTriageRepository) CreateEvents(events []model.FindingEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return repo.db.Create(&events).Error
+}
+
+// GetEventsByFindingID returns the audit trail of a finding in order
+func (repo *GormFindingTriageRepository) GetEventsByFindingID(findingID uint) ([]model.FindingEvent, error) {
+	var events []model.FindingEvent
+	if err := repo.db.Where("finding_id = ?", findingID).Order("id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,43 @@
+// internal/repository/package_summary.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type PackageSummaryRepository interface {
+	CreateMany(summaries []model.PackageSummary) error
+	GetManyByProjectID(projectID uint) ([]model.PackageSummary, error)
+	DeleteByProjectID(projectID uint) error
+}
+
+type GormPackageSummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPackageSummaryRepository(db *gorm.DB) *GormPackageSummaryRepository {
+	return &GormPackageSummaryRepository{db: db}
+}
+
+func (repo *GormPackageSummaryRepository) CreateMany(summaries []model.PackageSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+	return repo.db.Create(&summaries).Error
+}
+
+// GetManyByProjectID returns the project's package summaries ordered by path
+func (repo *GormPackageSummaryRepository) GetManyByProjectID(projectID uint) ([]model.PackageSummary, error) {
+	var summaries []model.PackageSummary
+	if err := repo.db.Where("project_id = ?", projectID).Order("path").Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+func (repo *GormPackageSummaryRepository) DeleteByProjectID(projectID uint) error {
+	return repo.db.Where("project_id = ?", projectID).Delete(&model.PackageSummary{}).Error
+}
@@ -0,0 +1,76 @@
+// internal/repository/patch_review.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type PatchReviewRepository interface {
+	CreateOne(review *model.PatchReview) error
+	UpdateOne(review *model.PatchReview) error
+	CreateFiles(files []model.PatchReviewFile) error
+	CreateFindings(findings []model.PatchFinding) error
+	GetOneByID(id uint) (*model.PatchReview, error)
+	GetManyByProjectID(projectID uint) ([]model.PatchReview, error)
+}
+
+type GormPatchReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPatchReviewRepository(db *gorm.DB) *GormPatchReviewRepository {
+	return &GormPatchReviewRepository{db: db}
+}
+
+func (repo *GormPatchReviewRepository) CreateOne(review *model.PatchReview) error {
+	return repo.db.Omit("Files", "Findings").Create(review).Error
+}
+
+func (repo *GormPatchReviewRepository) UpdateOne(review *model.PatchReview) error {
+	return repo.db.Omit("Files", "Findings").Save(review).Error
+}
+
+func (repo *GormPatchReviewRepository) CreateFiles(files []model.PatchReviewFile) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return repo.db.Create(&files).Error
+}
+
+func (repo *GormPatchReviewRepository) CreateFindings(findings []model.PatchFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+	return repo.db.Create(&findings).Error
+}
+
+// GetOneByID returns the review with its files and findings
+func (repo *GormPatchReviewRepository) GetOneByID(id uint) (*model.PatchReview, error) {
+	var review model.PatchReview
+	err := repo.db.
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("path") }).
+		Preload("Findings", func(db *gorm.DB) *gorm.DB { return db.Order("path, line, id") }).
+		First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetManyByProjectID returns the project's reviews, newest first, with their files and findings
+func (repo *GormPatchReviewRepository) GetManyByProjectID(projectID uint) ([]model.PatchReview, error) {
+	var reviews []model.PatchReview
+	err := repo.db.
+		Preload("Files", func(db *gorm.DB) *gorm.DB { return db.Order("path") }).
+		Preload("Findings", func(db *gorm.DB) *gorm.DB { return db.Order("path, line, id") }).
+		Where("project_id = ?", projectID).
+		Order("id DESC").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
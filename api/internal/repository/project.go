@@ -15,6 +15,7 @@ type ProjectRepository interface {
 	GetAll() ([]model.Project, error)
 	GetAllProjects() ([]model.Project, error)
 	GetProjectByID(projectID uint) (model.Project, error)
+	GetVersions(rootID uint) ([]model.Project, error)
 }
 
 type GormProjectRepository struct {
@@ -64,3 +65,12 @@ func (repo *GormProjectRepository) GetProjectByID(projectID uint) (model.Project
 	}
 	return project, nil
 }
+
+// GetVersions returns every version of the project with the given root, oldest first
+func (repo *GormProjectRepository) GetVersions(rootID uint) ([]model.Project, error) {
+	var projects []model.Project
+	if err := repo.db.Where("id = ? OR root_project_id = ?", rootID, rootID).Order("version, id").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
@@ -13,6 +13,7 @@ type ProjectAnalysisRepository interface {
 	CreateOne(analysis *model.ProjectAnalysisResult) error
 	//GetFilesByProjectID(projectID uint) ([]model.ProjectFile, error)
 	GetResultsByProjectID(projectID uint) ([]model.ProjectAnalysisResult, error)
+	GetOneByInputHash(projectID uint, promptName, inputHash string) (*model.ProjectAnalysisResult, error)
 	// Add more methods as needed
 }
 
@@ -42,3 +43,18 @@ func (repo *GormProjectAnalysisRepository) GetResultsByProjectID(projectID uint)
 	}
 	return results, nil
 }
+
+// GetOneByInputHash returns the latest completed result of the prompt produced from the
+// same input, or nil when there is none
+func (repo *GormProjectAnalysisRepository) GetOneByInputHash(projectID uint, promptName, inputHash string) (*model.ProjectAnalysisResult, error) {
+	var results []model.ProjectAnalysisResult
+	err := repo.db.Where("project_id = ? AND prompt_name = ? AND input_hash = ? AND status = ?", projectID, promptName, inputHash, model.AnalysisStatusCompleted).
+		Order("id desc").Limit(1).Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, nil
+	}
+	return &results[0], nil
+}
@@ -19,6 +19,7 @@ type ProjectFileRepository interface {
 	GetRootFileContentByName(projectID uint, fileName string) (string, error)
 	GetFilesByProjectID(projectID uint) ([]model.ProjectFile, error)
 	GetFilesWithAnalysisByProjectID(projectID uint) ([]model.ProjectFile, error)
+	UpdateSummary(id uint, summary string, gptCallID uint) error
 }
 
 type GormProjectFileRepository struct {
@@ -119,3 +120,10 @@ func (repo *GormProjectFileRepository) GetFilesWithAnalysisByProjectID(projectID
 	}
 	return files, nil
 }
+
+func (repo *GormProjectFileRepository) UpdateSummary(id uint, summary string, gptCallID uint) error {
+	return repo.db.Model(&model.ProjectFile{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"summary":             summary,
+		"summary_gpt_call_id": gptCallID,
+	}).Error
+}
@@ -0,0 +1,48 @@
+// internal/repository/qa_message.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type QAMessageRepository interface {
+	CreateOne(message *model.QAMessage) error
+	GetManyByProjectID(projectID uint) ([]model.QAMessage, error)
+	GetLastByProjectID(projectID uint, limit int) ([]model.QAMessage, error)
+}
+
+type GormQAMessageRepository struct {
+	db *gorm.DB
+}
+
+func NewGormQAMessageRepository(db *gorm.DB) *GormQAMessageRepository {
+	return &GormQAMessageRepository{db: db}
+}
+
+func (repo *GormQAMessageRepository) CreateOne(message *model.QAMessage) error {
+	return repo.db.Create(message).Error
+}
+
+// GetManyByProjectID returns the project's conversation in order
+func (repo *GormQAMessageRepository) GetManyByProjectID(projectID uint) ([]model.QAMessage, error) {
+	var messages []model.QAMessage
+	if err := repo.db.Where("project_id = ?", projectID).Order("id").Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetLastByProjectID returns the latest messages of the project's conversation in order
+func (repo *GormQAMessageRepository) GetLastByProjectID(projectID uint, limit int) ([]model.QAMessage, error) {
+	var messages []model.QAMessage
+	if err := repo.db.Where("project_id = ?", projectID).Order("id desc").Limit(limit).Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
@@ -0,0 +1,104 @@
+// internal/repository/suppression.go
+
+package repository
+
+import (
+	"evraz_api/internal/model"
+
+	"gorm.io/gorm"
+)
+
+type SuppressionRepository interface {
+	CreateRule(rule *model.SuppressionRule) error
+	GetRuleByID(id uint) (*model.SuppressionRule, error)
+	GetRulesByProjectIDs(projectIDs []uint) ([]model.SuppressionRule, error)
+	DeleteRule(id uint) error
+	CreateBaseline(baseline *model.Baseline) error
+	GetLatestBaseline(projectIDs []uint) (*model.Baseline, error)
+	DeleteBaselines(projectIDs []uint) error
+}
+
+type GormSuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSuppressionRepository(db *gorm.DB) *GormSuppressionRepository {
+	return &GormSuppressionRepository{db: db}
+}
+
+func (repo *GormSuppressionRepository) CreateRule(rule *model.SuppressionRule) error {
+	return repo.db.Create(rule).Error
+}
+
+func (repo *GormSuppressionRepository) GetRuleByID(id uint) (*model.SuppressionRule, error) {
+	var rule model.SuppressionRule
+	if err := repo.db.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (repo *GormSuppressionRepository) GetRulesByProjectIDs(projectIDs []uint) ([]model.SuppressionRule, error) {
+	var rules []model.SuppressionRule
+	if len(projectIDs) == 0 {
+		return rules, nil
+	}
+	if err := repo.db.Where("project_id IN ?", projectIDs).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (repo *GormSuppressionRepository) DeleteRule(id uint) error {
+	return repo.db.Delete(&model.SuppressionRule{}, id).Error
+}
+
+// CreateBaseline stores the baseline together with its fingerprints
+func (repo *GormSuppressionRepository) CreateBaseline(baseline *model.Baseline) error {
+	return repo.db.Transaction(func(tx *gorm.DB) error {
+		fingerprints := baseline.Fingerprints
+		baseline.Fingerprints = nil
+		if err := tx.Create(baseline).Error; err != nil {
+			return err
+		}
+		for i := range fingerprints {
+			fingerprints[i].BaselineID = baseline.ID
+		}
+		if len(fingerprints) > 0 {
+			if err := tx.CreateInBatches(&fingerprints, 500).Error; err != nil {
+				return err
+			}
+		}
+		baseline.Fingerprints = fingerprints
+		return nil
+	})
+}
+
+// GetLatestBaseline returns the newest baseline of the projects with its fingerprints, or
+// nil when none of them has one
+func (repo *GormSuppressionRepository) GetLatestBaseline(projectIDs []uint) (*model.Baseline, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	var baselines []model.Baseline
+	err := repo.db.Preload("Fingerprints").
+		Where("project_id IN ?", projectIDs).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&baselines).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(baselines) == 0 {
+		return nil, nil
+	}
+	return &baselines[0], nil
+}
+
+// DeleteBaselines removes the baselines of the projects
+func (repo *GormSuppressionRepository) DeleteBaselines(projectIDs []uint) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	return repo.db.Where("project_id IN ?", projectIDs).Delete(&model.Baseline{}).Error
+}
@@ -0,0 +1,152 @@
+// internal/retrieval/bm25.go
+
+package retrieval
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, the usual defaults
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+var stopWords = map[string]bool{
+	"the": true, "a": true, "an": true, "and": true, "or": true, "of": true, "to": true,
+	"in": true, "on": true, "for": true, "is": true, "are": true, "do": true, "does": true,
+	"we": true, "where": true, "what": true, "how": true, "which": true, "it": true,
+	"self": true, "return": true, "import": true, "from": true, "none": true,
+	"и": true, "в": true, "не": true, "на": true, "с": true, "по": true, "для": true,
+	"где": true, "как": true, "что": true, "мы": true, "ли": true,
+}
+
+// Tokenize splits text into lowercase, stemmed terms. Identifiers are indexed whole and by their
+// snake_case and camelCase parts, so "open_transaction" matches "transaction".
+func Tokenize(text string) []string {
+	var terms []string
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	for _, word := range words {
+		parts := identifierParts(word)
+		if len(parts) > 1 {
+			parts = append(parts, word)
+		}
+		for _, part := range parts {
+			part = strings.ToLower(part)
+			if len([]rune(part)) < 2 || stopWords[part] {
+				continue
+			}
+			terms = append(terms, stem(part))
+		}
+	}
+	return terms
+}
+
+// stem folds English plurals, "transactions" into "transaction"
+func stem(term string) string {
+	if len(term) > 3 && strings.HasSuffix(term, "s") && !strings.HasSuffix(term, "ss") {
+		return term[:len(term)-1]
+	}
+	return term
+}
+
+func identifierParts(word string) []string {
+	var parts []string
+	for _, piece := range strings.Split(word, "_") {
+		start := 0
+		runes := []rune(piece)
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]) {
+				parts = append(parts, string(runes[start:i]))
+				start = i
+			}
+		}
+		if start < len(runes) {
+			parts = append(parts, string(runes[start:]))
+		}
+	}
+	return parts
+}
+
+// TermFrequencies counts the terms of a text
+func TermFrequencies(text string) (map[string]int, int) {
+	frequencies := make(map[string]int)
+	terms := Tokenize(text)
+	for _, term := range terms {
+		frequencies[term]++
+	}
+	return frequencies, len(terms)
+}
+
+// Document is an indexed chunk: its term frequencies and number of terms
+type Document struct {
+	Terms  map[string]int
+	Length int
+}
+
+// Index scores documents against queries with BM25
+type Index struct {
+	documents     []Document
+	docFrequency  map[string]int
+	averageLength float64
+}
+
+// NewIndex builds an index over the documents
+func NewIndex(documents []Document) *Index {
+	index := &Index{documents: documents, docFrequency: make(map[string]int)}
+	total := 0
+	for _, document := range documents {
+		total += document.Length
+		for term := range document.Terms {
+			index.docFrequency[term]++
+		}
+	}
+	if len(documents) > 0 {
+		index.averageLength = float64(total) / float64(len(documents))
+	}
+	return index
+}
+
+// Result is a document matching a query
+type Result struct {
+	Document int // Index of the document
+	Score    float64
+}
+
+// Search returns up to limit documents matching the query, best first
+func (index *Index) Search(query string, limit int) []Result {
+	queryTerms := make(map[string]bool)
+	for _, term := range Tokenize(query) {
+		queryTerms[term] = true
+	}
+
+	n := float64(len(index.documents))
+	var results []Result
+	for i, document := range index.documents {
+		score := 0.0
+		for term := range queryTerms {
+			frequency := float64(document.Terms[term])
+			if frequency == 0 {
+				continue
+			}
+			df := float64(index.docFrequency[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(document.Length)/index.averageLength
+			score += idf * frequency * (bm25K1 + 1) / (frequency + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Document: i, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(a, b int) bool { return results[a].Score > results[b].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
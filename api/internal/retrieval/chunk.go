@@ -0,0 +1,66 @@
+// internal/retrieval/chunk.go
+
+package retrieval
+
+import (
+	"strings"
+
+	"evraz_api/internal/model"
+)
+
+const (
+	chunkLines   = 40 // Lines of a chunk
+	chunkOverlap = 10 // Lines shared by consecutive chunks
+	maxFileLines = 5000
+)
+
+// Chunk is a range of lines of a project file
+type Chunk struct {
+	StartLine int
+	EndLine   int
+	Content   string
+}
+
+// ChunkFile splits a file into overlapping line windows. A window is moved back to start
+// at a definition when one is close, so functions are rarely cut in the middle.
+func ChunkFile(file model.ProjectFile) []Chunk {
+	if strings.TrimSpace(file.Content) == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(file.Content, "\n"), "\n")
+	if len(lines) > maxFileLines {
+		lines = lines[:maxFileLines]
+	}
+
+	var chunks []Chunk
+	for start := 0; start < len(lines); {
+		end := start + chunkLines
+		if end > len(lines) {
+			end = len(lines)
+		}
+		chunks = append(chunks, Chunk{
+			StartLine: start + 1,
+			EndLine:   end,
+			Content:   strings.Join(lines[start:end], "\n"),
+		})
+		if end == len(lines) {
+			break
+		}
+
+		next := end - chunkOverlap
+		for i := next; i < end; i++ {
+			if isDefinition(lines[i]) {
+				next = i
+				break
+			}
+		}
+		start = next
+	}
+	return chunks
+}
+
+func isDefinition(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "def ") || strings.HasPrefix(trimmed, "async def ") ||
+		strings.HasPrefix(trimmed, "class ") || strings.HasPrefix(trimmed, "@")
+}
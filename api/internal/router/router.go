@@ -15,7 +15,7 @@ func SetupRouter(container *di.DIContainer) *gin.Engine {
 
 	config := cors.Config{
 		AllowAllOrigins:  true, // Allow requests from any origin
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true, // Enable cookies or HTTP auth
@@ -34,7 +34,32 @@ func SetupRouter(container *di.DIContainer) *gin.Engine {
 
 		projectsGroup.GET("/all", container.ProjectHandlers.GetAllProjects)
 		projectsGroup.GET("/:project_id/overview", container.ProjectHandlers.GetProjectOverview)
+		projectsGroup.GET("/:project_id/versions", container.ProjectHandlers.GetProjectVersions)
 		projectsGroup.GET("/:project_id/generate_pdf", container.ProjectHandlers.GenerateProjectPDF)
+		projectsGroup.GET("/:project_id/export/sarif", container.ProjectHandlers.ExportSARIF)
+		projectsGroup.GET("/:project_id/export/codeclimate", container.ProjectHandlers.ExportCodeClimate)
+		projectsGroup.GET("/:project_id/export/junit", container.ProjectHandlers.ExportJUnit)
+		projectsGroup.PUT("/:project_id/settings", container.ProjectHandlers.UpdateProjectSettings)
+		projectsGroup.POST("/:project_id/translate", container.ProjectHandlers.TranslateProject)
+		projectsGroup.POST("/:project_id/linters", container.ProjectHandlers.ImportLinterReport)
+		projectsGroup.GET("/:project_id/findings", container.ProjectHandlers.GetFindings)
+		projectsGroup.PATCH("/:project_id/findings/:finding_id", container.ProjectHandlers.UpdateFindingTriage)
+		projectsGroup.POST("/:project_id/findings/:finding_id/comments", container.ProjectHandlers.AddFindingComment)
+		projectsGroup.GET("/:project_id/findings/:finding_id/comments", container.ProjectHandlers.GetFindingComments)
+		projectsGroup.GET("/:project_id/findings/:finding_id/history", container.ProjectHandlers.GetFindingHistory)
+		projectsGroup.GET("/:project_id/suppressions", container.ProjectHandlers.GetSuppressions)
+		projectsGroup.POST("/:project_id/suppressions", container.ProjectHandlers.CreateSuppressionRule)
+		projectsGroup.DELETE("/:project_id/suppressions/:suppression_id", container.ProjectHandlers.DeleteSuppressionRule)
+		projectsGroup.POST("/:project_id/baseline", container.ProjectHandlers.CreateBaseline)
+		projectsGroup.DELETE("/:project_id/baseline", container.ProjectHandlers.DeleteBaseline)
+		projectsGroup.GET("/:project_id/runs", container.ProjectHandlers.GetRuns)
+		projectsGroup.GET("/:project_id/diff", container.ProjectHandlers.GetRunDiff)
+		projectsGroup.GET("/:project_id/gate", container.ProjectHandlers.GetGate)
+		projectsGroup.POST("/:project_id/review_patch", container.ProjectHandlers.ReviewPatch)
+		projectsGroup.GET("/:project_id/patch_reviews", container.ProjectHandlers.GetPatchReviews)
+		projectsGroup.GET("/:project_id/agent_tool_calls", container.ProjectHandlers.GetAgentToolCalls)
+		projectsGroup.POST("/:project_id/ask", container.ProjectHandlers.Ask)
+		projectsGroup.GET("/:project_id/conversation", container.ProjectHandlers.GetConversation)
 	}
 	filesGroup := apiGroup.Group("/files")
 	{
@@ -0,0 +1,105 @@
+// internal/service/mistral_embeddings.go
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+const (
+	defaultEmbeddingsURL   = "https://api.mistral.ai/v1/embeddings"
+	defaultEmbeddingsModel = "mistral-embed"
+	embeddingsBatchSize    = 64
+)
+
+type EmbeddingsRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type EmbeddingsResponse struct {
+	Data []struct {
+		Embedding []float64 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+}
+
+// Embed returns an embedding for every text, in order, using the configured provider
+func (ms *MistralService) Embed(texts []string) ([][]float64, error) {
+	url := os.Getenv("MISTRAL_EMBEDDINGS_URL")
+	if url == "" {
+		url = defaultEmbeddingsURL
+	}
+	embeddingsModel := os.Getenv("MISTRAL_EMBEDDINGS_MODEL")
+	if embeddingsModel == "" {
+		embeddingsModel = defaultEmbeddingsModel
+	}
+
+	embeddings := make([][]float64, 0, len(texts))
+	for start := 0; start < len(texts); start += embeddingsBatchSize {
+		end := start + embeddingsBatchSize
+		if end > len(texts) {
+			end = len(texts)
+		}
+
+		batch, err := ms.embedBatch(url, embeddingsModel, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, batch...)
+	}
+	return embeddings, nil
+}
+
+func (ms *MistralService) embedBatch(url, embeddingsModel string, texts []string) ([][]float64, error) {
+	jsonValue, err := json.Marshal(EmbeddingsRequest{Model: embeddingsModel, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal embeddings request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if url == defaultEmbeddingsURL {
+		req.Header.Set("Authorization", "Bearer "+ms.apiKey)
+	} else {
+		req.Header.Set("Authorization", ms.apiKey)
+	}
+
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("embeddings request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embeddings response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("embeddings request returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var embeddingsResponse EmbeddingsResponse
+	if err := json.Unmarshal(body, &embeddingsResponse); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal embeddings response: %w", err)
+	}
+	if len(embeddingsResponse.Data) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddingsResponse.Data))
+	}
+
+	embeddings := make([][]float64, len(texts))
+	for _, item := range embeddingsResponse.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return nil, fmt.Errorf("embedding index %d out of range", item.Index)
+		}
+		embeddings[item.Index] = item.Embedding
+	}
+	return embeddings, nil
+}
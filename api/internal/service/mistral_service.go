@@ -28,8 +28,11 @@ const (
 )
 
 type ChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role       string     `json:"role"`
+	Content    string     `json:"content"`
+	ToolCalls  []ToolCall `json:"tool_calls,omitempty"`   // Tools the assistant asked to call
+	ToolCallID string     `json:"tool_call_id,omitempty"` // Call a "tool" message answers
+	Name       string     `json:"name,omitempty"`         // Tool a "tool" message comes from
 }
 
 type ChatRequest struct {
@@ -0,0 +1,153 @@
+// internal/service/mistral_tools.go
+
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"evraz_api/internal/model"
+)
+
+// ToolDefinition describes a function the model may call
+type ToolDefinition struct {
+	Type     string       `json:"type"` // Always "function"
+	Function ToolFunction `json:"function"`
+}
+
+type ToolFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Parameters  map[string]any `json:"parameters"` // JSON schema of the arguments
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type,omitempty"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON-encoded arguments
+	} `json:"function"`
+}
+
+type ToolChatRequest struct {
+	Model       string           `json:"model"`
+	Messages    []ChatMessage    `json:"messages"`
+	Tools       []ToolDefinition `json:"tools,omitempty"`
+	ToolChoice  string           `json:"tool_choice,omitempty"`
+	MaxTokens   int              `json:"max_tokens"`
+	Temperature float64          `json:"temperature"`
+}
+
+type ToolChatResponse struct {
+	Choices []struct {
+		Message      ChatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// ChatWithTools sends a conversation in which the model may call the given tools and
+// returns the assistant message, the tokens the request used and the logged GPT call.
+// Without tools the model has to answer with content.
+func (ms *MistralService) ChatWithTools(messages []ChatMessage, tools []ToolDefinition, entityType string, entityID uint) (ChatMessage, int, uint, error) {
+	requestBody := ToolChatRequest{
+		Model:       ms.model,
+		Messages:    messages,
+		Tools:       tools,
+		MaxTokens:   1024,
+		Temperature: 0.3,
+	}
+	if len(tools) > 0 {
+		requestBody.ToolChoice = "auto"
+	}
+
+	jsonValue, err := json.Marshal(requestBody)
+	if err != nil {
+		return ChatMessage{}, 0, 0, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	body, err := ms.postChat(jsonValue)
+	if err != nil {
+		return ChatMessage{}, 0, 0, err
+	}
+
+	var chatResponse ToolChatResponse
+	if err := json.Unmarshal(body, &chatResponse); err != nil {
+		return ChatMessage{}, 0, 0, fmt.Errorf("failed to unmarshal JSON response: %w", err)
+	}
+	if len(chatResponse.Choices) == 0 {
+		return ChatMessage{}, 0, 0, fmt.Errorf("no choices in JSON response")
+	}
+	message := chatResponse.Choices[0].Message
+
+	// Log the exchange; the reply of a tool-calling turn is the list of requested calls
+	reply := message.Content
+	if len(message.ToolCalls) > 0 {
+		toolCalls, _ := json.Marshal(message.ToolCalls)
+		reply = string(toolCalls)
+	}
+	conversation, _ := json.Marshal(messages)
+	gptCall := model.GPTCall{
+		FinalPrompt:      string(conversation),
+		Reply:            reply,
+		EntityType:       entityType,
+		EntityID:         entityID,
+		PromptTokens:     chatResponse.Usage.PromptTokens,
+		CompletionTokens: chatResponse.Usage.CompletionTokens,
+		TotalTokens:      chatResponse.Usage.TotalTokens,
+	}
+	gptCallID, err := ms.GPTCallRepo.CreateOne(&gptCall)
+	if err != nil {
+		return message, chatResponse.Usage.TotalTokens, 0, fmt.Errorf("failed to log GPT call: %w", err)
+	}
+	return message, chatResponse.Usage.TotalTokens, gptCallID, nil
+}
+
+// postChat sends a chat completion request, retrying on rate limits and server errors
+func (ms *MistralService) postChat(jsonValue []byte) ([]byte, error) {
+	const maxAttempts = 5
+	client := &http.Client{}
+	for attempts := 1; ; attempts++ {
+		req, err := http.NewRequest("POST", ms.url, bytes.NewBuffer(jsonValue))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if ms.url == "https://api.mistral.ai/v1/chat/completions" {
+			req.Header.Set("Authorization", "Bearer "+ms.apiKey)
+		} else {
+			req.Header.Set("Authorization", ms.apiKey)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			return body, nil
+		}
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+		if !retryable || attempts == maxAttempts {
+			return nil, fmt.Errorf("received %d response after %d attempts: %s", resp.StatusCode, attempts, string(body))
+		}
+		sleepTime := time.Duration(attempts*attempts) * time.Second
+		fmt.Printf("Received %d response, retrying in %v\n", resp.StatusCode, sleepTime)
+		time.Sleep(sleepTime)
+	}
+}
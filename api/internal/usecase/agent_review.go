@@ -0,0 +1,83 @@
+// internal/usecase/agent_review.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+
+	"evraz_api/internal/model"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/service"
+)
+
+// runAgentCheck runs a project-level check as an agent loop: the model may call the file
+// tools until it answers or runs out of turns or tokens, after which it is asked for the
+// answer without tools. Every tool call is logged. It returns the final reply and the GPT
+// call that produced it.
+func (uc *ProjectAnalysisUsecase) runAgentCheck(actx *analysisContext, project *model.Project, promptName, prompt string) (string, uint, error) {
+	messages := []service.ChatMessage{
+		{Role: "system", Content: fmt.Sprintf(helper_prompts.ReviewAgentSystemPrompt, uc.Options.AgentMaxSteps, treeOrSummary(project))},
+		{Role: "user", Content: prompt},
+	}
+	tools := actx.Toolbox.Definitions()
+
+	// The last turn is kept for the answer, which is requested without tools
+	tokensUsed := 0
+	for step := 1; step < uc.Options.AgentMaxSteps && tokensUsed < uc.Options.AgentMaxTokens; step++ {
+		message, tokens, gptCallID, err := uc.MistralService.ChatWithTools(messages, tools, "project", project.ID)
+		if err != nil {
+			return "", 0, fmt.Errorf("agent step %d failed: %w", step, err)
+		}
+		tokensUsed += tokens
+		if len(message.ToolCalls) == 0 {
+			return message.Content, gptCallID, nil
+		}
+
+		message.Role = "assistant"
+		messages = append(messages, message)
+		for _, call := range message.ToolCalls {
+			result, toolErr := actx.Toolbox.Execute(call.Function.Name, call.Function.Arguments)
+			audit := &model.AgentToolCall{
+				ProjectID:     project.ID,
+				AnalysisRunID: &actx.RunID,
+				PromptName:    promptName,
+				GPTCallID:     gptCallID,
+				Step:          step,
+				ToolName:      call.Function.Name,
+				Arguments:     call.Function.Arguments,
+				Result:        result,
+			}
+			if toolErr != nil {
+				audit.Error = toolErr.Error()
+				result = "error: " + toolErr.Error()
+			}
+			if err := uc.AgentToolCallRepo.CreateOne(audit); err != nil {
+				log.Printf("failed to log agent tool call of %s: %v", promptName, err)
+			}
+
+			messages = append(messages, service.ChatMessage{
+				Role:       "tool",
+				Name:       call.Function.Name,
+				ToolCallID: call.ID,
+				Content:    result,
+			})
+		}
+	}
+
+	messages = append(messages, service.ChatMessage{Role: "user", Content: helper_prompts.ReviewAgentFinalMessage})
+	message, _, gptCallID, err := uc.MistralService.ChatWithTools(messages, nil, "project", project.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("agent answer failed: %w", err)
+	}
+	return message.Content, gptCallID, nil
+}
+
+// GetAgentToolCalls returns the tool calls review agents made for the project
+func (uc *ProjectAnalysisUsecase) GetAgentToolCalls(projectID uint) ([]model.AgentToolCall, error) {
+	calls, err := uc.AgentToolCallRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve agent tool calls: %w", err)
+	}
+	return calls, nil
+}
@@ -0,0 +1,95 @@
+// internal/usecase/analysis_run.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"evraz_api/internal/agent"
+	"evraz_api/internal/findings"
+	"evraz_api/internal/model"
+)
+
+// analysisContext carries the state shared by all checks of one analysis run
+type analysisContext struct {
+	RunID   uint
+	Index   *findings.SymbolIndex // Symbols and paths findings are checked against
+	Toolbox *agent.Toolbox        // File tools of project-level checks; nil when the agent is off
+}
+
+func (uc *ProjectAnalysisUsecase) startRun(projectID uint, projectFileID *uint, scope, language string) (*model.AnalysisRun, error) {
+	run := &model.AnalysisRun{
+		ProjectID:     projectID,
+		ProjectFileID: projectFileID,
+		Scope:         scope,
+		Status:        model.RunStatusRunning,
+		Language:      language,
+	}
+	if err := uc.AnalysisRunRepo.CreateOne(run); err != nil {
+		return nil, fmt.Errorf("failed to create analysis run: %w", err)
+	}
+	return run, nil
+}
+
+// finishRun records the outcome of the run, a snapshot of the project's findings and
+// verdicts for run-to-run diffs, and the statistics of the findings the run produced
+func (uc *ProjectAnalysisUsecase) finishRun(run *model.AnalysisRun, runErr error) {
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Status = model.RunStatusCompleted
+	if runErr != nil {
+		run.Status = model.RunStatusFailed
+		run.Error = runErr.Error()
+	}
+	if err := uc.AnalysisRunRepo.UpdateOne(run); err != nil {
+		log.Printf("failed to update analysis run %d: %v", run.ID, err)
+	}
+	if runErr == nil {
+		if err := uc.snapshotRun(run); err != nil {
+			log.Printf("failed to snapshot analysis run %d: %v", run.ID, err)
+		}
+	}
+
+	runFindings, err := uc.FindingRepo.GetManyByAnalysisRunID(run.ID)
+	if err != nil {
+		log.Printf("failed to retrieve findings of analysis run %d: %v", run.ID, err)
+		return
+	}
+
+	byPrompt := make(map[string]*model.AnalysisRunPromptStat)
+	for _, finding := range runFindings {
+		stat, ok := byPrompt[finding.RuleName]
+		if !ok {
+			stat = &model.AnalysisRunPromptStat{AnalysisRunID: run.ID, PromptName: finding.RuleName}
+			byPrompt[finding.RuleName] = stat
+		}
+		stat.Findings++
+		switch {
+		case finding.Hallucinated:
+			stat.Hallucinated++
+		case finding.Hidden && finding.Supported != nil:
+			stat.CriticRejected++
+		}
+	}
+
+	stats := make([]model.AnalysisRunPromptStat, 0, len(byPrompt))
+	for _, stat := range byPrompt {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].PromptName < stats[j].PromptName })
+	if err := uc.AnalysisRunRepo.CreatePromptStats(stats); err != nil {
+		log.Printf("failed to save statistics of analysis run %d: %v", run.ID, err)
+	}
+}
+
+// GetRuns returns the project's analysis runs, newest first
+func (uc *ProjectAnalysisUsecase) GetRuns(projectID uint) ([]model.AnalysisRun, error) {
+	runs, err := uc.AnalysisRunRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve analysis runs: %w", err)
+	}
+	return runs, nil
+}
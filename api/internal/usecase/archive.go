@@ -0,0 +1,68 @@
+// internal/usecase/archive.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+
+	"evraz_api/internal/service"
+)
+
+// extractArchive saves an uploaded archive into the directory, extracts it there and
+// returns the root of the extracted files: the directory itself, or the only directory
+// the archive contains
+func extractArchive(fileManager service.FileManager, header *multipart.FileHeader, extractPath string) (string, error) {
+	// Define the path where the file will be saved
+	tempFilePath := filepath.Join(extractPath, header.Filename)
+
+	// Save the uploaded file
+	if err := fileManager.SaveUploadedFile(header, tempFilePath); err != nil {
+		return "", errors.New("Failed to save file")
+	}
+
+	// Determine file type and extract accordingly
+	var err error
+	switch filepath.Ext(header.Filename) {
+	case ".zip":
+		err = fileManager.ExtractZip(tempFilePath, extractPath)
+	case ".tar.gz", ".tgz":
+		err = fileManager.ExtractTarGz(tempFilePath, extractPath)
+	case ".7z":
+		err = fileManager.Extract7z(tempFilePath, extractPath)
+	default:
+		return "", errors.New("unsupported file type")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract archive: %w", err)
+	}
+
+	// Delete the temporary archive file
+	if err := fileManager.RemovePath(tempFilePath); err != nil {
+		return "", errors.New("Failed to remove temp file")
+	}
+
+	entries, err := os.ReadDir(extractPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read extracted directory: %w", err)
+	}
+
+	// Filter out __MACOSX entries
+	filteredEntries := []os.DirEntry{}
+	for _, entry := range entries {
+		if entry.Name() != "__MACOSX" {
+			filteredEntries = append(filteredEntries, entry)
+		}
+	}
+
+	if len(filteredEntries) == 1 && filteredEntries[0].IsDir() {
+		// Only one directory (excluding __MACOSX), adjust the extractedPath
+		extractedPath := filepath.Join(extractPath, filteredEntries[0].Name())
+		fmt.Printf("Only one subdirectory detected, adjusting extractedPath to %s\n", extractedPath)
+		return extractedPath, nil
+	}
+	return extractPath, nil
+}
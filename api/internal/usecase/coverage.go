@@ -0,0 +1,85 @@
+// internal/usecase/coverage.go
+
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"sort"
+
+	"evraz_api/internal/ingest"
+	"evraz_api/internal/model"
+)
+
+// uploadedReport is a report file found in the upload or passed alongside it
+type uploadedReport struct {
+	Path    string
+	Content []byte
+}
+
+// importCoverage parses the coverage reports and stores the coverage of every project file
+// they mention; later reports override earlier ones. Reports that cannot be parsed are skipped.
+func (uc *ProjectUsecase) importCoverage(projectID uint, files []model.ProjectFile, reports []uploadedReport) error {
+	matcher := ingest.NewPathMatcher(files)
+	byFile := make(map[uint]model.FileCoverage)
+
+	for _, uploaded := range reports {
+		reportPath := uploaded.Path
+		report, err := ingest.ParseCoverage(uploaded.Content)
+		if err != nil {
+			log.Printf("Skipping coverage report %s: %v", reportPath, err)
+			continue
+		}
+
+		matched := 0
+		for _, fileCoverage := range report.Files {
+			file, ok := matcher.Match(fileCoverage.Path)
+			if !ok {
+				continue
+			}
+			matched++
+			byFile[file.ID] = model.FileCoverage{
+				ProjectID:       projectID,
+				ProjectFileID:   file.ID,
+				ReportPath:      reportPath,
+				LinesValid:      fileCoverage.LinesValid,
+				LinesCovered:    fileCoverage.LinesCovered,
+				BranchesValid:   fileCoverage.BranchesValid,
+				BranchesCovered: fileCoverage.BranchesCovered,
+				MissingLines:    ingest.FormatLineRanges(fileCoverage.MissingLines),
+			}
+		}
+		fmt.Printf("Coverage report %s (%s): %d of %d files matched\n", reportPath, report.Format, matched, len(report.Files))
+	}
+
+	coverage := make([]model.FileCoverage, 0, len(byFile))
+	for _, fileCoverage := range byFile {
+		coverage = append(coverage, fileCoverage)
+	}
+	sort.Slice(coverage, func(i, j int) bool { return coverage[i].ProjectFileID < coverage[j].ProjectFileID })
+
+	if err := uc.FileCoverageRepo.DeleteByProjectID(projectID); err != nil {
+		return fmt.Errorf("failed to delete previous coverage: %w", err)
+	}
+	if err := uc.FileCoverageRepo.CreateMany(coverage); err != nil {
+		return fmt.Errorf("failed to save coverage: %w", err)
+	}
+	return nil
+}
+
+// readUploadedReport reads a report passed as a separate multipart field
+func readUploadedReport(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// GetProjectCoverage returns the stored coverage of the project's files
+func (uc *ProjectUsecase) GetProjectCoverage(projectID uint) ([]model.FileCoverage, error) {
+	return uc.FileCoverageRepo.GetManyByProjectID(projectID)
+}
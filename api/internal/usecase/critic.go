@@ -0,0 +1,89 @@
+// internal/usecase/critic.go
+
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/model"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+// criticVerdict is the critic's assessment of a single candidate finding
+type criticVerdict struct {
+	ID         int     `json:"id"`
+	Supported  bool    `json:"supported"`
+	Confidence float64 `json:"confidence"`
+	Reason     string  `json:"reason"`
+}
+
+// runCritic asks the critic prompt to verify the review findings of a file. Findings the
+// critic rejects or is not confident in are hidden; findings without a verdict stay visible.
+func (uc *ProjectAnalysisUsecase) runCritic(file *model.ProjectFile, llmLanguage string) error {
+	fileFindings, err := uc.FindingRepo.GetManyByProjectFileID(file.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve file findings: %w", err)
+	}
+
+	var candidates []model.Finding
+	var sb strings.Builder
+	for _, finding := range fileFindings {
+		if finding.Source != model.FindingSourceLLM {
+			continue
+		}
+		candidates = append(candidates, finding)
+		sb.WriteString(fmt.Sprintf("%d. [%s] %s\n", len(candidates), finding.RuleName, finding.Message))
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	data := helper_prompts.CriticData{
+		FilePath:    file.Path,
+		FileContent: file.Content,
+		Findings:    sb.String(),
+	}
+	prompt, err := uc.PromptConstructor.GetPrompt(helper_prompts.CriticPrompt, data, llmLanguage, true)
+	if err != nil {
+		return fmt.Errorf("failed to construct critic prompt: %w", err)
+	}
+
+	reply, gptCallID, err := uc.MistralService.CallMistral(prompt, true, service.Hack, "critic", file.ID)
+	if err != nil {
+		return fmt.Errorf("failed to call Mistral service for critic: %w", err)
+	}
+
+	var criticResponse struct {
+		Verdicts []criticVerdict `json:"verdicts"`
+	}
+	if err := utils.ExtractJSON(reply, &criticResponse); err != nil {
+		return fmt.Errorf("failed to parse critic response: %w", err)
+	}
+
+	var verified []model.Finding
+	for _, verdict := range criticResponse.Verdicts {
+		if verdict.ID < 1 || verdict.ID > len(candidates) {
+			continue
+		}
+		finding := candidates[verdict.ID-1]
+		supported := verdict.Supported
+		confidence := verdict.Confidence
+		if confidence < 0 {
+			confidence = 0
+		} else if confidence > 1 {
+			confidence = 1
+		}
+
+		finding.Supported = &supported
+		finding.Confidence = &confidence
+		finding.CriticReason = verdict.Reason
+		finding.CriticGPTCallID = &gptCallID
+		finding.Hidden = finding.Hallucinated || !supported || confidence < uc.Options.CriticMinConfidence
+		verified = append(verified, finding)
+	}
+
+	return uc.FindingRepo.UpdateColumns(verified, "supported", "confidence", "critic_reason", "critic_gpt_call_id", "hidden")
+}
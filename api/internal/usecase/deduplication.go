@@ -0,0 +1,121 @@
+// internal/usecase/deduplication.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"evraz_api/internal/findings"
+	"evraz_api/internal/model"
+)
+
+// saveReviewFindings stores the issues a review prompt raised as findings, replacing the
+// ones the prompt raised for the same project or file before. Findings the LLM raised that
+// cite symbols, paths or lines that do not exist are flagged by the hallucination guard and
+// hidden; issues the analysis states itself without an LLM call, such as missing key files,
+// are not checked.
+func (uc *ProjectAnalysisUsecase) saveReviewFindings(actx *analysisContext, projectID uint, projectFileID *uint, path, promptName string, issues []string, gptCallID uint) error {
+	var err error
+	if projectFileID != nil {
+		err = uc.FindingRepo.DeleteByProjectFileIDAndRule(*projectFileID, model.FindingSourceLLM, promptName)
+	} else {
+		err = uc.FindingRepo.DeleteByProjectIDAndRule(projectID, model.FindingSourceLLM, promptName)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete previous findings: %w", err)
+	}
+
+	var callID *uint
+	if gptCallID != 0 {
+		callID = &gptCallID
+	}
+
+	items := make([]model.Finding, 0, len(issues))
+	for _, issue := range issues {
+		if issue = strings.TrimSpace(issue); issue == "" {
+			continue
+		}
+		finding := model.Finding{
+			ProjectID:     projectID,
+			ProjectFileID: projectFileID,
+			AnalysisRunID: &actx.RunID,
+			Source:        model.FindingSourceLLM,
+			RuleName:      promptName,
+			Severity:      model.SeverityMedium,
+			Message:       issue,
+			Path:          path,
+			GPTCallID:     callID,
+		}
+		if callID == nil {
+			items = append(items, finding)
+			continue
+		}
+		if result := actx.Index.Check(finding); result.Flagged() {
+			finding.Hallucinated = true
+			finding.GuardReason = result.Reason()
+			finding.Hidden = true
+		}
+		items = append(items, finding)
+	}
+	return uc.FindingRepo.CreateMany(items)
+}
+
+// deduplicateFindings clusters the project's near-duplicate findings and keeps one
+// canonical finding per cluster that lists all the rules that raised it. Findings hidden
+// by the critic stay out of the clusters.
+func (uc *ProjectAnalysisUsecase) deduplicateFindings(projectID uint) error {
+	all, err := uc.FindingRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve findings: %w", err)
+	}
+
+	var items, hidden []model.Finding
+	for _, item := range all {
+		if item.Hidden {
+			item.CanonicalID, item.RuleNames, item.Occurrences = nil, "", 1
+			hidden = append(hidden, item)
+		} else {
+			items = append(items, item)
+		}
+	}
+	if len(items) == 0 && len(hidden) == 0 {
+		return nil
+	}
+
+	options := findings.DefaultOptions
+	if uc.Options.UseEmbeddings && len(items) > 0 {
+		messages := make([]string, len(items))
+		for i, item := range items {
+			messages[i] = item.Message
+		}
+		embeddings, err := uc.MistralService.Embed(messages)
+		if err != nil {
+			log.Printf("Embeddings unavailable, deduplicating by tokens only: %v", err)
+		} else {
+			options.Embeddings = embeddings
+		}
+	}
+
+	clusters := findings.Cluster(items, options)
+	for _, cluster := range clusters {
+		canonical := &items[cluster[0]]
+		canonical.CanonicalID = nil
+		canonical.RuleNames = strings.Join(findings.RuleNames(items, cluster), ", ")
+		canonical.Occurrences = len(cluster)
+
+		canonicalID := canonical.ID
+		for _, i := range cluster[1:] {
+			items[i].CanonicalID = &canonicalID
+			items[i].RuleNames = ""
+			items[i].Occurrences = 0
+		}
+	}
+	fmt.Printf("Deduplicated %d findings of project %d into %d\n", len(items), projectID, len(clusters))
+
+	if err := uc.FindingRepo.UpdateColumns(append(items, hidden...), "canonical_id", "rule_names", "occurrences"); err != nil {
+		return fmt.Errorf("failed to save finding clusters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// internal/usecase/finding.go
+
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/ingest"
+	"evraz_api/internal/model"
+	"evraz_api/internal/repository"
+)
+
+// maxKnownIssuesInPrompt limits the linter issues listed in a file prompt
+const maxKnownIssuesInPrompt = 50
+
+type FindingUsecase struct {
+	ProjectRepo       repository.ProjectRepository
+	ProjectFileRepo   repository.ProjectFileRepository
+	FindingRepo       repository.FindingRepository
+	FindingTriageRepo repository.FindingTriageRepository
+	SuppressionRepo   repository.SuppressionRepository
+}
+
+func NewFindingUsecase(projectRepo repository.ProjectRepository, projectFileRepo repository.ProjectFileRepository, findingRepo repository.FindingRepository, findingTriageRepo repository.FindingTriageRepository, suppressionRepo repository.SuppressionRepository) *FindingUsecase {
+	return &FindingUsecase{
+		ProjectRepo:       projectRepo,
+		ProjectFileRepo:   projectFileRepo,
+		FindingRepo:       findingRepo,
+		FindingTriageRepo: findingTriageRepo,
+		SuppressionRepo:   suppressionRepo,
+	}
+}
+
+// ImportLinterReport stores a linter report as external findings, replacing the findings
+// of an earlier report from the same tool. An empty tool is detected from the report.
+func (uc *FindingUsecase) ImportLinterReport(projectID uint, tool string, content []byte) (LinterImport, error) {
+	if _, err := uc.ProjectRepo.GetOneByID(projectID); err != nil {
+		return LinterImport{}, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	files, err := uc.ProjectFileRepo.GetFilesByProjectID(projectID)
+	if err != nil {
+		return LinterImport{}, fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	result, err := importLinterReport(uc.FindingRepo, projectID, files, tool, content)
+	if err != nil {
+		return LinterImport{}, err
+	}
+	// Rules that match by rule name, path or message apply to the imported findings at once
+	if err := uc.refreshSuppressions(projectID); err != nil {
+		return LinterImport{}, err
+	}
+	return result, nil
+}
+
+// FindingFilter selects which findings are returned
+type FindingFilter struct {
+	Source            string // Only findings of this source; empty means all
+	IncludeDuplicates bool   // Include findings merged into a canonical finding
+	IncludeHidden     bool   // Include findings the critic rejected
+	IncludeSuppressed bool   // Include findings matched by a suppression rule or the baseline
+	Status            string // Only findings with this triage status; empty means all
+	Assignee          string // Only findings assigned to this user; empty means all
+}
+
+// GetFindings returns the project's findings. By default only canonical findings the
+// critic did not reject and nothing suppresses are returned.
+func (uc *FindingUsecase) GetFindings(projectID uint, filter FindingFilter) ([]model.Finding, error) {
+	findings, err := uc.FindingRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve findings: %w", err)
+	}
+
+	filtered := findings[:0]
+	for _, finding := range findings {
+		if filter.Source != "" && finding.Source != filter.Source {
+			continue
+		}
+		if !filter.IncludeDuplicates && finding.CanonicalID != nil {
+			continue
+		}
+		if !filter.IncludeHidden && finding.Hidden {
+			continue
+		}
+		if !filter.IncludeSuppressed && finding.Suppressed {
+			continue
+		}
+		if filter.Status != "" && finding.TriageStatus() != filter.Status {
+			continue
+		}
+		if filter.Assignee != "" && finding.Assignee != filter.Assignee {
+			continue
+		}
+		filtered = append(filtered, finding)
+	}
+	return filtered, nil
+}
+
+// LinterImport is the outcome of importing a linter report
+type LinterImport struct {
+	Tool      string
+	Imported  int
+	Unmatched int // Entries whose path matches no project file
+}
+
+func importLinterReport(findingRepo repository.FindingRepository, projectID uint, files []model.ProjectFile, tool string, content []byte) (LinterImport, error) {
+	entries, err := ingest.ParseLinterReport(tool, content)
+	if err != nil {
+		return LinterImport{}, err
+	}
+
+	result := LinterImport{Tool: tool}
+	matcher := ingest.NewPathMatcher(files)
+	findings := make([]model.Finding, 0, len(entries))
+	for _, entry := range entries {
+		result.Tool = entry.Tool
+		file, ok := matcher.Match(entry.Path)
+		if !ok {
+			result.Unmatched++
+			continue
+		}
+		fileID := file.ID
+		findings = append(findings, model.Finding{
+			ProjectID:     projectID,
+			ProjectFileID: &fileID,
+			Source:        model.FindingSourceExternal,
+			RuleName:      entry.RuleID,
+			Tool:          entry.Tool,
+			Severity:      entry.Severity,
+			Message:       entry.Message,
+			Path:          file.Path,
+			Line:          entry.Line,
+			Column:        entry.Column,
+		})
+	}
+
+	// A report without entries still replaces the previous one of its tool
+	if result.Tool != "" {
+		if err := findingRepo.DeleteByProjectIDAndTool(projectID, model.FindingSourceExternal, result.Tool); err != nil {
+			return LinterImport{}, fmt.Errorf("failed to delete previous findings: %w", err)
+		}
+	}
+	if err := findingRepo.CreateMany(findings); err != nil {
+		return LinterImport{}, fmt.Errorf("failed to save findings: %w", err)
+	}
+	result.Imported = len(findings)
+	return result, nil
+}
+
+// formatKnownIssues lists the external findings of a file for the file prompts
+func formatKnownIssues(findings []model.Finding) string {
+	var sb strings.Builder
+	count := 0
+	for _, finding := range findings {
+		if finding.Source != model.FindingSourceExternal {
+			continue
+		}
+		if count == maxKnownIssuesInPrompt {
+			sb.WriteString("...\n")
+			break
+		}
+		count++
+		sb.WriteString(fmt.Sprintf("- line %d: %s: %s\n", finding.Line, finding.RuleName, finding.Message))
+	}
+	return sb.String()
+}
@@ -0,0 +1,117 @@
+// internal/usecase/gate.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/quality"
+)
+
+// ErrNoCompletedRuns reports that the project has not been analyzed yet, so there is no
+// run to check
+var ErrNoCompletedRuns = errors.New("the project has no completed runs")
+
+// ErrUnknownGatePolicy reports a policy that is neither built in nor defined by the
+// project's configuration file
+var ErrUnknownGatePolicy = errors.New("unknown gate policy")
+
+// GateResult is the outcome of a quality gate on the latest run of a project
+type GateResult struct {
+	Policies    []string
+	Run         model.AnalysisRun
+	PreviousRun *model.AnalysisRun // Nil when every finding of the run counts as new
+	Passed      bool
+	Violations  []quality.Violation
+}
+
+// GatePolicies returns the gate policies the project can use: the built-in ones and the
+// ones of its configuration file
+func (uc *ProjectAnalysisUsecase) GatePolicies(projectID uint) (map[string]quality.Policy, error) {
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	policies := quality.BuiltinPolicies()
+	for name, policy := range projectConfig(project).Gates {
+		policies[name] = policy
+	}
+	return policies, nil
+}
+
+// EvaluateGate checks the latest completed run of the project against the named policies.
+// Findings are new when they are not in the latest completed run of the previous version,
+// so that analyzing a version again does not turn its regressions into persisting findings;
+// without such a run every finding is new. Suppressed findings are not in the run snapshots
+// and never fail a gate.
+func (uc *ProjectAnalysisUsecase) EvaluateGate(projectID uint, names []string) (*GateResult, error) {
+	policies, err := uc.GatePolicies(projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if _, ok := policies[name]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownGatePolicy, name)
+		}
+	}
+
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	versionIDs := []uint{project.ID}
+	if project.PreviousVersionID != nil {
+		versionIDs = append(versionIDs, *project.PreviousVersionID)
+	}
+	runs, err := uc.AnalysisRunRepo.GetManyByProjectIDs(versionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve analysis runs: %w", err)
+	}
+	result := &GateResult{Policies: names}
+	found := false
+	for _, run := range runs {
+		if run.Status != model.RunStatusCompleted {
+			continue
+		}
+		if run.ProjectID == projectID {
+			if !found {
+				result.Run = run
+				found = true
+			}
+			continue
+		}
+		if result.PreviousRun == nil {
+			previous := run
+			result.PreviousRun = &previous
+		}
+	}
+	if !found {
+		return nil, ErrNoCompletedRuns
+	}
+
+	input := quality.GateInput{Score: result.Run.Score}
+	input.Findings, err = uc.AnalysisRunRepo.GetSnapshotFindings(result.Run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve findings of run %d: %w", result.Run.ID, err)
+	}
+	input.Compliance, err = uc.AnalysisRunRepo.GetSnapshotCompliance(result.Run.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve verdicts of run %d: %w", result.Run.ID, err)
+	}
+	input.New = input.Findings
+	if result.PreviousRun != nil {
+		previousFindings, err := uc.AnalysisRunRepo.GetSnapshotFindings(result.PreviousRun.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve findings of run %d: %w", result.PreviousRun.ID, err)
+		}
+		input.New, _, _ = matchFindings(previousFindings, input.Findings)
+	}
+
+	for _, name := range names {
+		result.Violations = append(result.Violations, policies[name].Evaluate(name, input)...)
+	}
+	result.Passed = len(result.Violations) == 0
+	return result, nil
+}
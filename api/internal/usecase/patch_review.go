@@ -0,0 +1,333 @@
+// internal/usecase/patch_review.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"evraz_api/internal/dto"
+	"evraz_api/internal/dto/llm_responses"
+	"evraz_api/internal/ingest"
+	"evraz_api/internal/model"
+	"evraz_api/internal/patch"
+	"evraz_api/internal/projectconfig"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+// patchContextLines is the number of unchanged lines shown around the added lines of a patch
+const patchContextLines = 10
+
+// changedFile is a file of a patch with its content after the change
+type changedFile struct {
+	Diff    patch.FileDiff
+	Content string
+	Added   []int // Added or modified lines of Content
+}
+
+// ReviewPatch reviews the changes of a merge request to the project: a unified diff is
+// applied to the stored files, base and head archives are compared with each other. The
+// file-level checks only see the changed hunks with their context, and only issues on
+// added or modified lines are kept.
+func (uc *ProjectAnalysisUsecase) ReviewPatch(projectID uint, req dto.ReviewPatchRequest) (*model.PatchReview, error) {
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if req.Language != "" && !uc.Catalog.IsSupported(req.Language) {
+		return nil, fmt.Errorf("unsupported language: %s", req.Language)
+	}
+	language := uc.Catalog.Resolve(req.Language, project.Language)
+
+	var source string
+	var changed []changedFile
+	switch {
+	case req.Base != nil && req.Head != nil:
+		source = model.PatchSourceArchives
+		changed, err = uc.compareArchives(project, req.Base, req.Head)
+	case req.Patch != nil || req.Diff != "":
+		source = model.PatchSourceDiff
+		if req.Patch != nil {
+			content, readErr := readUploadedReport(req.Patch)
+			if readErr != nil {
+				return nil, fmt.Errorf("failed to read patch: %w", readErr)
+			}
+			req.Diff = string(content)
+		}
+		changed, err = uc.applyDiff(project, req.Diff)
+	default:
+		return nil, errors.New("a diff or base and head archives are required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diffs := make([]patch.FileDiff, len(changed))
+	for i, file := range changed {
+		diffs[i] = file.Diff
+	}
+	review := &model.PatchReview{
+		ProjectID: project.ID,
+		Source:    source,
+		Status:    model.RunStatusRunning,
+		Language:  language,
+		Diff:      patch.Format(diffs),
+	}
+	if err := uc.PatchReviewRepo.CreateOne(review); err != nil {
+		return nil, fmt.Errorf("failed to create patch review: %w", err)
+	}
+
+	reviewErr := uc.reviewChanges(review, project, changed)
+	now := time.Now()
+	review.FinishedAt = &now
+	review.Status = model.RunStatusCompleted
+	if reviewErr != nil {
+		review.Status = model.RunStatusFailed
+		review.Error = reviewErr.Error()
+	}
+	if err := uc.PatchReviewRepo.UpdateOne(review); err != nil {
+		return nil, fmt.Errorf("failed to update patch review: %w", err)
+	}
+	if reviewErr != nil {
+		return nil, reviewErr
+	}
+
+	return uc.PatchReviewRepo.GetOneByID(review.ID)
+}
+
+// GetPatchReviews returns the project's patch reviews, newest first
+func (uc *ProjectAnalysisUsecase) GetPatchReviews(projectID uint) ([]model.PatchReview, error) {
+	reviews, err := uc.PatchReviewRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve patch reviews: %w", err)
+	}
+	return reviews, nil
+}
+
+// applyDiff applies a unified diff to the stored files of the project
+func (uc *ProjectAnalysisUsecase) applyDiff(project *model.Project, text string) ([]changedFile, error) {
+	diffs, err := patch.Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid diff: %w", err)
+	}
+	files, err := uc.ProjectFileRepo.GetFilesByProjectID(project.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	matcher := ingest.NewPathMatcher(files)
+
+	changed := make([]changedFile, 0, len(diffs))
+	for _, diff := range diffs {
+		base := ""
+		if !diff.IsNew() {
+			file, ok := matcher.Match(diff.OldPath)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a file of the project", diff.OldPath)
+			}
+			base = file.Content
+		}
+		content, added, err := patch.Apply(base, diff)
+		if err != nil {
+			return nil, err
+		}
+		changed = append(changed, changedFile{Diff: diff, Content: content, Added: added})
+	}
+	return changed, nil
+}
+
+// compareArchives extracts the base and head archives next to the project and diffs
+// their files; the extracted files are removed afterwards
+func (uc *ProjectAnalysisUsecase) compareArchives(project *model.Project, base, head *multipart.FileHeader) ([]changedFile, error) {
+	dirName := fmt.Sprintf("%s_patch_%d", project.Name, time.Now().UnixNano())
+	dir := uc.FileManager.FormulatePath("1", dirName, false)
+	defer func() {
+		if err := uc.FileManager.RemovePath(dir); err != nil {
+			log.Printf("failed to remove %s: %v", dir, err)
+		}
+	}()
+
+	readFiles := func(name string, header *multipart.FileHeader) (map[string]string, error) {
+		if err := uc.FileManager.CreateProject("1", filepath.Join(dirName, name)); err != nil {
+			return nil, fmt.Errorf("failed to create directory for the %s archive: %w", name, err)
+		}
+		root, err := extractArchive(uc.FileManager, header, filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("%s archive: %w", name, err)
+		}
+		files := make(map[string]string)
+		err = uc.FileManager.ProcessFilesInDirectory(root, func(relPath string, content []byte) error {
+			files[relPath] = string(content)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s archive: %w", name, err)
+		}
+		return files, nil
+	}
+	baseFiles, err := readFiles("base", base)
+	if err != nil {
+		return nil, err
+	}
+	headFiles, err := readFiles("head", head)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(baseFiles)+len(headFiles))
+	for path := range headFiles {
+		paths = append(paths, path)
+	}
+	for path := range baseFiles {
+		if _, ok := headFiles[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	var changed []changedFile
+	for _, path := range paths {
+		oldContent, inBase := baseFiles[path]
+		newContent, inHead := headFiles[path]
+		if inBase && inHead && oldContent == newContent {
+			continue
+		}
+		oldPath, newPath := path, path
+		if !inBase {
+			oldPath = ""
+		}
+		if !inHead {
+			newPath = ""
+		}
+		diff := patch.Compute(oldPath, newPath, oldContent, newContent)
+		changed = append(changed, changedFile{Diff: diff, Content: newContent, Added: diff.AddedLines()})
+	}
+	if len(changed) == 0 {
+		return nil, errors.New("the base and head archives have the same files")
+	}
+	return changed, nil
+}
+
+// reviewChanges runs the file-level checks on the changed hunks of every file and stores
+// the files of the patch and the issues found on added lines
+func (uc *ProjectAnalysisUsecase) reviewChanges(review *model.PatchReview, project *model.Project, changed []changedFile) error {
+	llmLanguage := uc.Catalog.LLMLanguage(review.Language)
+	config := projectConfig(project)
+
+	var targetExtension string
+	if project.ProgrammingLanguageID == 1 {
+		targetExtension = ".py"
+	}
+
+	var files []model.PatchReviewFile
+	var patchFindings []model.PatchFinding
+	for _, file := range changed {
+		path := file.Diff.Path()
+		reviewFile := model.PatchReviewFile{
+			PatchReviewID: review.ID,
+			Path:          path,
+			Change:        model.FileChangeModified,
+			AddedLines:    len(file.Added),
+		}
+		switch {
+		case file.Diff.IsNew():
+			reviewFile.Change = model.FileChangeAdded
+		case file.Diff.IsDeleted():
+			reviewFile.Change = model.FileChangeDeleted
+		case file.Diff.OldPath != file.Diff.NewPath:
+			reviewFile.OldPath = file.Diff.OldPath
+		}
+		reviewFile.Reviewed = !file.Diff.IsDeleted() && len(file.Added) > 0 && strings.HasSuffix(path, targetExtension) &&
+			!config.Excluded(path)
+		files = append(files, reviewFile)
+		if !reviewFile.Reviewed {
+			continue
+		}
+
+		found, err := uc.reviewChangedFile(review, project, config, file, llmLanguage)
+		if err != nil {
+			return err
+		}
+		patchFindings = append(patchFindings, found...)
+	}
+
+	if err := uc.PatchReviewRepo.CreateFiles(files); err != nil {
+		return fmt.Errorf("failed to save patch files: %w", err)
+	}
+	if err := uc.PatchReviewRepo.CreateFindings(patchFindings); err != nil {
+		return fmt.Errorf("failed to save patch findings: %w", err)
+	}
+	return nil
+}
+
+// reviewChangedFile runs the checks that apply to the file on its changed hunks. Findings
+// keep the severity the LLM gives them, medium when it gives none it knows, unless the
+// project's configuration sets one for the check.
+func (uc *ProjectAnalysisUsecase) reviewChangedFile(review *model.PatchReview, project *model.Project, config *projectconfig.Config, file changedFile, llmLanguage string) ([]model.PatchFinding, error) {
+	path := file.Diff.Path()
+	checks, err := uc.fileChecks(project, path, file.Content, llmLanguage, "patch_review", review.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	added := make(map[int]bool, len(file.Added))
+	for _, line := range file.Added {
+		added[line] = true
+	}
+	data := helper_prompts.PatchReviewData{
+		FilePath: path,
+		Changes:  patch.Excerpt(file.Content, file.Added, patchContextLines),
+	}
+
+	var found []model.PatchFinding
+	for promptName, check := range checks {
+		prompt, err := uc.PromptConstructor.GetPrompt(helper_prompts.PatchReviewPrompt(check), data, llmLanguage, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to construct prompt: %w", err)
+		}
+
+		reply, gptCallID, err := uc.MistralService.CallMistral(prompt, false, service.Hack, "patch_review", review.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call Mistral service: %w", err)
+		}
+
+		var response llm_responses.PatchReviewResponse
+		if err := utils.ExtractJSON(reply, &response); err != nil {
+			log.Printf("Error while extracting Patch Review Response for %s of %s", promptName, path)
+			continue
+		}
+
+		callID := gptCallID
+		for _, issue := range response.Issues {
+			message := strings.TrimSpace(issue.Message)
+			// Issues about unchanged code belong to a full review, not to this merge request
+			if message == "" || !added[issue.Line] {
+				continue
+			}
+			severity, ok := model.NormalizeSeverity(issue.Severity)
+			if !ok {
+				severity = model.SeverityMedium
+			}
+			if configured, ok := config.SeverityFor(promptName); ok {
+				severity = configured
+			}
+			found = append(found, model.PatchFinding{
+				PatchReviewID: review.ID,
+				Path:          path,
+				Line:          issue.Line,
+				RuleName:      promptName,
+				Severity:      severity,
+				Message:       message,
+				GPTCallID:     &callID,
+			})
+		}
+	}
+	return found, nil
+}
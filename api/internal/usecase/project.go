@@ -3,13 +3,17 @@
 package usecase
 
 import (
+	"encoding/json"
 	"errors"
 	"evraz_api/internal/dto"
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/ingest"
 	"evraz_api/internal/model"
 	"evraz_api/internal/repository"
 	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
 	"fmt"
-	"os"
+	"log"
 	"path/filepath"
 )
 
@@ -17,15 +21,25 @@ type ProjectUsecase struct {
 	ProjectRepo               repository.ProjectRepository
 	ProjectFileRepo           repository.ProjectFileRepository
 	ProjectAnalysisResultRepo repository.ProjectAnalysisRepository
+	FileCoverageRepo          repository.FileCoverageRepository
+	FindingRepo               repository.FindingRepository
+	FileAnalysisRepo          repository.FileAnalysisRepository
+	CodeChunkRepo             repository.CodeChunkRepository
 	FileManager               service.FileManager
+	Catalog                   *i18n.Catalog
 }
 
-func NewProjectUsecase(projectRepo repository.ProjectRepository, projectFileRepo repository.ProjectFileRepository, projectAnalysisResultRepo repository.ProjectAnalysisRepository, fileManager service.FileManager) *ProjectUsecase {
+func NewProjectUsecase(projectRepo repository.ProjectRepository, projectFileRepo repository.ProjectFileRepository, projectAnalysisResultRepo repository.ProjectAnalysisRepository, fileCoverageRepo repository.FileCoverageRepository, findingRepo repository.FindingRepository, fileAnalysisRepo repository.FileAnalysisRepository, codeChunkRepo repository.CodeChunkRepository, fileManager service.FileManager, catalog *i18n.Catalog) *ProjectUsecase {
 	return &ProjectUsecase{
 		ProjectRepo:               projectRepo,
 		ProjectFileRepo:           projectFileRepo,
 		ProjectAnalysisResultRepo: projectAnalysisResultRepo,
+		FileCoverageRepo:          fileCoverageRepo,
+		FindingRepo:               findingRepo,
+		FileAnalysisRepo:          fileAnalysisRepo,
+		CodeChunkRepo:             codeChunkRepo,
 		FileManager:               fileManager,
+		Catalog:                   catalog,
 	}
 }
 
@@ -34,63 +48,33 @@ func (uc *ProjectUsecase) UploadProject(req dto.UploadProjectRequest) (dto.Proje
 	if req.UserID == "" {
 		return dto.ProjectDTO{}, errors.New("User ID is required")
 	}
-
-	// Create the project directory
-	if err := uc.FileManager.CreateProject(req.UserID, req.Name); err != nil {
-		return dto.ProjectDTO{}, errors.New("Failed to create project")
-	}
-
-	// Define the path where the file will be saved
-	tempFilePath := filepath.Join(uc.FileManager.FormulatePath(req.UserID, req.Name, false), req.File.Filename)
-
-	// Save the uploaded file
-	if err := uc.FileManager.SaveUploadedFile(req.File, tempFilePath); err != nil {
-		return dto.ProjectDTO{}, errors.New("Failed to save file")
+	if req.Language != "" && !uc.Catalog.IsSupported(req.Language) {
+		return dto.ProjectDTO{}, fmt.Errorf("unsupported language: %s", req.Language)
 	}
 
-	// Determine file type and extract accordingly
-	extension := filepath.Ext(req.File.Filename)
-	extractPath := uc.FileManager.FormulatePath(req.UserID, req.Name, false)
+	// A new version of an existing project is stored next to the previous versions
+	var previous *model.Project
 	var err error
-	switch extension {
-	case ".zip":
-		err = uc.FileManager.ExtractZip(tempFilePath, extractPath)
-	case ".tar.gz", ".tgz":
-		err = uc.FileManager.ExtractTarGz(tempFilePath, extractPath)
-	case ".7z":
-		err = uc.FileManager.Extract7z(tempFilePath, extractPath)
-	default:
-		return dto.ProjectDTO{}, errors.New("unsupported file type")
-	}
-	if err != nil {
-		return dto.ProjectDTO{}, fmt.Errorf("failed to extract archive: %w", err)
+	dirName := req.Name
+	if req.ProjectID != 0 {
+		if previous, err = uc.latestVersion(req.ProjectID); err != nil {
+			return dto.ProjectDTO{}, err
+		}
+		req.Name = previous.Name
+		dirName = fmt.Sprintf("%s_v%d", previous.Name, previous.Version+1)
+		if req.Language == "" {
+			req.Language = previous.Language
+		}
 	}
 
-	// Delete the temporary archive file
-	if err := uc.FileManager.RemovePath(tempFilePath); err != nil {
-		return dto.ProjectDTO{}, errors.New("Failed to remove temp file")
+	// Create the project directory
+	if err := uc.FileManager.CreateProject(req.UserID, dirName); err != nil {
+		return dto.ProjectDTO{}, errors.New("Failed to create project")
 	}
 
-	// Adjust the extractedPath if necessary
-	extractedPath := extractPath
-
-	entries, err := os.ReadDir(extractedPath)
+	extractedPath, err := extractArchive(uc.FileManager, req.File, uc.FileManager.FormulatePath(req.UserID, dirName, false))
 	if err != nil {
-		return dto.ProjectDTO{}, fmt.Errorf("failed to read extracted directory: %w", err)
-	}
-
-	// Filter out __MACOSX entries
-	filteredEntries := []os.DirEntry{}
-	for _, entry := range entries {
-		if entry.Name() != "__MACOSX" {
-			filteredEntries = append(filteredEntries, entry)
-		}
-	}
-
-	if len(filteredEntries) == 1 && filteredEntries[0].IsDir() {
-		// Only one directory (excluding __MACOSX), adjust the extractedPath
-		extractedPath = filepath.Join(extractedPath, filteredEntries[0].Name())
-		fmt.Printf("Only one subdirectory detected, adjusting extractedPath to %s\n", extractedPath)
+		return dto.ProjectDTO{}, err
 	}
 
 	// Execute tree command to get the directory structure
@@ -106,41 +90,130 @@ func (uc *ProjectUsecase) UploadProject(req dto.UploadProjectRequest) (dto.Proje
 		Path:                  extractedPath,
 		Tree:                  treeOutput,
 		WasAnalyzed:           false,
+		Language:              uc.Catalog.Resolve(req.Language),
+		Version:               1,
+	}
+	if previous != nil {
+		rootID := previous.ID
+		if previous.RootProjectID != nil {
+			rootID = *previous.RootProjectID
+		}
+		project.RootProjectID = &rootID
+		project.PreviousVersionID = &previous.ID
+		project.Version = previous.Version + 1
 	}
 	if err := uc.ProjectRepo.CreateOne(&project); err != nil {
 		return dto.ProjectDTO{}, errors.New("Failed to create project")
 	}
 
-	// Process files in directory
+	// Process files in directory, collecting coverage and linter reports on the way
+	var projectFiles []model.ProjectFile
+	var coverageReports, linterReports []uploadedReport
 	err = uc.FileManager.ProcessFilesInDirectory(extractedPath, func(relPath string, content []byte) error {
 		fileName := filepath.Base(relPath)
+		if ingest.IsCoverageReport(fileName) {
+			coverageReports = append(coverageReports, uploadedReport{Path: relPath, Content: content})
+		}
+		if ingest.LinterReportTool(fileName) != "" {
+			linterReports = append(linterReports, uploadedReport{Path: relPath, Content: content})
+		}
 		projectFile := model.ProjectFile{
 			ProjectID:   project.ID,
 			Path:        relPath,
 			Content:     string(content),
 			WasAnalyzed: false,
 			Name:        fileName,
+			ContentHash: utils.ContentHash(string(content)),
+		}
+		if err := uc.ProjectFileRepo.CreateOne(&projectFile); err != nil {
+			return err
 		}
-		return uc.ProjectFileRepo.CreateOne(&projectFile)
+		projectFiles = append(projectFiles, projectFile)
+		return nil
 	})
 	if err != nil {
 		return dto.ProjectDTO{}, errors.New("Failed to process project files")
 	}
 
-	return dto.ProjectDTO{
+	// The configuration file in the project root adjusts how the project is analyzed
+	if err := uc.applyConfigFile(&project, projectFiles, req.Language != ""); err != nil {
+		log.Printf("Failed to apply configuration of project %d: %v", project.ID, err)
+	}
+
+	// Results of files that did not change since the previous version are kept
+	if previous != nil {
+		if err := uc.carryOver(previous, &project, projectFiles); err != nil {
+			log.Printf("Failed to carry over results of project %d: %v", previous.ID, err)
+		}
+	}
+
+	// Index the code for questions about the project
+	if _, err := indexCodeChunks(uc.CodeChunkRepo, project.ID, projectFiles); err != nil {
+		log.Printf("Failed to index code of project %d: %v", project.ID, err)
+	}
+
+	// A report passed as a separate field takes precedence over the ones in the archive
+	if req.Coverage != nil {
+		content, err := readUploadedReport(req.Coverage)
+		if err != nil {
+			return dto.ProjectDTO{}, fmt.Errorf("failed to read coverage report: %w", err)
+		}
+		coverageReports = append(coverageReports, uploadedReport{Path: req.Coverage.Filename, Content: content})
+	}
+	if len(coverageReports) > 0 {
+		if err := uc.importCoverage(project.ID, projectFiles, coverageReports); err != nil {
+			log.Printf("Failed to import coverage for project %d: %v", project.ID, err)
+		}
+	}
+	for _, report := range linterReports {
+		imported, err := importLinterReport(uc.FindingRepo, project.ID, projectFiles, ingest.LinterReportTool(report.Path), report.Content)
+		if err != nil {
+			log.Printf("Skipping linter report %s: %v", report.Path, err)
+			continue
+		}
+		fmt.Printf("Linter report %s (%s): %d findings imported, %d unmatched\n", report.Path, imported.Tool, imported.Imported, imported.Unmatched)
+	}
+
+	projectDTO := dto.ProjectDTO{
 		ID:                    project.ID,
 		ProgrammingLanguageID: project.ProgrammingLanguageID,
 		Name:                  project.Name,
 		Path:                  project.Path,
 		Tree:                  project.Tree,
 		WasAnalyzed:           project.WasAnalyzed,
-	}, nil
+		Language:              project.Language,
+		Version:               project.Version,
+		RootProjectID:         project.RootProjectID,
+		ConfigPath:            project.ConfigPath,
+	}
+	if project.Config != "" {
+		projectDTO.Config = json.RawMessage(project.Config)
+	}
+	return projectDTO, nil
 }
 
 func (uc *ProjectUsecase) GetAllProjects() ([]model.Project, error) {
 	return uc.ProjectRepo.GetAllProjects()
 }
 
+// UpdateProjectSettings changes the project-level settings, currently the report language
+func (uc *ProjectUsecase) UpdateProjectSettings(projectID uint, req dto.UpdateProjectSettingsRequest) (*model.Project, error) {
+	if !uc.Catalog.IsSupported(req.Language) {
+		return nil, fmt.Errorf("unsupported language: %s", req.Language)
+	}
+
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+
+	project.Language = uc.Catalog.Resolve(req.Language)
+	if err := uc.ProjectRepo.UpdateOneByID(project); err != nil {
+		return nil, fmt.Errorf("failed to update project settings: %w", err)
+	}
+	return project, nil
+}
+
 func (uc *ProjectUsecase) GetProjectOverview(projectID uint) (model.Project, []model.ProjectFile, []model.ProjectAnalysisResult, error) {
 	project, err := uc.ProjectRepo.GetProjectByID(projectID)
 	if err != nil {
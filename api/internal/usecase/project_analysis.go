@@ -8,12 +8,15 @@ import (
 	"strings"
 	"sync"
 
+	"evraz_api/internal/agent"
 	"evraz_api/internal/dto/llm_responses"
+	"evraz_api/internal/findings"
+	"evraz_api/internal/i18n"
 	"evraz_api/internal/model"
+	"evraz_api/internal/projectconfig"
+	"evraz_api/internal/projectinputs"
 	"evraz_api/internal/prompts"
 	"evraz_api/internal/prompts/prompts_storage/file_prompts"
-	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
-	"evraz_api/internal/prompts/prompts_storage/project_prompts"
 	"evraz_api/internal/prompts/types"
 	"evraz_api/internal/repository"
 	"evraz_api/internal/service"
@@ -25,9 +28,30 @@ type ProjectAnalysisUsecase struct {
 	ProjectFileRepo     repository.ProjectFileRepository
 	ProjectAnalysisRepo repository.ProjectAnalysisRepository
 	FileAnalysisRepo    repository.FileAnalysisRepository
+	FindingRepo         repository.FindingRepository
+	FindingTriageRepo   repository.FindingTriageRepository
+	SuppressionRepo     repository.SuppressionRepository
+	FileCoverageRepo    repository.FileCoverageRepository
+	AnalysisRunRepo     repository.AnalysisRunRepository
+	AgentToolCallRepo   repository.AgentToolCallRepository
+	PackageSummaryRepo  repository.PackageSummaryRepository
+	PatchReviewRepo     repository.PatchReviewRepository
 	MistralService      service.MistralService
+	FileManager         service.FileManager
 	Prompts             *prompts.Prompts
 	PromptConstructor   *prompts.PromptConstructor
+	Catalog             *i18n.Catalog
+	Options             AnalysisOptions
+}
+
+// AnalysisOptions toggles the optional analysis stages
+type AnalysisOptions struct {
+	UseEmbeddings       bool    // Compare findings by embeddings as well when deduplicating
+	CriticEnabled       bool    // Verify review findings with the critic prompt
+	CriticMinConfidence float64 // Findings the critic is less confident in are hidden
+	AgentEnabled        bool    // Run project-level checks as an agent with file tools
+	AgentMaxSteps       int     // Model turns an agent check may take
+	AgentMaxTokens      int     // Tokens an agent check may spend
 }
 
 func NewProjectAnalysisUsecase(
@@ -35,252 +59,192 @@ func NewProjectAnalysisUsecase(
 	projectFileRepo repository.ProjectFileRepository,
 	projectAnalysisRepo repository.ProjectAnalysisRepository,
 	fileAnalysisRepo repository.FileAnalysisRepository,
+	findingRepo repository.FindingRepository,
+	findingTriageRepo repository.FindingTriageRepository,
+	suppressionRepo repository.SuppressionRepository,
+	fileCoverageRepo repository.FileCoverageRepository,
+	analysisRunRepo repository.AnalysisRunRepository,
+	agentToolCallRepo repository.AgentToolCallRepository,
+	packageSummaryRepo repository.PackageSummaryRepository,
+	patchReviewRepo repository.PatchReviewRepository,
 	mistralService service.MistralService,
+	fileManager service.FileManager,
+	catalog *i18n.Catalog,
+	options AnalysisOptions,
 ) *ProjectAnalysisUsecase {
 	return &ProjectAnalysisUsecase{
 		ProjectRepo:         projectRepo,
 		ProjectFileRepo:     projectFileRepo,
 		ProjectAnalysisRepo: projectAnalysisRepo,
 		FileAnalysisRepo:    fileAnalysisRepo,
+		FindingRepo:         findingRepo,
+		FindingTriageRepo:   findingTriageRepo,
+		SuppressionRepo:     suppressionRepo,
+		FileCoverageRepo:    fileCoverageRepo,
+		AnalysisRunRepo:     analysisRunRepo,
+		AgentToolCallRepo:   agentToolCallRepo,
+		PackageSummaryRepo:  packageSummaryRepo,
+		PatchReviewRepo:     patchReviewRepo,
 		MistralService:      mistralService,
+		FileManager:         fileManager,
 		Prompts:             prompts.NewPrompts(),
 		PromptConstructor:   prompts.NewPromptConstructor(),
+		Catalog:             catalog,
+		Options:             options,
 	}
 }
 
-func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
+// AnalyzeProject runs the project- and file-level checks. The report language is taken
+// from the request when set, otherwise from the project settings.
+func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint, language string) (err error) {
 
 	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if language != "" && !uc.Catalog.IsSupported(language) {
+		return fmt.Errorf("unsupported language: %s", language)
+	}
 	project.WasAnalyzed = true
 	if err := uc.ProjectRepo.UpdateOneByID(project); err != nil {
 		return fmt.Errorf("failed to update project GPTCallID: %w", err)
 	}
+
+	language = uc.Catalog.Resolve(language, project.Language)
+	llmLanguage := uc.Catalog.LLMLanguage(language)
+	config := projectConfig(project)
+
+	run, err := uc.startRun(project.ID, nil, model.AnalysisScopeProject, language)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve project: %w", err)
+		return err
 	}
+	defer func() { uc.finishRun(run, err) }()
 
-	// Map prompt names to actual prompts
-	promptMap := map[string]types.Prompt{
-		"ProjectStructure":        uc.Prompts.ProjectStructure,
-		"KeyFiles":                uc.Prompts.KeyFiles,
-		"ApplicationArchitecture": uc.Prompts.ApplicationArchitecture,
-		"DependencyManagement":    uc.Prompts.DependencyManagement,
-		"ProjectSettings":         uc.Prompts.ProjectSettings,
-		"TestingStrategy":         uc.Prompts.TestingStrategy,
-		"AdditionalTechnical":     uc.Prompts.AdditionalTechnical,
-		"DateTimeHandling":        uc.Prompts.DateTimeHandling,
+	// Load the files once; checks pick the relevant ones by content, and summaries replace
+	// the tree and code that do not fit in a prompt
+	projectFiles, err := uc.ProjectFileRepo.GetFilesByProjectID(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	uploadedFiles := projectFiles
+	projectFiles = make([]model.ProjectFile, 0, len(uploadedFiles))
+	for _, file := range uploadedFiles {
+		if !config.Excluded(file.Path) {
+			projectFiles = append(projectFiles, file)
+		}
+	}
+	summaries := uc.newSummarizer(project, projectFiles, llmLanguage)
+	projectTree := summaries.Tree()
+	coverage, err := uc.FileCoverageRepo.GetManyByProjectID(project.ID)
+	if err != nil {
+		log.Printf("failed to retrieve coverage for project %d: %v", project.ID, err)
+	}
+	inputs := &projectinputs.Builder{
+		Files:     projectFiles,
+		Uploaded:  uploadedFiles,
+		Coverage:  coverage,
+		Summaries: summaries,
+		Catalog:   uc.Catalog,
+		Language:  language,
 	}
 
-	// Iterate over all prompts in promptMap
-	for promptName, promptTemplate := range promptMap {
-		var data types.PromptData
-		var outputDBValue string
-		var emptyValue bool
-
-		switch promptName {
-		case "ProjectStructure":
-			data = project_prompts.ProjectStructureData{
-				ProjectTree: project.Tree,
-			}
-		case "KeyFiles":
-			emptyValue = false
-			setupFilesContent := make(map[string]string)
-			setupFiles := []string{"setup.py", "setup.cfg", "pyproject.toml", "README.md"}
-
-			foundAny := false
-			for _, filename := range setupFiles {
-				content, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, filename)
-				if err == nil {
-					foundAny = true
-					setupFilesContent[filename] = content
-				} else {
-					content, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, filename)
-					if err == nil {
-						foundAny = true
-						setupFilesContent[filename] = content
-					} else {
-						setupFilesContent[filename] = filename + " is missing at the root of the project directory"
-					}
-				}
-			}
-			if foundAny == false {
-				outputDBValue = "setup.py, setup.cfg, pyproject.toml и README.md отсутствуют в корне проекта"
-				emptyValue = true
-			}
-			data = project_prompts.KeyFilesData{
-				SetupFilesContent: setupFilesContent,
-			}
-		case "ApplicationArchitecture":
-			data = project_prompts.ApplicationArchitectureData{
-				ProjectStructure: project.Tree,
-			}
-		case "DependencyManagement":
-			emptyValue = false
-			dependencyFile := "requirements.txt"
-			dependenciesContent, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, dependencyFile)
-			if err != nil {
-				dependenciesContent, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, dependencyFile)
-				if err != nil {
-					dependenciesContent = dependencyFile + " is missing at the root of the project directory"
-					outputDBValue = dependenciesContent
-					emptyValue = true
-				}
-			}
-			data = project_prompts.DependencyManagementData{
-				DependenciesContent: dependenciesContent,
-			}
-		case "ProjectSettings":
-			emptyValue = false
-			settingsFiles := []string{"settings.py", "config.yaml", "pyproject.toml"}
-			settingsContent := make(map[string]string)
-
-			foundAny := false
-			for _, filename := range settingsFiles {
-				content, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, filename)
-				if err == nil {
-					foundAny = true
-					settingsContent[filename] = content
-				} else {
-					content, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, filename)
-					if err == nil {
-						foundAny = true
-						settingsContent[filename] = content
-					} else {
-						settingsContent[filename] = filename + " is missing at the root of the project directory"
-					}
-				}
-			}
-			if foundAny == false {
-				outputDBValue = "settings.py, config.yaml и pyproject.toml отсутствуют в корне проекта"
-				emptyValue = true
-			}
-			data = project_prompts.ProjectSettingsData{
-				SettingsFilesContent: settingsContent,
-			}
-		case "TestingStrategy":
-			emptyValue = false
-			// Construct the prompt to identify tests
-			data = helper_prompts.ExtractTestsData{
-				ProjectTree: project.Tree,
-			}
-			prompt, err := uc.PromptConstructor.GetPrompt(helper_prompts.ExtractTestsPrompt, data, "Russian (русский)", true)
-			if err != nil {
-				return fmt.Errorf("failed to construct prompt for %s: %w", promptName, err)
-			}
+	// Let the master agent decide which project-level checks apply
+	decisions := uc.selectProjectChecks(project, projectTree, llmLanguage)
+	actx := &analysisContext{RunID: run.ID, Index: findings.NewSymbolIndex(projectFiles)}
+	var agentInput string
+	if uc.Options.AgentEnabled {
+		actx.Toolbox = agent.NewToolbox(projectFiles)
+		agentInput = filesInput(projectFiles)
+	}
 
-			// Call the LLM
-			analysisResult, _, err := uc.MistralService.CallMistral(prompt, true, service.Hack, "testsExtract", project.ID)
-			if err != nil {
-				return fmt.Errorf("failed to call Mistral service for %s: %w", promptName, err)
-			}
+	// Iterate over all project-level prompts
+	for _, promptName := range prompts.ProjectPromptNames {
+		promptTemplate, _ := uc.Prompts.ByName(promptName)
 
-			// Parse the response
-			var testsExtractResponse struct {
-				TestFilesRoutes []string `json:"test_files_routes"`
+		// Record skipped checks with the master agent's justification
+		decision := decisions[promptName]
+		if !config.CheckEnabled(promptName) {
+			decision = checkDecision{
+				Name:          promptName,
+				Justification: uc.Catalog.T(language, "analysis.check_disabled", project.ConfigPath),
 			}
-			if err := utils.ExtractJSON(analysisResult, &testsExtractResponse); err != nil {
-				emptyValue = true
-				continue
+		}
+		if !decision.Applicable {
+			projectAnalysis := &model.ProjectAnalysisResult{
+				ProjectID:    project.ID,
+				PromptName:   promptName,
+				Language:     language,
+				Status:       model.AnalysisStatusNotApplicable,
+				StatusReason: decision.Justification,
 			}
-
-			// Initialize a variable to store concatenated paths and contents
-			var concattedString string
-
-			// Fetch file details and concatenate paths and contents
-			for _, route := range testsExtractResponse.TestFilesRoutes {
-				// Convert string ID to uint
-				testsFileContent, err := uc.ProjectFileRepo.GetFileContentByPath(projectID, route)
-				if err != nil {
-					emptyValue = true
-					continue
-				}
-
-				// Ensure both path and content are not empty before appending
-				if route != "" && testsFileContent != "" {
-					concattedString += fmt.Sprintf("Path: %s\nContent:\n%s\n\n", route, testsFileContent)
-				}
+			if err := uc.ProjectAnalysisRepo.CreateOne(projectAnalysis); err != nil {
+				return fmt.Errorf("failed to save project analysis for %s: %w", promptName, err)
 			}
-
-			// Pass concatenated string to the next prompt
-			data = project_prompts.TestingStrategyData{
-				ProjectTree:       project.Tree,
-				TestsFilesContent: concattedString,
+			// Drop findings carried over from a version where the check applied
+			if err := uc.FindingRepo.DeleteByProjectIDAndRule(project.ID, model.FindingSourceLLM, promptName); err != nil {
+				log.Printf("failed to delete findings of skipped check %s: %v", promptName, err)
 			}
+			continue
+		}
 
-		case "AdditionalTechnical":
-			emptyValue = false
-			filePath := "transaction_manager.py"
-			foundAny := false
-			transactionCode, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, filePath)
-			if err != nil {
-				transactionCode, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, filePath)
-				if err != nil {
-					transactionCode = filePath + " не найден"
-					outputDBValue = transactionCode
-					emptyValue = true
-				} else {
-					foundAny = true
-				}
-			} else {
-				foundAny = true
-			}
-			filePath = "async_features.py"
-			asyncCode, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, filePath)
-			if err != nil {
-				asyncCode, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, filePath)
-				if err != nil {
-					asyncCode = filePath + " не найден"
-				} else {
-					foundAny = true
-				}
-			} else {
-				foundAny = true
-			}
-			if foundAny == false {
-				outputDBValue = "transaction_manager.py и async_features.py отсутствуют в корне проекта"
-				emptyValue = true
-			}
-			data = project_prompts.AdditionalTechnicalData{
-				TransactionManagementCode: transactionCode,
-				AsynchronousCodeUsage:     asyncCode,
-			}
-		case "DateTimeHandling":
-			emptyValue = false
-			filePath := "datetime_utils.py"
-			dateTimeCode, err := uc.ProjectFileRepo.GetRootFileContentByName(project.ID, filePath)
-			if err != nil {
-				dateTimeCode, err = uc.ProjectFileRepo.GetFileContentByName(project.ID, filePath)
-				if err != nil {
-					dateTimeCode = filePath + " не найден"
-					outputDBValue = dateTimeCode
-					emptyValue = true
-				}
-			}
-			data = project_prompts.DateTimeHandlingData{
-				DateTimeCodeSamples: dateTimeCode,
-			}
-		default:
+		data, outputDBValue, err := inputs.Data(promptName)
+		if err != nil {
+			log.Printf("skipping %s: %v", promptName, err)
 			continue
 		}
+		emptyValue := outputDBValue != ""
+		if promptName == "TestingStrategy" {
+			_, gaps := inputs.Tests()
+			if err := uc.saveMirroringFindings(project.ID, gaps, language); err != nil {
+				log.Printf("failed to save test mirroring findings for project %d: %v", project.ID, err)
+			}
+		}
 
 		var analysisResult string
 		var gptCallID uint
+		var inputHash string
 		var analysisDTO llm_responses.FileAnalysisResponse
 		if emptyValue == false {
 
 			// Construct the prompt
-			prompt, err := uc.PromptConstructor.GetPrompt(promptTemplate, data, "Russian (русский)", true)
+			prompt, err := uc.PromptConstructor.GetPrompt(promptTemplate, data, llmLanguage, true)
 			if err != nil {
 				return fmt.Errorf("failed to construct prompt for %s: %w", promptName, err)
 			}
 
-			// Call the LLM
-			analysisResult, gptCallID, err = uc.MistralService.CallMistral(prompt, false, service.Hack, "project", project.ID)
+			// Prompts whose input did not change since the previous version keep its
+			// result; the input of agent checks includes the files they can read
+			inputHash = utils.ContentHash(prompt)
+			if actx.Toolbox != nil {
+				inputHash = utils.ContentHash(prompt + "\n" + agentInput)
+			}
+			if reused, err := uc.reuseProjectResult(project, promptName, inputHash); err != nil {
+				log.Printf("failed to look up previous result of %s: %v", promptName, err)
+			} else if reused {
+				continue
+			}
+
+			// Call the LLM, letting it fetch more files when the agent is enabled
+			if actx.Toolbox != nil {
+				analysisResult, gptCallID, err = uc.runAgentCheck(actx, project, promptName, prompt)
+			} else {
+				analysisResult, gptCallID, err = uc.MistralService.CallMistral(prompt, false, service.Hack, "project", project.ID)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to call Mistral service for %s: %w", promptName, err)
 			}
 			if err := utils.ExtractJSON(analysisResult, &analysisDTO); err != nil {
 				log.Println("Error while extracting Project Analysis Response")
+				projectAnalysis := &model.ProjectAnalysisResult{
+					ProjectID:    project.ID,
+					PromptName:   promptName,
+					Language:     language,
+					Status:       model.AnalysisStatusError,
+					StatusReason: err.Error(),
+				}
+				if err := uc.ProjectAnalysisRepo.CreateOne(projectAnalysis); err != nil {
+					return fmt.Errorf("failed to save project analysis for %s: %w", promptName, err)
+				}
 				continue
 			}
 		} else {
@@ -298,12 +262,18 @@ func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
 			Compliance:      fmt.Sprintf("%t", analysisDTO.Compliance),
 			Issues:          strings.Join(analysisDTO.Issues, ", "),
 			Recommendations: strings.Join(analysisDTO.Recommendations, ", "),
+			Language:        language,
+			Status:          model.AnalysisStatusCompleted,
+			InputHash:       inputHash,
 		}
 
 		if err := uc.ProjectAnalysisRepo.CreateOne(projectAnalysis); err != nil {
 			return fmt.Errorf("failed to save project analysis for %s: %w", promptName, err)
 		}
 		log.Println("Successfully created ProjectAnalysis in the database.")
+		if err := uc.saveReviewFindings(actx, project.ID, nil, "", promptName, analysisDTO.Issues, gptCallID); err != nil {
+			log.Printf("failed to save findings for %s: %v", promptName, err)
+		}
 
 		// Optionally, update the project with GPTCallID
 		project.GPTCallID = &gptCallID
@@ -323,6 +293,11 @@ func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
 	errChan := make(chan error, len(files))
 	semaphore := make(chan struct{}, 5)
 	for _, file := range files {
+		// Files carried over unchanged from the previous version are not analyzed again
+		if uc.hasCarriedResults(file, language) {
+			continue
+		}
+
 		wg.Add(1)
 		semaphore <- struct{}{} // Acquire a slot
 
@@ -330,7 +305,7 @@ func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
 			defer wg.Done()
 			defer func() { <-semaphore }() // Release the slot
 
-			if err := uc.AnalyzeFile(fileID); err != nil {
+			if err := uc.analyzeFile(fileID, language, actx); err != nil {
 				errChan <- fmt.Errorf("failed to analyze file %d: %w", fileID, err)
 			}
 		}(file.ID)
@@ -340,6 +315,10 @@ func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
 	wg.Wait()
 	close(errChan)
 
+	if err := uc.deduplicateFindings(project.ID); err != nil {
+		log.Printf("failed to deduplicate findings of project %d: %v", project.ID, err)
+	}
+
 	// Check for errors
 	if len(errChan) > 0 {
 		return <-errChan // Return the first error
@@ -348,94 +327,84 @@ func (uc *ProjectAnalysisUsecase) AnalyzeProject(projectID uint) error {
 	return nil
 }
 
-func (uc *ProjectAnalysisUsecase) AnalyzeFile(fileID uint) error {
-
+// AnalyzeFile runs the file-level checks on a single file; an empty language means
+// the project language
+func (uc *ProjectAnalysisUsecase) AnalyzeFile(fileID uint, language string) (err error) {
 	file, err := uc.ProjectFileRepo.GetOneByID(fileID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve project file: %w", err)
 	}
-
 	project, err := uc.ProjectRepo.GetOneByID(file.ProjectID)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve project for file: %w", err)
 	}
+	if language != "" && !uc.Catalog.IsSupported(language) {
+		return fmt.Errorf("unsupported language: %s", language)
+	}
+	language = uc.Catalog.Resolve(language, project.Language)
 
-	var targetExtension string
-	if project.ProgrammingLanguageID == 1 {
-		targetExtension = ".py"
+	projectFiles, err := uc.ProjectFileRepo.GetFilesByProjectID(project.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project files: %w", err)
 	}
-	if !strings.HasSuffix(file.Name, targetExtension) {
-		return nil
+
+	run, err := uc.startRun(project.ID, &file.ID, model.AnalysisScopeFile, language)
+	if err != nil {
+		return err
 	}
+	defer func() { uc.finishRun(run, err) }()
 
-	// Construct the master prompt data
-	masterData := file_prompts.FileMasterData{
-		ProjectTree: project.Tree,
-		FilePath:    file.Path,
-		FileContent: file.Content,
+	actx := &analysisContext{RunID: run.ID, Index: findings.NewSymbolIndex(projectFiles)}
+	if err := uc.analyzeFile(fileID, language, actx); err != nil {
+		return err
 	}
 
-	// Construct the master prompt
-	masterPrompt, err := uc.PromptConstructor.GetPrompt(uc.Prompts.FileMasterPrompt, masterData, "Russian (русский)", true)
-	if err != nil {
-		return fmt.Errorf("failed to construct master prompt: %w", err)
+	if err := uc.deduplicateFindings(project.ID); err != nil {
+		log.Printf("failed to deduplicate findings of project %d: %v", project.ID, err)
 	}
+	return nil
+}
 
-	// Call the LLM to get applicable prompts
-	masterResult, _, err := uc.MistralService.CallMistral(masterPrompt, false, service.Hack, "file", file.ID)
+func (uc *ProjectAnalysisUsecase) analyzeFile(fileID uint, language string, actx *analysisContext) error {
+
+	file, err := uc.ProjectFileRepo.GetOneByID(fileID)
 	if err != nil {
-		return fmt.Errorf("failed to call Mistral service for master prompt: %w", err)
+		return fmt.Errorf("failed to retrieve project file: %w", err)
 	}
 
-	// Parse the LLM response to get a single int that indicates the file type
-	var masterResponse struct {
-		Value int `json:"value"`
-	}
-	if err := utils.ExtractJSON(masterResult, &masterResponse); err != nil {
-		masterResponse.Value = 2
+	project, err := uc.ProjectRepo.GetOneByID(file.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project for file: %w", err)
 	}
 
-	promptMap := map[string]types.Prompt{
-		"CodingStandards":         uc.Prompts.CodingStandards,
-		"ErrorHandlingAndLogging": uc.Prompts.ErrorHandlingAndLogging,
-		"AdditionalTechnicalFile": uc.Prompts.AdditionalTechnicalFile,
-		"DateTimeHandlingFile":    uc.Prompts.DateTimeHandlingFile,
+	if language != "" && !uc.Catalog.IsSupported(language) {
+		return fmt.Errorf("unsupported language: %s", language)
 	}
+	language = uc.Catalog.Resolve(language, project.Language)
+	llmLanguage := uc.Catalog.LLMLanguage(language)
 
-	// Prepare an array to hold the applicable prompts based on master response
-	var applicablePrompts []string
+	var targetExtension string
+	if project.ProgrammingLanguageID == 1 {
+		targetExtension = ".py"
+	}
+	if !strings.HasSuffix(file.Name, targetExtension) || projectConfig(project).Excluded(file.Path) {
+		return nil
+	}
 
-	if masterResponse.Value == 0 {
-		applicablePrompts = append(applicablePrompts, "ApplicationLayerCode")
-	} else if masterResponse.Value == 1 {
-		applicablePrompts = append(applicablePrompts, "AdaptersLayerCode")
+	// Issues linters already reported are passed so the review does not repeat them
+	fileFindings, err := uc.FindingRepo.GetManyByProjectFileID(file.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve file findings: %w", err)
 	}
+	knownIssues := formatKnownIssues(fileFindings)
 
-	// Always add universal prompts
-	for k := range promptMap {
-		applicablePrompts = append(applicablePrompts, k)
+	checks, err := uc.fileChecks(project, file.Path, file.Content, llmLanguage, "file", file.ID)
+	if err != nil {
+		return err
 	}
 
 	// Iterate over applicable prompts and perform analysis
-	for _, promptName := range applicablePrompts {
-
-		var promptTemplate types.Prompt
-		switch promptName {
-		case "ApplicationLayerCode":
-			promptTemplate = uc.Prompts.ApplicationLayerCode
-		case "AdaptersLayerCode":
-			promptTemplate = uc.Prompts.AdaptersLayerCode
-		case "CodingStandards":
-			promptTemplate = uc.Prompts.CodingStandards
-		case "ErrorHandlingAndLogging":
-			promptTemplate = uc.Prompts.ErrorHandlingAndLogging
-		case "AdditionalTechnicalFile":
-			promptTemplate = uc.Prompts.AdditionalTechnicalFile
-		case "DateTimeHandlingFile":
-			promptTemplate = uc.Prompts.DateTimeHandlingFile
-		default:
-			continue
-		}
+	for promptName, promptTemplate := range checks {
 
 		// Prepare prompt data based on prompt
 		var data types.PromptData
@@ -444,38 +413,44 @@ func (uc *ProjectAnalysisUsecase) AnalyzeFile(fileID uint) error {
 			data = file_prompts.ApplicationLayerCodeData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		case "AdaptersLayerCode":
 			data = file_prompts.AdaptersLayerCodeData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		case "CodingStandards":
 			data = file_prompts.CodingStandardsData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		case "ErrorHandlingAndLogging":
 			data = file_prompts.ErrorHandlingAndLoggingData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		case "AdditionalTechnicalFile":
 			data = file_prompts.AdditionalTechnicalFileData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		case "DateTimeHandlingFile":
 			data = file_prompts.DateTimeHandlingFileData{
 				FilePath:    file.Path,
 				FileContent: file.Content,
+				KnownIssues: knownIssues,
 			}
 		default:
 			continue
 		}
 
 		// Construct the prompt
-		prompt, err := uc.PromptConstructor.GetPrompt(promptTemplate, data, "Russian (русский)", true)
+		prompt, err := uc.PromptConstructor.GetPrompt(promptTemplate, data, llmLanguage, true)
 		if err != nil {
 			return fmt.Errorf("failed to construct prompt: %w", err)
 		}
@@ -500,11 +475,16 @@ func (uc *ProjectAnalysisUsecase) AnalyzeFile(fileID uint) error {
 			Compliance:      fmt.Sprintf("%t", analysisDTO.Compliance),
 			Issues:          strings.Join(analysisDTO.Issues, ", "),
 			Recommendations: strings.Join(analysisDTO.Recommendations, ", "),
+			Language:        language,
 		}
 		if err := uc.FileAnalysisRepo.CreateOne(fileAnalysis); err != nil {
 			return fmt.Errorf("failed to save file analysis: %w", err)
 		}
 		log.Println("Successfully created FileAnalysis in the database.")
+		fileID := file.ID
+		if err := uc.saveReviewFindings(actx, file.ProjectID, &fileID, file.Path, promptName, analysisDTO.Issues, gptCallID); err != nil {
+			log.Printf("failed to save findings for %s of file %d: %v", promptName, file.ID, err)
+		}
 
 		// Optionally, update the file with GPTCallID
 		file.GPTCallID = &gptCallID
@@ -514,5 +494,75 @@ func (uc *ProjectAnalysisUsecase) AnalyzeFile(fileID uint) error {
 		}
 	}
 
+	if uc.Options.CriticEnabled {
+		if err := uc.runCritic(file, llmLanguage); err != nil {
+			log.Printf("critic failed for file %d: %v", file.ID, err)
+		}
+	}
+
 	return nil
 }
+
+// fileChecks asks the master prompt which layer the file belongs to, unless the project's
+// configuration assigns it one, and returns the enabled file-level checks that apply to it
+// by prompt name
+func (uc *ProjectAnalysisUsecase) fileChecks(project *model.Project, path, content, llmLanguage, entityType string, entityID uint) (map[string]types.Prompt, error) {
+	config := projectConfig(project)
+	layer, assigned := config.Layer(path)
+	if !assigned {
+		var err error
+		if layer, err = uc.fileLayer(project, path, content, llmLanguage, entityType, entityID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Universal prompts always apply
+	checks := map[string]types.Prompt{
+		"CodingStandards":         uc.Prompts.CodingStandards,
+		"ErrorHandlingAndLogging": uc.Prompts.ErrorHandlingAndLogging,
+		"AdditionalTechnicalFile": uc.Prompts.AdditionalTechnicalFile,
+		"DateTimeHandlingFile":    uc.Prompts.DateTimeHandlingFile,
+	}
+	if layer == projectconfig.LayerApplication {
+		checks["ApplicationLayerCode"] = uc.Prompts.ApplicationLayerCode
+	} else if layer == projectconfig.LayerAdapters {
+		checks["AdaptersLayerCode"] = uc.Prompts.AdaptersLayerCode
+	}
+	for promptName := range checks {
+		if !config.CheckEnabled(promptName) {
+			delete(checks, promptName)
+		}
+	}
+	return checks, nil
+}
+
+// fileLayer asks the master prompt which layer the file belongs to
+func (uc *ProjectAnalysisUsecase) fileLayer(project *model.Project, path, content, llmLanguage, entityType string, entityID uint) (int, error) {
+	// Construct the master prompt data
+	masterData := file_prompts.FileMasterData{
+		ProjectTree: treeOrSummary(project),
+		FilePath:    path,
+		FileContent: content,
+	}
+
+	// Construct the master prompt
+	masterPrompt, err := uc.PromptConstructor.GetPrompt(uc.Prompts.FileMasterPrompt, masterData, llmLanguage, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to construct master prompt: %w", err)
+	}
+
+	// Call the LLM to get applicable prompts
+	masterResult, _, err := uc.MistralService.CallMistral(masterPrompt, false, service.Hack, entityType, entityID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call Mistral service for master prompt: %w", err)
+	}
+
+	// Parse the LLM response to get a single int that indicates the file type
+	var masterResponse struct {
+		Value int `json:"value"`
+	}
+	if err := utils.ExtractJSON(masterResult, &masterResponse); err != nil {
+		masterResponse.Value = projectconfig.LayerOther
+	}
+	return masterResponse.Value, nil
+}
@@ -0,0 +1,97 @@
+// internal/usecase/project_config.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/projectconfig"
+	"evraz_api/internal/prompts"
+)
+
+// projectConfigRule is the rule name of findings about an invalid configuration file
+const projectConfigRule = "ProjectConfig"
+
+// applyConfigFile looks for a configuration file in the root of an uploaded project,
+// stores the parsed configuration on the project and reports the problems found in it as
+// findings. The language of the configuration applies unless the upload requested one.
+func (uc *ProjectUsecase) applyConfigFile(project *model.Project, files []model.ProjectFile, languageRequested bool) error {
+	var configFile *model.ProjectFile
+	for _, name := range projectconfig.FileNames {
+		for i := range files {
+			if projectconfig.IsConfigFile(files[i].Path) && files[i].Name == name {
+				configFile = &files[i]
+				break
+			}
+		}
+		if configFile != nil {
+			break
+		}
+	}
+	if configFile == nil {
+		return nil
+	}
+
+	checks := append(append([]string{}, prompts.ProjectPromptNames...), prompts.FilePromptNames...)
+	config, issues := projectconfig.Parse([]byte(configFile.Content), checks, uc.Catalog.IsSupported)
+	encoded, err := config.Encode()
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	project.Config = encoded
+	project.ConfigPath = configFile.Path
+	if !languageRequested && config.Language != "" {
+		project.Language = uc.Catalog.Resolve(config.Language)
+	}
+	if err := uc.ProjectRepo.UpdateOneByID(project); err != nil {
+		return fmt.Errorf("failed to save configuration: %w", err)
+	}
+
+	fileID := configFile.ID
+	findings := make([]model.Finding, 0, len(issues))
+	for _, issue := range issues {
+		findings = append(findings, model.Finding{
+			ProjectID:     project.ID,
+			ProjectFileID: &fileID,
+			Source:        model.FindingSourceComputed,
+			RuleName:      projectConfigRule,
+			Severity:      model.SeverityMedium,
+			Message:       uc.Catalog.T(project.Language, "finding.config_invalid", configFile.Path, issue.Message),
+			Path:          configFile.Path,
+			Line:          issue.Line,
+		})
+	}
+	if err := uc.FindingRepo.CreateMany(findings); err != nil {
+		return fmt.Errorf("failed to save configuration findings: %w", err)
+	}
+	return nil
+}
+
+// projectConfig returns the configuration stored on the project. A configuration that
+// cannot be read is logged and the defaults apply, so that it never stops an analysis.
+func projectConfig(project *model.Project) *projectconfig.Config {
+	config, err := projectconfig.Load(project.Config)
+	if err != nil {
+		log.Printf("Ignoring configuration of project %d: %v", project.ID, err)
+	}
+	return config
+}
+
+// applySeverityOverrides gives findings the severity the configuration sets for their
+// rule and stores the ones that changed
+func (uc *ProjectAnalysisUsecase) applySeverityOverrides(config *projectconfig.Config, projectFindings []model.Finding) error {
+	var changed []model.Finding
+	for i := range projectFindings {
+		finding := &projectFindings[i]
+		if severity, ok := config.SeverityFor(finding.RuleName); ok && severity != finding.Severity {
+			finding.Severity = severity
+			changed = append(changed, *finding)
+		}
+	}
+	if err := uc.FindingRepo.UpdateColumns(changed, "severity"); err != nil {
+		return fmt.Errorf("failed to save severity overrides: %w", err)
+	}
+	return nil
+}
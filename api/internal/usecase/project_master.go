@@ -0,0 +1,121 @@
+// internal/usecase/project_master.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/prompts"
+	"evraz_api/internal/prompts/prompts_storage/project_prompts"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+// keyFileNames are the files reported to the project master agent when present
+var keyFileNames = map[string]bool{
+	"setup.py":               true,
+	"setup.cfg":              true,
+	"pyproject.toml":         true,
+	"README.md":              true,
+	"requirements.txt":       true,
+	"settings.py":            true,
+	"config.yaml":            true,
+	"conftest.py":            true,
+	"pytest.ini":             true,
+	"tox.ini":                true,
+	"Dockerfile":             true,
+	".gitignore":             true,
+	".editorconfig":          true,
+	".gitattributes":         true,
+	"transaction_manager.py": true,
+	"async_features.py":      true,
+	"datetime_utils.py":      true,
+}
+
+// checkDecision is the master agent's verdict for a single project-level check
+type checkDecision struct {
+	Name          string `json:"name"`
+	Applicable    bool   `json:"applicable"`
+	Justification string `json:"justification"`
+}
+
+// selectProjectChecks asks the project master agent which project-level checks apply.
+// Checks the agent does not mention, and all checks when the agent fails, are applicable.
+func (uc *ProjectAnalysisUsecase) selectProjectChecks(project *model.Project, projectTree, llmLanguage string) map[string]checkDecision {
+	decisions := make(map[string]checkDecision)
+	for _, promptName := range prompts.ProjectPromptNames {
+		decisions[promptName] = checkDecision{Name: promptName, Applicable: true}
+	}
+
+	files, err := uc.ProjectFileRepo.GetFilesByProjectID(project.ID)
+	if err != nil {
+		log.Printf("Failed to retrieve project files for master agent: %v", err)
+		return decisions
+	}
+
+	paths := make([]string, 0, len(files))
+	var keyFiles []string
+	dependencySummary := "No dependency file found"
+	for _, file := range files {
+		paths = append(paths, file.Path)
+		if keyFileNames[filepath.Base(file.Path)] {
+			keyFiles = append(keyFiles, file.Path)
+		}
+		if file.Name == "requirements.txt" {
+			dependencySummary = summarizeRequirements(file.Content)
+		}
+	}
+
+	data := project_prompts.ProjectMasterData{
+		ProjectTree:       projectTree,
+		ProjectLanguage:   utils.DetectProgrammingLanguage(paths),
+		KeyFiles:          keyFiles,
+		DependencySummary: dependencySummary,
+	}
+	prompt, err := uc.PromptConstructor.GetPrompt(uc.Prompts.ProjectMasterPrompt, data, llmLanguage, true)
+	if err != nil {
+		log.Printf("Failed to construct project master prompt: %v", err)
+		return decisions
+	}
+
+	masterResult, _, err := uc.MistralService.CallMistral(prompt, true, service.Hack, "projectMaster", project.ID)
+	if err != nil {
+		log.Printf("Failed to call Mistral service for project master prompt: %v", err)
+		return decisions
+	}
+
+	var masterResponse struct {
+		Checks []checkDecision `json:"checks"`
+	}
+	if err := utils.ExtractJSON(masterResult, &masterResponse); err != nil {
+		log.Println("Error while extracting Project Master Response")
+		return decisions
+	}
+
+	for _, decision := range masterResponse.Checks {
+		if _, ok := decisions[decision.Name]; ok {
+			decisions[decision.Name] = decision
+		}
+	}
+	return decisions
+}
+
+// summarizeRequirements lists the requirement specifiers of a requirements.txt
+func summarizeRequirements(content string) string {
+	var requirements []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		requirements = append(requirements, line)
+	}
+	if len(requirements) == 0 {
+		return "requirements.txt is empty"
+	}
+	return fmt.Sprintf("requirements.txt (%d entries): %s", len(requirements), strings.Join(requirements, ", "))
+}
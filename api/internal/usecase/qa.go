@@ -0,0 +1,261 @@
+// internal/usecase/qa.go
+
+package usecase
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/model"
+	"evraz_api/internal/prompts"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/repository"
+	"evraz_api/internal/retrieval"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+const (
+	qaContextChunks  = 6    // Chunks retrieved for a question
+	qaHistoryLength  = 6    // Earlier messages passed with a question
+	qaHistoryMessage = 1500 // Bytes of an earlier message passed with a question
+)
+
+type QAUsecase struct {
+	ProjectRepo       repository.ProjectRepository
+	ProjectFileRepo   repository.ProjectFileRepository
+	CodeChunkRepo     repository.CodeChunkRepository
+	QAMessageRepo     repository.QAMessageRepository
+	MistralService    service.MistralService
+	PromptConstructor *prompts.PromptConstructor
+	Catalog           *i18n.Catalog
+}
+
+func NewQAUsecase(
+	projectRepo repository.ProjectRepository,
+	projectFileRepo repository.ProjectFileRepository,
+	codeChunkRepo repository.CodeChunkRepository,
+	qaMessageRepo repository.QAMessageRepository,
+	mistralService service.MistralService,
+	catalog *i18n.Catalog,
+) *QAUsecase {
+	return &QAUsecase{
+		ProjectRepo:       projectRepo,
+		ProjectFileRepo:   projectFileRepo,
+		CodeChunkRepo:     codeChunkRepo,
+		QAMessageRepo:     qaMessageRepo,
+		MistralService:    mistralService,
+		PromptConstructor: prompts.NewPromptConstructor(),
+		Catalog:           catalog,
+	}
+}
+
+// Citation is a line range of a project file an answer relies on
+type Citation struct {
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// QAAnswer is the answer to a question and the stored assistant message
+type QAAnswer struct {
+	MessageID uint
+	Answer    string
+	Citations []Citation
+}
+
+// Ask answers a question about the project from the chunks the BM25 index retrieves for
+// it, taking the earlier conversation into account. Both the question and the answer are
+// kept in the project's conversation.
+func (uc *QAUsecase) Ask(projectID uint, question, language string) (QAAnswer, error) {
+	question = strings.TrimSpace(question)
+	if question == "" {
+		return QAAnswer{}, errors.New("question is required")
+	}
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if language != "" && !uc.Catalog.IsSupported(language) {
+		return QAAnswer{}, fmt.Errorf("unsupported language: %s", language)
+	}
+	language = uc.Catalog.Resolve(language, project.Language)
+
+	chunks, err := uc.projectChunks(projectID)
+	if err != nil {
+		return QAAnswer{}, err
+	}
+
+	// Retrieve the chunks for the question together with the previous one, so that
+	// follow-up questions keep their subject
+	history, err := uc.QAMessageRepo.GetLastByProjectID(projectID, qaHistoryLength)
+	if err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to retrieve conversation: %w", err)
+	}
+	query := question
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Role == model.QARoleUser {
+			query = history[i].Content + " " + question
+			break
+		}
+	}
+	retrieved := searchChunks(chunks, query, qaContextChunks)
+
+	var contextBuilder strings.Builder
+	for i, chunk := range retrieved {
+		contextBuilder.WriteString(fmt.Sprintf("[%d] %s, lines %d-%d:\n%s\n\n", i+1, chunk.Path, chunk.StartLine, chunk.EndLine, chunk.Content))
+	}
+	if len(retrieved) == 0 {
+		contextBuilder.WriteString("No code matches the question.")
+	}
+
+	data := helper_prompts.AskCodebaseData{
+		History:  formatConversation(history),
+		Context:  contextBuilder.String(),
+		Question: question,
+	}
+	prompt, err := uc.PromptConstructor.GetPrompt(helper_prompts.AskCodebasePrompt, data, uc.Catalog.LLMLanguage(language), true)
+	if err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to construct question prompt: %w", err)
+	}
+	reply, gptCallID, err := uc.MistralService.CallMistral(prompt, true, service.Hack, "project", projectID)
+	if err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to call Mistral service for question: %w", err)
+	}
+
+	var response struct {
+		Answer    string `json:"answer"`
+		Citations []int  `json:"citations"`
+	}
+	if err := utils.ExtractJSON(reply, &response); err != nil || strings.TrimSpace(response.Answer) == "" {
+		// Keep the reply as it is rather than losing the answer
+		response.Answer = strings.TrimSpace(reply)
+		response.Citations = nil
+	}
+
+	citations := make([]Citation, 0, len(response.Citations))
+	cited := make(map[int]bool)
+	for _, number := range response.Citations {
+		if number < 1 || number > len(retrieved) || cited[number] {
+			continue
+		}
+		cited[number] = true
+		chunk := retrieved[number-1]
+		citations = append(citations, Citation{Path: chunk.Path, StartLine: chunk.StartLine, EndLine: chunk.EndLine})
+	}
+	citationsJSON, err := json.Marshal(citations)
+	if err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to marshal citations: %w", err)
+	}
+
+	if err := uc.QAMessageRepo.CreateOne(&model.QAMessage{ProjectID: projectID, Role: model.QARoleUser, Content: question}); err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to save question: %w", err)
+	}
+	answer := &model.QAMessage{
+		ProjectID: projectID,
+		Role:      model.QARoleAssistant,
+		Content:   response.Answer,
+		Citations: string(citationsJSON),
+		GPTCallID: &gptCallID,
+	}
+	if err := uc.QAMessageRepo.CreateOne(answer); err != nil {
+		return QAAnswer{}, fmt.Errorf("failed to save answer: %w", err)
+	}
+
+	return QAAnswer{MessageID: answer.ID, Answer: response.Answer, Citations: citations}, nil
+}
+
+// GetConversation returns the questions and answers about the project in order
+func (uc *QAUsecase) GetConversation(projectID uint) ([]model.QAMessage, error) {
+	messages, err := uc.QAMessageRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve conversation: %w", err)
+	}
+	return messages, nil
+}
+
+// projectChunks returns the indexed chunks of the project, indexing projects uploaded
+// before question answering existed
+func (uc *QAUsecase) projectChunks(projectID uint) ([]model.CodeChunk, error) {
+	chunks, err := uc.CodeChunkRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve code chunks: %w", err)
+	}
+	if len(chunks) > 0 {
+		return chunks, nil
+	}
+
+	files, err := uc.ProjectFileRepo.GetFilesByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	return indexCodeChunks(uc.CodeChunkRepo, projectID, files)
+}
+
+// indexCodeChunks splits the project files into chunks and stores them with their term
+// frequencies for BM25 retrieval
+func indexCodeChunks(repo repository.CodeChunkRepository, projectID uint, files []model.ProjectFile) ([]model.CodeChunk, error) {
+	var chunks []model.CodeChunk
+	for _, file := range files {
+		for _, chunk := range retrieval.ChunkFile(file) {
+			frequencies, length := retrieval.TermFrequencies(file.Path + "\n" + chunk.Content)
+			if length == 0 {
+				continue
+			}
+			terms, err := json.Marshal(frequencies)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal terms of %s: %w", file.Path, err)
+			}
+			chunks = append(chunks, model.CodeChunk{
+				ProjectID:     projectID,
+				ProjectFileID: file.ID,
+				Path:          file.Path,
+				StartLine:     chunk.StartLine,
+				EndLine:       chunk.EndLine,
+				Content:       chunk.Content,
+				Terms:         string(terms),
+				Length:        length,
+			})
+		}
+	}
+	if err := repo.CreateMany(chunks); err != nil {
+		return nil, fmt.Errorf("failed to save code chunks: %w", err)
+	}
+	return chunks, nil
+}
+
+// searchChunks returns the chunks that best match the query
+func searchChunks(chunks []model.CodeChunk, query string, limit int) []model.CodeChunk {
+	documents := make([]retrieval.Document, len(chunks))
+	for i, chunk := range chunks {
+		documents[i].Length = chunk.Length
+		if err := json.Unmarshal([]byte(chunk.Terms), &documents[i].Terms); err != nil {
+			documents[i].Terms = nil
+		}
+	}
+
+	results := retrieval.NewIndex(documents).Search(query, limit)
+	retrieved := make([]model.CodeChunk, len(results))
+	for i, result := range results {
+		retrieved[i] = chunks[result.Document]
+	}
+	return retrieved
+}
+
+func formatConversation(messages []model.QAMessage) string {
+	if len(messages) == 0 {
+		return "No earlier questions."
+	}
+	var sb strings.Builder
+	for _, message := range messages {
+		content := message.Content
+		if len(content) > qaHistoryMessage {
+			content = utils.TruncateBytes(content, qaHistoryMessage) + "..."
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s\n", message.Role, content))
+	}
+	return sb.String()
+}
@@ -0,0 +1,71 @@
+// internal/usecase/quality.go
+
+package usecase
+
+import (
+	"fmt"
+	"strings"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/projectconfig"
+	"evraz_api/internal/quality"
+)
+
+// scoreRun computes the quality score of a run from its snapshot and stores it on the run
+func (uc *ProjectAnalysisUsecase) scoreRun(run *model.AnalysisRun, project *model.Project, files []model.ProjectFile, snapshot []model.AnalysisRunFinding, compliance []model.AnalysisRunCompliance) error {
+	score := quality.Compute(snapshot, compliance, codeLines(project, projectConfig(project), files))
+
+	run.Score = &score.Score
+	run.Grade = score.Grade
+	run.CodeLines = score.CodeLines
+	categories := make([]model.AnalysisRunCategoryScore, len(score.Categories))
+	for i, category := range score.Categories {
+		categories[i] = model.AnalysisRunCategoryScore{
+			AnalysisRunID: run.ID,
+			Category:      category.Category,
+			Score:         category.Score,
+			Penalty:       category.Penalty,
+			Findings:      category.Findings,
+			FailedChecks:  category.FailedChecks,
+		}
+	}
+	if err := uc.AnalysisRunRepo.SaveScore(run, categories); err != nil {
+		return fmt.Errorf("failed to save quality score: %w", err)
+	}
+	run.CategoryScores = categories
+	return nil
+}
+
+// GetLatestScores returns the newest scored run of each of the projects by project ID
+func (uc *ProjectAnalysisUsecase) GetLatestScores(projectIDs []uint) (map[uint]model.AnalysisRun, error) {
+	runs, err := uc.AnalysisRunRepo.GetLatestScored(projectIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve quality scores: %w", err)
+	}
+	scores := make(map[uint]model.AnalysisRun, len(runs))
+	for _, run := range runs {
+		scores[run.ProjectID] = run
+	}
+	return scores, nil
+}
+
+// codeLines counts the non-blank lines of the files the analysis reviews
+func codeLines(project *model.Project, config *projectconfig.Config, files []model.ProjectFile) int {
+	var targetExtension string
+	if project.ProgrammingLanguageID == 1 {
+		targetExtension = ".py"
+	}
+
+	count := 0
+	for _, file := range files {
+		if !strings.HasSuffix(file.Path, targetExtension) || config.Excluded(file.Path) {
+			continue
+		}
+		for _, line := range strings.Split(file.Content, "\n") {
+			if strings.TrimSpace(line) != "" {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,253 @@
+// internal/usecase/run_diff.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"evraz_api/internal/model"
+)
+
+// ComplianceFlip is a check whose verdict changed between two runs
+type ComplianceFlip struct {
+	PromptName string
+	Path       string // Empty for project-level checks
+	From       string
+	To         string
+}
+
+// RunDiff compares the findings and verdicts of two runs of a project or of its versions
+type RunDiff struct {
+	From            model.AnalysisRun
+	To              model.AnalysisRun
+	New             []model.AnalysisRunFinding // Only in the later run
+	Resolved        []model.AnalysisRunFinding // Only in the earlier run
+	Persisting      []model.AnalysisRunFinding // In both runs, as seen in the later run
+	ComplianceFlips []ComplianceFlip
+}
+
+// snapshotRun fingerprints the project's findings, applies the severities of the project's
+// configuration, carries over their triage, applies the suppression rules and the baseline
+// and stores the visible findings nothing suppresses together with the latest verdict of
+// every check, so that later runs can be compared with this one. The quality score of the
+// run is computed from that snapshot.
+func (uc *ProjectAnalysisUsecase) snapshotRun(run *model.AnalysisRun) error {
+	files, err := uc.ProjectFileRepo.GetFilesWithAnalysisByProjectID(run.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	contents := make(map[uint]string, len(files))
+	for _, file := range files {
+		contents[file.ID] = file.Content
+	}
+
+	projectFindings, err := uc.FindingRepo.GetManyByProjectID(run.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve findings: %w", err)
+	}
+	if err := fingerprintFindings(uc.FindingRepo, contents, projectFindings); err != nil {
+		return err
+	}
+	project, err := uc.ProjectRepo.GetOneByID(run.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if err := uc.applySeverityOverrides(projectConfig(project), projectFindings); err != nil {
+		return err
+	}
+	if err := uc.carryOverTriage(run.ProjectID, projectFindings); err != nil {
+		return err
+	}
+	if err := applySuppressions(uc.ProjectRepo, uc.FindingRepo, uc.SuppressionRepo, run.ProjectID, projectFindings); err != nil {
+		return err
+	}
+
+	var snapshot []model.AnalysisRunFinding
+	for _, finding := range projectFindings {
+		if finding.Hidden || finding.CanonicalID != nil || finding.Suppressed {
+			continue
+		}
+		snapshot = append(snapshot, model.AnalysisRunFinding{
+			AnalysisRunID: run.ID,
+			FindingID:     finding.ID,
+			Fingerprint:   finding.Fingerprint,
+			Source:        finding.Source,
+			RuleName:      finding.RuleName,
+			Severity:      finding.Severity,
+			Message:       finding.Message,
+			Path:          finding.Path,
+			Line:          finding.Line,
+		})
+	}
+
+	projectResults, err := uc.ProjectAnalysisRepo.GetResultsByProjectID(run.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project analysis results: %w", err)
+	}
+	var compliance []model.AnalysisRunCompliance
+	latestProject := make(map[string]model.ProjectAnalysisResult)
+	for _, result := range projectResults {
+		if result.Language != run.Language || !result.Completed() {
+			continue
+		}
+		if latest, ok := latestProject[result.PromptName]; !ok || result.ID > latest.ID {
+			latestProject[result.PromptName] = result
+		}
+	}
+	for promptName, result := range latestProject {
+		compliance = append(compliance, model.AnalysisRunCompliance{
+			AnalysisRunID: run.ID,
+			PromptName:    promptName,
+			Compliance:    result.Compliance,
+		})
+	}
+	for _, file := range files {
+		latestFile := make(map[string]model.FileAnalysisResult)
+		for _, result := range file.FileAnalysisResults {
+			if result.Language != run.Language {
+				continue
+			}
+			if latest, ok := latestFile[result.PromptName]; !ok || result.ID > latest.ID {
+				latestFile[result.PromptName] = result
+			}
+		}
+		for promptName, result := range latestFile {
+			compliance = append(compliance, model.AnalysisRunCompliance{
+				AnalysisRunID: run.ID,
+				PromptName:    promptName,
+				Path:          file.Path,
+				Compliance:    result.Compliance,
+			})
+		}
+	}
+
+	if err := uc.AnalysisRunRepo.CreateSnapshot(snapshot, compliance); err != nil {
+		return err
+	}
+	return uc.scoreRun(run, project, files, snapshot, compliance)
+}
+
+// DiffRuns compares two completed runs of the project's versions. When a run is not given,
+// the latest completed run of the project and the completed run before it are used.
+func (uc *ProjectAnalysisUsecase) DiffRuns(projectID, fromRunID, toRunID uint) (*RunDiff, error) {
+	versionIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := uc.AnalysisRunRepo.GetManyByProjectIDs(versionIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve analysis runs: %w", err)
+	}
+	var completed []model.AnalysisRun
+	for _, run := range runs {
+		if run.Status == model.RunStatusCompleted {
+			completed = append(completed, run)
+		}
+	}
+
+	var from, to *model.AnalysisRun
+	for i := range completed {
+		run := &completed[i]
+		switch {
+		case toRunID != 0 && run.ID == toRunID:
+			to = run
+		case toRunID == 0 && to == nil && run.ProjectID == projectID:
+			to = run
+		}
+	}
+	if to == nil {
+		if toRunID != 0 {
+			return nil, fmt.Errorf("run %d is not a completed run of this project", toRunID)
+		}
+		return nil, errors.New("the project has no completed runs")
+	}
+	for i := range completed {
+		run := &completed[i]
+		switch {
+		case fromRunID != 0 && run.ID == fromRunID:
+			from = run
+		case fromRunID == 0 && from == nil && run.ID < to.ID:
+			from = run
+		}
+	}
+	if from == nil {
+		if fromRunID != 0 {
+			return nil, fmt.Errorf("run %d is not a completed run of this project", fromRunID)
+		}
+		return nil, errors.New("the project has no earlier completed run to compare with")
+	}
+
+	fromFindings, err := uc.AnalysisRunRepo.GetSnapshotFindings(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve findings of run %d: %w", from.ID, err)
+	}
+	toFindings, err := uc.AnalysisRunRepo.GetSnapshotFindings(to.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve findings of run %d: %w", to.ID, err)
+	}
+	fromCompliance, err := uc.AnalysisRunRepo.GetSnapshotCompliance(from.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve verdicts of run %d: %w", from.ID, err)
+	}
+	toCompliance, err := uc.AnalysisRunRepo.GetSnapshotCompliance(to.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve verdicts of run %d: %w", to.ID, err)
+	}
+
+	diff := &RunDiff{From: *from, To: *to}
+	diff.New, diff.Resolved, diff.Persisting = matchFindings(fromFindings, toFindings)
+
+	type checkKey struct{ promptName, path string }
+	before := make(map[checkKey]string, len(fromCompliance))
+	for _, verdict := range fromCompliance {
+		before[checkKey{verdict.PromptName, verdict.Path}] = verdict.Compliance
+	}
+	for _, verdict := range toCompliance {
+		previous, ok := before[checkKey{verdict.PromptName, verdict.Path}]
+		if !ok || previous == verdict.Compliance {
+			continue
+		}
+		diff.ComplianceFlips = append(diff.ComplianceFlips, ComplianceFlip{
+			PromptName: verdict.PromptName,
+			Path:       verdict.Path,
+			From:       previous,
+			To:         verdict.Compliance,
+		})
+	}
+	sort.Slice(diff.ComplianceFlips, func(i, j int) bool {
+		if diff.ComplianceFlips[i].Path != diff.ComplianceFlips[j].Path {
+			return diff.ComplianceFlips[i].Path < diff.ComplianceFlips[j].Path
+		}
+		return diff.ComplianceFlips[i].PromptName < diff.ComplianceFlips[j].PromptName
+	})
+
+	return diff, nil
+}
+
+// matchFindings splits the findings of two runs by fingerprint. Fingerprints are matched
+// as a multiset: a problem raised twice in the earlier run and once in the later one is
+// one persisting and one resolved finding.
+func matchFindings(fromFindings, toFindings []model.AnalysisRunFinding) (added, resolved, persisting []model.AnalysisRunFinding) {
+	remaining := make(map[string]int)
+	for _, finding := range fromFindings {
+		remaining[finding.Fingerprint]++
+	}
+	for _, finding := range toFindings {
+		if remaining[finding.Fingerprint] > 0 {
+			remaining[finding.Fingerprint]--
+			persisting = append(persisting, finding)
+		} else {
+			added = append(added, finding)
+		}
+	}
+	for _, finding := range fromFindings {
+		if remaining[finding.Fingerprint] > 0 {
+			remaining[finding.Fingerprint]--
+			resolved = append(resolved, finding)
+		}
+	}
+	return added, resolved, persisting
+}
@@ -0,0 +1,351 @@
+// internal/usecase/summarization.go
+
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"path"
+	"sort"
+	"strings"
+
+	"evraz_api/internal/dto/llm_responses"
+	"evraz_api/internal/model"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/prompts/types"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+const (
+	// summaryBudget is the number of characters of tree or code a prompt section may hold
+	// before summaries are passed instead
+	summaryBudget = 24000
+	// summaryFileChars caps the bytes of a single file sent to the file summary prompt
+	summaryFileChars = 6000
+	// summaryRollupRounds caps how many times summaries are summarized again to fit
+	summaryRollupRounds = 4
+)
+
+// summarizer map-reduces a project into file, package and project summaries. Summaries
+// are built on first use and stored, so a project is only summarized once.
+type summarizer struct {
+	uc          *ProjectAnalysisUsecase
+	project     *model.Project
+	files       []model.ProjectFile
+	llmLanguage string
+	summaries   map[uint]string // Project file ID -> summary
+	overview    string          // Project and package summaries
+}
+
+func (uc *ProjectAnalysisUsecase) newSummarizer(project *model.Project, files []model.ProjectFile, llmLanguage string) *summarizer {
+	s := &summarizer{
+		uc:          uc,
+		project:     project,
+		files:       files,
+		llmLanguage: llmLanguage,
+		summaries:   make(map[uint]string, len(files)),
+	}
+	for _, file := range files {
+		if file.Summary != "" {
+			s.summaries[file.ID] = file.Summary
+		}
+	}
+	return s
+}
+
+// Tree returns the project tree, or the project overview when the tree does not fit
+func (s *summarizer) Tree() string {
+	if len(s.project.Tree) <= summaryBudget {
+		return s.project.Tree
+	}
+	overview, err := s.Overview()
+	if err != nil {
+		log.Printf("failed to summarize project %d: %v", s.project.ID, err)
+		return s.project.Tree
+	}
+	return overview
+}
+
+// Files returns the content built from the files when it fits, otherwise their summaries
+func (s *summarizer) Files(content string, files []model.ProjectFile) string {
+	if len(content) <= summaryBudget {
+		return content
+	}
+	if err := s.summarizeFiles(files); err != nil {
+		log.Printf("failed to summarize files of project %d: %v", s.project.ID, err)
+		return content
+	}
+
+	var sb strings.Builder
+	for _, file := range files {
+		sb.WriteString(fmt.Sprintf("Path: %s\nSummary:\n%s\n\n", file.Path, s.summaries[file.ID]))
+	}
+	return sb.String()
+}
+
+// Contents is Files for prompt sections keyed by file path
+func (s *summarizer) Contents(contents map[string]string, files []model.ProjectFile) map[string]string {
+	size := 0
+	for _, content := range contents {
+		size += len(content)
+	}
+	if size <= summaryBudget {
+		return contents
+	}
+	if err := s.summarizeFiles(files); err != nil {
+		log.Printf("failed to summarize files of project %d: %v", s.project.ID, err)
+		return contents
+	}
+
+	summarized := make(map[string]string, len(files))
+	for _, file := range files {
+		summarized[file.Path] = "Summary: " + s.summaries[file.ID]
+	}
+	return summarized
+}
+
+// Overview returns the project summary followed by the package summaries that fit
+func (s *summarizer) Overview() (string, error) {
+	if s.overview != "" {
+		return s.overview, nil
+	}
+
+	packages, err := s.uc.PackageSummaryRepo.GetManyByProjectID(s.project.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve package summaries: %w", err)
+	}
+	if s.project.Summary == "" || len(packages) == 0 {
+		if packages, err = s.summarizePackages(); err != nil {
+			return "", err
+		}
+
+		parts := make([]string, len(packages))
+		for i, pkg := range packages {
+			parts[i] = fmt.Sprintf("Package: %s\nSummary:\n%s\n", pkg.Path, pkg.Summary)
+		}
+		summary, _, err := s.rollup(helper_prompts.ProjectSummaryPrompt, "project "+s.project.Name, parts)
+		if err != nil {
+			return "", err
+		}
+		s.project.Summary = summary
+		if err := s.uc.ProjectRepo.UpdateOneByID(s.project); err != nil {
+			return "", fmt.Errorf("failed to save project summary: %w", err)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Project summary:\n%s\n\nPackages:\n", s.project.Summary))
+	for _, pkg := range packages {
+		section := fmt.Sprintf("%s (%d files): %s\n", pkg.Path, pkg.Files, pkg.Summary)
+		if sb.Len()+len(section) > summaryBudget {
+			sb.WriteString("...\n")
+			break
+		}
+		sb.WriteString(section)
+	}
+	s.overview = sb.String()
+	return s.overview, nil
+}
+
+// summarizePackages rolls the file summaries of every directory up into a package summary
+func (s *summarizer) summarizePackages() ([]model.PackageSummary, error) {
+	if err := s.summarizeFiles(s.files); err != nil {
+		return nil, err
+	}
+
+	byPackage := make(map[string][]model.ProjectFile)
+	for _, file := range s.files {
+		dir := packageOf(file.Path)
+		byPackage[dir] = append(byPackage[dir], file)
+	}
+	dirs := make([]string, 0, len(byPackage))
+	for dir := range byPackage {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	packages := make([]model.PackageSummary, 0, len(dirs))
+	for _, dir := range dirs {
+		files := byPackage[dir]
+		pkg := model.PackageSummary{ProjectID: s.project.ID, Path: dir, Files: len(files)}
+
+		// A single file already describes its package
+		if len(files) == 1 {
+			pkg.Summary = s.summaries[files[0].ID]
+			packages = append(packages, pkg)
+			continue
+		}
+
+		parts := make([]string, len(files))
+		for i, file := range files {
+			parts[i] = fmt.Sprintf("Path: %s\nSummary:\n%s\n", file.Path, s.summaries[file.ID])
+		}
+		summary, gptCallID, err := s.rollup(helper_prompts.PackageSummaryPrompt, "package "+dir, parts)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Summary = summary
+		pkg.GPTCallID = &gptCallID
+		packages = append(packages, pkg)
+	}
+
+	if err := s.uc.PackageSummaryRepo.DeleteByProjectID(s.project.ID); err != nil {
+		return nil, fmt.Errorf("failed to delete package summaries: %w", err)
+	}
+	if err := s.uc.PackageSummaryRepo.CreateMany(packages); err != nil {
+		return nil, fmt.Errorf("failed to save package summaries: %w", err)
+	}
+	return packages, nil
+}
+
+// summarizeFiles summarizes the files that have no summary yet, in batches that fit the budget
+func (s *summarizer) summarizeFiles(files []model.ProjectFile) error {
+	var missing []model.ProjectFile
+	for _, file := range files {
+		if s.summaries[file.ID] == "" {
+			missing = append(missing, file)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sections := make([]string, len(missing))
+	for i, file := range missing {
+		content := file.Content
+		if len(content) > summaryFileChars {
+			content = utils.TruncateBytes(content, summaryFileChars) + "\n... (truncated)"
+		}
+		sections[i] = fmt.Sprintf("File id %d: %s\n%s\n", file.ID, file.Path, content)
+	}
+
+	offset := 0
+	for _, batch := range batchSections(sections, summaryBudget) {
+		batchFiles := missing[offset : offset+len(batch)]
+		offset += len(batch)
+
+		data := helper_prompts.FileSummaryData{Files: strings.Join(batch, "\n")}
+		prompt, err := s.uc.PromptConstructor.GetPrompt(helper_prompts.FileSummaryPrompt, data, s.llmLanguage, true)
+		if err != nil {
+			return fmt.Errorf("failed to construct file summary prompt: %w", err)
+		}
+		reply, gptCallID, err := s.uc.MistralService.CallMistral(prompt, true, service.Hack, "project", s.project.ID)
+		if err != nil {
+			return fmt.Errorf("failed to call Mistral service for file summaries: %w", err)
+		}
+
+		var response llm_responses.ProjectAnalysisResponse
+		if err := utils.ExtractJSON(reply, &response); err != nil {
+			return fmt.Errorf("failed to parse file summaries: %w", err)
+		}
+
+		inBatch := make(map[uint]bool, len(batchFiles))
+		for _, file := range batchFiles {
+			inBatch[file.ID] = true
+		}
+		for _, overview := range response.FilesOverview {
+			summary := strings.TrimSpace(overview.Purpose + " " + overview.Context)
+			if !inBatch[overview.ID] || summary == "" {
+				continue
+			}
+			s.summaries[overview.ID] = summary
+			if err := s.uc.ProjectFileRepo.UpdateSummary(overview.ID, summary, gptCallID); err != nil {
+				return fmt.Errorf("failed to save summary of file %d: %w", overview.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// rollup summarizes parts with the prompt, summarizing batches of them first while they
+// do not fit in a single prompt
+func (s *summarizer) rollup(prompt types.Prompt, scope string, parts []string) (string, uint, error) {
+	for round := 0; round < summaryRollupRounds; round++ {
+		batches := batchSections(parts, summaryBudget)
+		if len(batches) == 1 {
+			break
+		}
+
+		next := make([]string, len(batches))
+		for i, batch := range batches {
+			summary, _, err := s.summarize(prompt, fmt.Sprintf("%s, part %d of %d", scope, i+1, len(batches)), strings.Join(batch, "\n"))
+			if err != nil {
+				return "", 0, err
+			}
+			next[i] = fmt.Sprintf("Part %d:\n%s\n", i+1, summary)
+		}
+		parts = next
+	}
+
+	summaries := utils.TruncateBytes(strings.Join(parts, "\n"), summaryBudget)
+	return s.summarize(prompt, scope, summaries)
+}
+
+func (s *summarizer) summarize(prompt types.Prompt, scope, summaries string) (string, uint, error) {
+	data := helper_prompts.SummaryRollupData{Scope: scope, Summaries: summaries}
+	text, err := s.uc.PromptConstructor.GetPrompt(prompt, data, s.llmLanguage, true)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to construct summary prompt for %s: %w", scope, err)
+	}
+	reply, gptCallID, err := s.uc.MistralService.CallMistral(text, true, service.Hack, "project", s.project.ID)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to call Mistral service for %s summary: %w", scope, err)
+	}
+
+	var response llm_responses.SummaryResponse
+	if err := utils.ExtractJSON(reply, &response); err != nil {
+		return "", 0, fmt.Errorf("failed to parse %s summary: %w", scope, err)
+	}
+	if strings.TrimSpace(response.Summary) == "" {
+		return "", 0, fmt.Errorf("empty %s summary", scope)
+	}
+	return strings.TrimSpace(response.Summary), gptCallID, nil
+}
+
+// batchSections groups consecutive sections into batches of at most budget characters;
+// a section larger than the budget forms a batch of its own
+func batchSections(sections []string, budget int) [][]string {
+	var batches [][]string
+	var batch []string
+	size := 0
+	for _, section := range sections {
+		if len(batch) > 0 && size+len(section) > budget {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+		batch = append(batch, section)
+		size += len(section)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// packageOf returns the directory of a project file, "/" for files in the root
+func packageOf(filePath string) string {
+	dir := path.Dir("/" + strings.TrimPrefix(filePath, "/"))
+	if dir == "." {
+		return "/"
+	}
+	return dir
+}
+
+// treeOrSummary returns the project tree, or the stored project summary when the tree
+// does not fit in a prompt
+func treeOrSummary(project *model.Project) string {
+	if len(project.Tree) > summaryBudget && project.Summary != "" {
+		return project.Summary
+	}
+	return project.Tree
+}
+
+// GetPackageSummaries returns the package summaries of a summarized project
+func (uc *ProjectAnalysisUsecase) GetPackageSummaries(projectID uint) ([]model.PackageSummary, error) {
+	packages, err := uc.PackageSummaryRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve package summaries: %w", err)
+	}
+	return packages, nil
+}
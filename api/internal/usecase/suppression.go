@@ -0,0 +1,221 @@
+// internal/usecase/suppression.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"evraz_api/internal/findings"
+	"evraz_api/internal/model"
+	"evraz_api/internal/repository"
+)
+
+// GetSuppressions returns the suppression rules of the project, expired ones included, and
+// its latest baseline, which is nil when none was taken. Rules and baselines apply to all
+// versions of a project, so that accepting a problem once is enough for later uploads.
+func (uc *FindingUsecase) GetSuppressions(projectID uint) ([]model.SuppressionRule, *model.Baseline, error) {
+	projectIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return nil, nil, err
+	}
+	rules, err := uc.SuppressionRepo.GetRulesByProjectIDs(projectIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve suppression rules: %w", err)
+	}
+	baseline, err := uc.SuppressionRepo.GetLatestBaseline(projectIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve baseline: %w", err)
+	}
+	return rules, baseline, nil
+}
+
+// CreateSuppressionRule stores a suppression rule and applies it to the project's findings
+func (uc *FindingUsecase) CreateSuppressionRule(projectID uint, rule model.SuppressionRule) (*model.SuppressionRule, error) {
+	if _, err := uc.ProjectRepo.GetOneByID(projectID); err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if err := findings.ValidateRule(rule); err != nil {
+		return nil, err
+	}
+
+	rule.ID = 0
+	rule.ProjectID = projectID
+	if err := uc.SuppressionRepo.CreateRule(&rule); err != nil {
+		return nil, fmt.Errorf("failed to save suppression rule: %w", err)
+	}
+	if err := uc.refreshSuppressions(projectID); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// DeleteSuppressionRule removes a suppression rule of the project; the findings it
+// suppressed are reported again
+func (uc *FindingUsecase) DeleteSuppressionRule(projectID, ruleID uint) error {
+	projectIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return err
+	}
+	rule, err := uc.SuppressionRepo.GetRuleByID(ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve suppression rule: %w", err)
+	}
+	if !containsID(projectIDs, rule.ProjectID) {
+		return errors.New("the suppression rule does not belong to the project")
+	}
+
+	if err := uc.SuppressionRepo.DeleteRule(rule.ID); err != nil {
+		return fmt.Errorf("failed to delete suppression rule: %w", err)
+	}
+	return uc.refreshSuppressions(projectID)
+}
+
+// CreateBaseline accepts the project's current findings: later runs only report findings
+// whose fingerprint is not part of the baseline. It replaces an earlier baseline.
+func (uc *FindingUsecase) CreateBaseline(projectID uint, author, reason string) (*model.Baseline, error) {
+	projectIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return nil, err
+	}
+	files, err := uc.ProjectFileRepo.GetFilesByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+	contents := make(map[uint]string, len(files))
+	for _, file := range files {
+		contents[file.ID] = file.Content
+	}
+	projectFindings, err := uc.FindingRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve findings: %w", err)
+	}
+	// Findings imported since the last run have no fingerprint yet
+	if err := fingerprintFindings(uc.FindingRepo, contents, projectFindings); err != nil {
+		return nil, err
+	}
+
+	baseline := &model.Baseline{ProjectID: projectID, Author: author, Reason: reason}
+	seen := make(map[string]bool)
+	for _, finding := range projectFindings {
+		if finding.Hidden || finding.CanonicalID != nil || seen[finding.Fingerprint] {
+			continue
+		}
+		seen[finding.Fingerprint] = true
+		baseline.Fingerprints = append(baseline.Fingerprints, model.BaselineFingerprint{Fingerprint: finding.Fingerprint})
+	}
+
+	if err := uc.SuppressionRepo.DeleteBaselines(projectIDs); err != nil {
+		return nil, fmt.Errorf("failed to delete the previous baseline: %w", err)
+	}
+	if err := uc.SuppressionRepo.CreateBaseline(baseline); err != nil {
+		return nil, fmt.Errorf("failed to save baseline: %w", err)
+	}
+	if err := applySuppressions(uc.ProjectRepo, uc.FindingRepo, uc.SuppressionRepo, projectID, projectFindings); err != nil {
+		return nil, err
+	}
+	return baseline, nil
+}
+
+// DeleteBaseline removes the baseline of the project; the findings it accepted are
+// reported again
+func (uc *FindingUsecase) DeleteBaseline(projectID uint) error {
+	projectIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return err
+	}
+	if err := uc.SuppressionRepo.DeleteBaselines(projectIDs); err != nil {
+		return fmt.Errorf("failed to delete baseline: %w", err)
+	}
+	return uc.refreshSuppressions(projectID)
+}
+
+// refreshSuppressions applies the current rules and baseline to the project's findings
+func (uc *FindingUsecase) refreshSuppressions(projectID uint) error {
+	projectFindings, err := uc.FindingRepo.GetManyByProjectID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve findings: %w", err)
+	}
+	return applySuppressions(uc.ProjectRepo, uc.FindingRepo, uc.SuppressionRepo, projectID, projectFindings)
+}
+
+// applySuppressions marks the findings matched by a rule that has not expired, stored or
+// listed in the project's configuration file, or by the baseline as suppressed and clears
+// the mark of the others. The findings are updated in place and only the changed ones are
+// stored.
+func applySuppressions(projectRepo repository.ProjectRepository, findingRepo repository.FindingRepository, suppressionRepo repository.SuppressionRepository, projectID uint, projectFindings []model.Finding) error {
+	projectIDs, err := lineageProjectIDs(projectRepo, projectID)
+	if err != nil {
+		return err
+	}
+	rules, err := suppressionRepo.GetRulesByProjectIDs(projectIDs)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve suppression rules: %w", err)
+	}
+	baseline, err := suppressionRepo.GetLatestBaseline(projectIDs)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve baseline: %w", err)
+	}
+	project, err := projectRepo.GetOneByID(projectID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	rules = append(rules, projectConfig(project).SuppressionRules(project.ConfigPath)...)
+	suppressor := findings.NewSuppressor(rules, baseline, time.Now())
+
+	var changed []model.Finding
+	for i := range projectFindings {
+		finding := &projectFindings[i]
+		suppression, suppressed := suppressor.Match(*finding)
+		if suppressed == finding.Suppressed && suppression.Reason == finding.SuppressionReason &&
+			equalIDs(suppression.RuleID, finding.SuppressionRuleID) {
+			continue
+		}
+		finding.Suppressed = suppressed
+		finding.SuppressionRuleID = suppression.RuleID
+		finding.SuppressionReason = suppression.Reason
+		changed = append(changed, *finding)
+	}
+	if err := findingRepo.UpdateColumns(changed, "suppressed", "suppression_rule_id", "suppression_reason"); err != nil {
+		return fmt.Errorf("failed to save suppressions: %w", err)
+	}
+	return nil
+}
+
+// fingerprintFindings computes the fingerprints of the findings from the content of their
+// files and stores the ones that changed
+func fingerprintFindings(findingRepo repository.FindingRepository, contents map[uint]string, projectFindings []model.Finding) error {
+	var changed []model.Finding
+	for i := range projectFindings {
+		finding := &projectFindings[i]
+		content := ""
+		if finding.ProjectFileID != nil {
+			content = contents[*finding.ProjectFileID]
+		}
+		if fingerprint := findings.Fingerprint(*finding, content); fingerprint != finding.Fingerprint {
+			finding.Fingerprint = fingerprint
+			changed = append(changed, *finding)
+		}
+	}
+	if err := findingRepo.UpdateColumns(changed, "fingerprint"); err != nil {
+		return fmt.Errorf("failed to save fingerprints: %w", err)
+	}
+	return nil
+}
+
+func equalIDs(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func containsID(ids []uint, id uint) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
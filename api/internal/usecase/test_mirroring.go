@@ -0,0 +1,44 @@
+// internal/usecase/test_mirroring.go
+
+package usecase
+
+import (
+	"fmt"
+
+	"evraz_api/internal/discovery"
+	"evraz_api/internal/model"
+)
+
+// testMirroringRule is the rule name of the computed test structure findings
+const testMirroringRule = "TestStructureMirroring"
+
+// saveMirroringFindings replaces the project's previous test mirroring findings with the new gaps
+func (uc *ProjectAnalysisUsecase) saveMirroringFindings(projectID uint, gaps []discovery.MirroringGap, language string) error {
+	if err := uc.FindingRepo.DeleteByProjectIDAndRule(projectID, model.FindingSourceComputed, testMirroringRule); err != nil {
+		return fmt.Errorf("failed to delete previous findings: %w", err)
+	}
+
+	findings := make([]model.Finding, 0, len(gaps))
+	for _, gap := range gaps {
+		fileID := gap.Module.ID
+		finding := model.Finding{
+			ProjectID:     projectID,
+			ProjectFileID: &fileID,
+			Source:        model.FindingSourceComputed,
+			RuleName:      testMirroringRule,
+			Severity:      model.SeverityMedium,
+			Message:       uc.Catalog.T(language, "finding.tests_missing", gap.Module.Path),
+			Path:          gap.Module.Path,
+		}
+		if gap.TestPath != "" {
+			finding.Severity = model.SeverityLow
+			finding.Message = uc.Catalog.T(language, "finding.tests_not_mirrored", gap.Module.Path, gap.TestPath)
+		}
+		findings = append(findings, finding)
+	}
+
+	if err := uc.FindingRepo.CreateMany(findings); err != nil {
+		return fmt.Errorf("failed to save findings: %w", err)
+	}
+	return nil
+}
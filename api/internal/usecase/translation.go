@@ -0,0 +1,275 @@
+// internal/usecase/translation.go
+
+package usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"evraz_api/internal/i18n"
+	"evraz_api/internal/model"
+	"evraz_api/internal/prompts"
+	helper_prompts "evraz_api/internal/prompts/prompts_storage/helpers"
+	"evraz_api/internal/repository"
+	"evraz_api/internal/service"
+	"evraz_api/internal/utils"
+)
+
+// translationBatchSize is the number of results translated with a single LLM call
+const translationBatchSize = 10
+
+type TranslationUsecase struct {
+	ProjectRepo         repository.ProjectRepository
+	ProjectFileRepo     repository.ProjectFileRepository
+	ProjectAnalysisRepo repository.ProjectAnalysisRepository
+	TranslationRepo     repository.AnalysisTranslationRepository
+	MistralService      service.MistralService
+	PromptConstructor   *prompts.PromptConstructor
+	Catalog             *i18n.Catalog
+}
+
+func NewTranslationUsecase(
+	projectRepo repository.ProjectRepository,
+	projectFileRepo repository.ProjectFileRepository,
+	projectAnalysisRepo repository.ProjectAnalysisRepository,
+	translationRepo repository.AnalysisTranslationRepository,
+	mistralService service.MistralService,
+	catalog *i18n.Catalog,
+) *TranslationUsecase {
+	return &TranslationUsecase{
+		ProjectRepo:         projectRepo,
+		ProjectFileRepo:     projectFileRepo,
+		ProjectAnalysisRepo: projectAnalysisRepo,
+		TranslationRepo:     translationRepo,
+		MistralService:      mistralService,
+		PromptConstructor:   prompts.NewPromptConstructor(),
+		Catalog:             catalog,
+	}
+}
+
+// translationItem is a single result sent to and received from the translation prompt
+type translationItem struct {
+	ID              string `json:"id"`
+	Issues          string `json:"issues"`
+	Recommendations string `json:"recommendations"`
+}
+
+// TranslateProject translates every stored project and file result that has no variant in
+// the target language yet and returns the number of created translations
+func (uc *TranslationUsecase) TranslateProject(projectID uint, language string) (int, error) {
+	if !uc.Catalog.IsSupported(language) {
+		return 0, fmt.Errorf("unsupported language: %s", language)
+	}
+	language = uc.Catalog.Resolve(language)
+
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+
+	projectResults, err := uc.ProjectAnalysisRepo.GetResultsByProjectID(project.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve project analysis results: %w", err)
+	}
+	files, err := uc.ProjectFileRepo.GetFilesWithAnalysisByProjectID(project.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve project files: %w", err)
+	}
+
+	// Skip results that are already in the target language or already translated
+	projectResultIDs := make([]uint, 0, len(projectResults))
+	for _, result := range projectResults {
+		projectResultIDs = append(projectResultIDs, result.ID)
+	}
+	existingProject, err := uc.TranslationRepo.GetManyByProjectResultIDs(projectResultIDs, language)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve existing translations: %w", err)
+	}
+	translatedProject := make(map[uint]bool)
+	for _, translation := range existingProject {
+		translatedProject[*translation.ProjectAnalysisResultID] = true
+	}
+
+	var fileResults []model.FileAnalysisResult
+	for _, file := range files {
+		fileResults = append(fileResults, file.FileAnalysisResults...)
+	}
+	fileResultIDs := make([]uint, 0, len(fileResults))
+	for _, result := range fileResults {
+		fileResultIDs = append(fileResultIDs, result.ID)
+	}
+	existingFile, err := uc.TranslationRepo.GetManyByFileResultIDs(fileResultIDs, language)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve existing translations: %w", err)
+	}
+	translatedFile := make(map[uint]bool)
+	for _, translation := range existingFile {
+		translatedFile[*translation.FileAnalysisResultID] = true
+	}
+
+	var items []translationItem
+	for _, result := range projectResults {
+		if result.Language == language || translatedProject[result.ID] {
+			continue
+		}
+		items = append(items, translationItem{
+			ID:              fmt.Sprintf("project:%d", result.ID),
+			Issues:          result.Issues,
+			Recommendations: result.Recommendations,
+		})
+	}
+	for _, result := range fileResults {
+		if result.Language == language || translatedFile[result.ID] {
+			continue
+		}
+		items = append(items, translationItem{
+			ID:              fmt.Sprintf("file:%d", result.ID),
+			Issues:          result.Issues,
+			Recommendations: result.Recommendations,
+		})
+	}
+
+	created := 0
+	for start := 0; start < len(items); start += translationBatchSize {
+		end := start + translationBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		count, err := uc.translateBatch(project.ID, items[start:end], language)
+		if err != nil {
+			return created, err
+		}
+		created += count
+	}
+
+	return created, nil
+}
+
+// translateBatch sends one batch through the translation prompt and stores the variants
+func (uc *TranslationUsecase) translateBatch(projectID uint, items []translationItem, language string) (int, error) {
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode translation batch: %w", err)
+	}
+
+	llmLanguage := uc.Catalog.LLMLanguage(language)
+	data := helper_prompts.TranslateResultsData{
+		TargetLanguage: llmLanguage,
+		Items:          string(itemsJSON),
+	}
+	prompt, err := uc.PromptConstructor.GetPrompt(helper_prompts.TranslateResultsPrompt, data, llmLanguage, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to construct translation prompt: %w", err)
+	}
+
+	reply, gptCallID, err := uc.MistralService.CallMistral(prompt, true, service.Hack, "translation", projectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call Mistral service for translation: %w", err)
+	}
+
+	var response struct {
+		Translations []translationItem `json:"translations"`
+	}
+	if err := utils.ExtractJSON(reply, &response); err != nil {
+		log.Println("Error while extracting Translation Response")
+		return 0, nil
+	}
+
+	requested := make(map[string]bool, len(items))
+	for _, item := range items {
+		requested[item.ID] = true
+	}
+
+	created := 0
+	for _, item := range response.Translations {
+		if !requested[item.ID] {
+			continue
+		}
+		delete(requested, item.ID)
+
+		translation := &model.AnalysisTranslation{
+			Language:        language,
+			Issues:          item.Issues,
+			Recommendations: item.Recommendations,
+			GPTCallID:       &gptCallID,
+		}
+		var resultID uint
+		if _, err := fmt.Sscanf(item.ID, "project:%d", &resultID); err == nil {
+			translation.ProjectAnalysisResultID = &resultID
+		} else if _, err := fmt.Sscanf(item.ID, "file:%d", &resultID); err == nil {
+			translation.FileAnalysisResultID = &resultID
+		} else {
+			continue
+		}
+
+		if err := uc.TranslationRepo.CreateOne(translation); err != nil {
+			return created, fmt.Errorf("failed to save translation for %s: %w", item.ID, err)
+		}
+		created++
+	}
+
+	return created, nil
+}
+
+// LocalizeProjectResults replaces issues and recommendations with their variants in the
+// given language where one exists; results without a translation are returned unchanged
+func (uc *TranslationUsecase) LocalizeProjectResults(results []model.ProjectAnalysisResult, language string) ([]model.ProjectAnalysisResult, error) {
+	ids := make([]uint, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	translations, err := uc.TranslationRepo.GetManyByProjectResultIDs(ids, language)
+	if err != nil {
+		return nil, err
+	}
+	byResultID := make(map[uint]model.AnalysisTranslation, len(translations))
+	for _, translation := range translations {
+		byResultID[*translation.ProjectAnalysisResultID] = translation
+	}
+
+	localized := make([]model.ProjectAnalysisResult, len(results))
+	for i, result := range results {
+		if translation, ok := byResultID[result.ID]; ok {
+			result.Issues = translation.Issues
+			result.Recommendations = translation.Recommendations
+			result.Language = translation.Language
+		}
+		localized[i] = result
+	}
+	return localized, nil
+}
+
+// LocalizeFileResults does the same as LocalizeProjectResults for the results of each file
+func (uc *TranslationUsecase) LocalizeFileResults(files []model.ProjectFile, language string) ([]model.ProjectFile, error) {
+	var ids []uint
+	for _, file := range files {
+		for _, result := range file.FileAnalysisResults {
+			ids = append(ids, result.ID)
+		}
+	}
+	translations, err := uc.TranslationRepo.GetManyByFileResultIDs(ids, language)
+	if err != nil {
+		return nil, err
+	}
+	byResultID := make(map[uint]model.AnalysisTranslation, len(translations))
+	for _, translation := range translations {
+		byResultID[*translation.FileAnalysisResultID] = translation
+	}
+
+	localized := make([]model.ProjectFile, len(files))
+	for i, file := range files {
+		results := make([]model.FileAnalysisResult, len(file.FileAnalysisResults))
+		for j, result := range file.FileAnalysisResults {
+			if translation, ok := byResultID[result.ID]; ok {
+				result.Issues = translation.Issues
+				result.Recommendations = translation.Recommendations
+				result.Language = translation.Language
+			}
+			results[j] = result
+		}
+		file.FileAnalysisResults = results
+		localized[i] = file
+	}
+	return localized, nil
+}
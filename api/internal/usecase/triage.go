@@ -0,0 +1,180 @@
+// internal/usecase/triage.go
+
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"evraz_api/internal/model"
+)
+
+// TriageUpdate changes the status and the assignee of a finding; nil fields are kept
+type TriageUpdate struct {
+	Status   *string
+	Assignee *string
+	Actor    string
+}
+
+// UpdateTriage changes the triage of a finding and records the changes in its audit trail
+func (uc *FindingUsecase) UpdateTriage(projectID, findingID uint, update TriageUpdate) (*model.Finding, error) {
+	finding, err := uc.projectFinding(projectID, findingID)
+	if err != nil {
+		return nil, err
+	}
+	if update.Status != nil && !model.IsFindingStatus(*update.Status) {
+		return nil, fmt.Errorf("unknown finding status: %s", *update.Status)
+	}
+
+	var events []model.FindingEvent
+	record := func(action, oldValue, newValue string) {
+		events = append(events, model.FindingEvent{
+			FindingID: finding.ID,
+			ProjectID: finding.ProjectID,
+			Actor:     update.Actor,
+			Action:    action,
+			OldValue:  oldValue,
+			NewValue:  newValue,
+		})
+	}
+	if update.Status != nil && *update.Status != finding.TriageStatus() {
+		record(model.FindingEventStatus, finding.TriageStatus(), *update.Status)
+		finding.Status = *update.Status
+	}
+	if update.Assignee != nil && *update.Assignee != finding.Assignee {
+		record(model.FindingEventAssignee, finding.Assignee, *update.Assignee)
+		finding.Assignee = *update.Assignee
+	}
+	if len(events) == 0 {
+		return finding, nil
+	}
+
+	now := time.Now()
+	finding.TriagedAt = &now
+	if err := uc.FindingRepo.UpdateColumns([]model.Finding{*finding}, "status", "assignee", "triaged_at"); err != nil {
+		return nil, fmt.Errorf("failed to update finding: %w", err)
+	}
+	if err := uc.FindingTriageRepo.CreateEvents(events); err != nil {
+		return nil, fmt.Errorf("failed to record triage: %w", err)
+	}
+	return finding, nil
+}
+
+// AddComment adds a comment to the discussion of a finding
+func (uc *FindingUsecase) AddComment(projectID, findingID uint, author, body string) (*model.FindingComment, error) {
+	finding, err := uc.projectFinding(projectID, findingID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &model.FindingComment{
+		FindingID: finding.ID,
+		ProjectID: finding.ProjectID,
+		Author:    author,
+		Body:      body,
+	}
+	if err := uc.FindingTriageRepo.CreateComment(comment); err != nil {
+		return nil, fmt.Errorf("failed to save comment: %w", err)
+	}
+	event := model.FindingEvent{
+		FindingID: finding.ID,
+		ProjectID: finding.ProjectID,
+		Actor:     author,
+		Action:    model.FindingEventComment,
+		NewValue:  body,
+	}
+	if err := uc.FindingTriageRepo.CreateEvents([]model.FindingEvent{event}); err != nil {
+		return nil, fmt.Errorf("failed to record comment: %w", err)
+	}
+	return comment, nil
+}
+
+// GetComments returns the discussion of a finding in order
+func (uc *FindingUsecase) GetComments(projectID, findingID uint) ([]model.FindingComment, error) {
+	if _, err := uc.projectFinding(projectID, findingID); err != nil {
+		return nil, err
+	}
+	comments, err := uc.FindingTriageRepo.GetCommentsByFindingID(findingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+	return comments, nil
+}
+
+// GetHistory returns the audit trail of a finding in order
+func (uc *FindingUsecase) GetHistory(projectID, findingID uint) ([]model.FindingEvent, error) {
+	if _, err := uc.projectFinding(projectID, findingID); err != nil {
+		return nil, err
+	}
+	events, err := uc.FindingTriageRepo.GetEventsByFindingID(findingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve finding history: %w", err)
+	}
+	return events, nil
+}
+
+// projectFinding returns the finding when it belongs to the project
+func (uc *FindingUsecase) projectFinding(projectID, findingID uint) (*model.Finding, error) {
+	finding, err := uc.FindingRepo.GetOneByID(findingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve finding: %w", err)
+	}
+	if finding.ProjectID != projectID {
+		return nil, errors.New("the finding does not belong to the project")
+	}
+	return finding, nil
+}
+
+// carryOverTriage gives findings nobody triaged yet the status and assignee of the latest
+// triaged finding with the same fingerprint in any version of the project, so that a
+// problem marked as a false positive stays one when a later run raises it again
+func (uc *ProjectAnalysisUsecase) carryOverTriage(projectID uint, projectFindings []model.Finding) error {
+	projectIDs, err := lineageProjectIDs(uc.ProjectRepo, projectID)
+	if err != nil {
+		return err
+	}
+	triaged, err := uc.FindingRepo.GetTriagedByProjectIDs(projectIDs)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve triaged findings: %w", err)
+	}
+	latest := make(map[string]model.Finding, len(triaged))
+	for _, finding := range triaged {
+		latest[finding.Fingerprint] = finding
+	}
+
+	var carried []model.Finding
+	var events []model.FindingEvent
+	for i := range projectFindings {
+		finding := &projectFindings[i]
+		if finding.TriagedAt != nil || finding.Fingerprint == "" {
+			continue
+		}
+		source, ok := latest[finding.Fingerprint]
+		if !ok || source.ID == finding.ID {
+			continue
+		}
+
+		events = append(events, model.FindingEvent{
+			FindingID: finding.ID,
+			ProjectID: finding.ProjectID,
+			Actor:     model.FindingActorSystem,
+			Action:    model.FindingEventCarriedOver,
+			OldValue:  finding.TriageStatus(),
+			NewValue:  source.TriageStatus(),
+			Detail:    fmt.Sprintf("finding %d", source.ID),
+		})
+		finding.Status = source.Status
+		finding.Assignee = source.Assignee
+		finding.TriagedAt = source.TriagedAt
+		carried = append(carried, *finding)
+	}
+
+	if err := uc.FindingRepo.UpdateColumns(carried, "status", "assignee", "triaged_at"); err != nil {
+		return fmt.Errorf("failed to carry over triage: %w", err)
+	}
+	if err := uc.FindingTriageRepo.CreateEvents(events); err != nil {
+		return fmt.Errorf("failed to record carried over triage: %w", err)
+	}
+	return nil
+}
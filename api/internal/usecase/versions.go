@@ -0,0 +1,261 @@
+// internal/usecase/versions.go
+
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"evraz_api/internal/model"
+	"evraz_api/internal/repository"
+	"evraz_api/internal/utils"
+)
+
+// latestVersion returns the newest version of the project the given version belongs to
+func (uc *ProjectUsecase) latestVersion(projectID uint) (*model.Project, error) {
+	versions, err := uc.GetVersions(projectID)
+	if err != nil {
+		return nil, err
+	}
+	return &versions[len(versions)-1], nil
+}
+
+// GetVersions returns all versions of the project the given version belongs to, oldest first
+func (uc *ProjectUsecase) GetVersions(projectID uint) ([]model.Project, error) {
+	project, err := uc.ProjectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	rootID := project.ID
+	if project.RootProjectID != nil {
+		rootID = *project.RootProjectID
+	}
+
+	versions, err := uc.ProjectRepo.GetVersions(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return []model.Project{*project}, nil
+	}
+	return versions, nil
+}
+
+// lineageProjectIDs returns the IDs of all versions of the project the given version
+// belongs to, the given version first
+func lineageProjectIDs(projectRepo repository.ProjectRepository, projectID uint) ([]uint, error) {
+	project, err := projectRepo.GetOneByID(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	rootID := project.ID
+	if project.RootProjectID != nil {
+		rootID = *project.RootProjectID
+	}
+	versions, err := projectRepo.GetVersions(rootID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve project versions: %w", err)
+	}
+
+	ids := []uint{project.ID}
+	for _, version := range versions {
+		if version.ID != project.ID {
+			ids = append(ids, version.ID)
+		}
+	}
+	return ids, nil
+}
+
+// carryOver copies the analysis results, summaries and review findings of files whose
+// content did not change since the previous version, so that only new and modified files
+// are sent to the LLM again. Review findings of project-level prompts are copied as well;
+// they are replaced when a prompt runs again because its input changed.
+func (uc *ProjectUsecase) carryOver(previous, project *model.Project, files []model.ProjectFile) error {
+	previousFiles, err := uc.ProjectFileRepo.GetFilesByProjectID(previous.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve files of the previous version: %w", err)
+	}
+	previousByPath := make(map[string]model.ProjectFile, len(previousFiles))
+	for _, file := range previousFiles {
+		previousByPath[file.Path] = file
+	}
+
+	// Previous file ID -> file of the new version
+	carried := make(map[uint]uint)
+	for i := range files {
+		file := &files[i]
+		previousFile, ok := previousByPath[file.Path]
+		if !ok || previousFile.ContentHash == "" || previousFile.ContentHash != file.ContentHash {
+			continue
+		}
+
+		file.CarriedFromID = &previousFile.ID
+		file.WasAnalyzed = previousFile.WasAnalyzed
+		file.GPTCallID = previousFile.GPTCallID
+		file.Summary = previousFile.Summary
+		file.SummaryGPTCallID = previousFile.SummaryGPTCallID
+		if err := uc.ProjectFileRepo.UpdateOneByID(file); err != nil {
+			return fmt.Errorf("failed to update file %s: %w", file.Path, err)
+		}
+		carried[previousFile.ID] = file.ID
+
+		results, err := uc.FileAnalysisRepo.GetManyByFileID(previousFile.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve results of %s: %w", file.Path, err)
+		}
+		// Only the latest result of every prompt and language is current; superseded re-runs
+		// stay with the previous version
+		type resultKey struct{ promptName, language string }
+		latest := make(map[resultKey]model.FileAnalysisResult)
+		var order []resultKey
+		for _, result := range results {
+			key := resultKey{result.PromptName, result.Language}
+			previousResult, ok := latest[key]
+			if !ok {
+				order = append(order, key)
+			}
+			if !ok || result.ID > previousResult.ID {
+				latest[key] = result
+			}
+		}
+		copies := make([]model.FileAnalysisResult, len(order))
+		for j, key := range order {
+			result := latest[key]
+			copies[j] = model.FileAnalysisResult{
+				PromptName:      result.PromptName,
+				Compliance:      result.Compliance,
+				Issues:          result.Issues,
+				Recommendations: result.Recommendations,
+				Language:        result.Language,
+				ProjectFileID:   file.ID,
+			}
+		}
+		if err := uc.FileAnalysisRepo.CreateMany(copies); err != nil {
+			return fmt.Errorf("failed to copy results of %s: %w", file.Path, err)
+		}
+	}
+
+	return uc.carryOverFindings(previous.ID, project.ID, carried)
+}
+
+func (uc *ProjectUsecase) carryOverFindings(previousID, projectID uint, carried map[uint]uint) error {
+	previousFindings, err := uc.FindingRepo.GetManyByProjectID(previousID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve findings of the previous version: %w", err)
+	}
+
+	var copies []model.Finding
+	var originals []model.Finding
+	for _, finding := range previousFindings {
+		if finding.Source != model.FindingSourceLLM {
+			continue
+		}
+		var projectFileID *uint
+		if finding.ProjectFileID != nil {
+			fileID, ok := carried[*finding.ProjectFileID]
+			if !ok {
+				continue
+			}
+			projectFileID = &fileID
+		}
+
+		originals = append(originals, finding)
+		finding.ID = 0
+		finding.CreatedAt, finding.UpdatedAt = time.Time{}, time.Time{}
+		finding.ProjectID = projectID
+		finding.ProjectFileID = projectFileID
+		finding.AnalysisRunID = nil
+		finding.CanonicalID = nil
+		copies = append(copies, finding)
+	}
+	if len(copies) == 0 {
+		return nil
+	}
+	if err := uc.FindingRepo.CreateMany(copies); err != nil {
+		return fmt.Errorf("failed to copy findings: %w", err)
+	}
+
+	// Point copied duplicates at the copies of their canonical findings
+	copiedIDs := make(map[uint]uint, len(copies))
+	for i := range copies {
+		copiedIDs[originals[i].ID] = copies[i].ID
+	}
+	var duplicates []model.Finding
+	for i := range copies {
+		if originals[i].CanonicalID == nil {
+			continue
+		}
+		if canonicalID, ok := copiedIDs[*originals[i].CanonicalID]; ok {
+			copies[i].CanonicalID = &canonicalID
+			duplicates = append(duplicates, copies[i])
+		}
+	}
+	if err := uc.FindingRepo.UpdateColumns(duplicates, "canonical_id"); err != nil {
+		return fmt.Errorf("failed to link copied duplicates: %w", err)
+	}
+	return nil
+}
+
+// reuseProjectResult copies the result of a project-level prompt from the previous
+// version when it was produced from the same input. Results of this version are never
+// reused, so that analyzing a version again re-runs its checks.
+func (uc *ProjectAnalysisUsecase) reuseProjectResult(project *model.Project, promptName, inputHash string) (bool, error) {
+	if project.PreviousVersionID == nil {
+		return false, nil
+	}
+
+	previous, err := uc.ProjectAnalysisRepo.GetOneByInputHash(*project.PreviousVersionID, promptName, inputHash)
+	if err != nil || previous == nil {
+		return false, err
+	}
+	result := &model.ProjectAnalysisResult{
+		ProjectID:       project.ID,
+		PromptName:      previous.PromptName,
+		Compliance:      previous.Compliance,
+		Issues:          previous.Issues,
+		Recommendations: previous.Recommendations,
+		Language:        previous.Language,
+		Status:          previous.Status,
+		StatusReason:    previous.StatusReason,
+		InputHash:       previous.InputHash,
+	}
+	if err := uc.ProjectAnalysisRepo.CreateOne(result); err != nil {
+		return false, fmt.Errorf("failed to copy result of %s: %w", promptName, err)
+	}
+	return true, nil
+}
+
+// filesInput describes the content of every file by its hash. Agent checks can read any
+// file of the project, so their input covers the files besides the prompt.
+func filesInput(files []model.ProjectFile) string {
+	entries := make([]string, len(files))
+	for i, file := range files {
+		hash := file.ContentHash
+		if hash == "" {
+			hash = utils.ContentHash(file.Content)
+		}
+		entries[i] = file.Path + " " + hash
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, "\n")
+}
+
+// hasCarriedResults reports whether the file was carried over unchanged from the previous
+// version together with results in the language
+func (uc *ProjectAnalysisUsecase) hasCarriedResults(file model.ProjectFile, language string) bool {
+	if file.CarriedFromID == nil || !file.WasAnalyzed {
+		return false
+	}
+	results, err := uc.FileAnalysisRepo.GetManyByFileID(file.ID)
+	if err != nil {
+		return false
+	}
+	for _, result := range results {
+		if result.Language == language {
+			return true
+		}
+	}
+	return false
+}
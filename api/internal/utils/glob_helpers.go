@@ -0,0 +1,58 @@
+// internal/utils/glob_helpers.go
+
+package utils
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Glob matches slash-separated paths: * and ? do not cross directories, ** matches any
+// number of them. A glob without a slash matches the file name in any directory, as in
+// .gitignore.
+type Glob struct {
+	pattern  string
+	compiled *regexp.Regexp
+}
+
+// CompileGlob prepares a path glob for matching
+func CompileGlob(pattern string) (*Glob, error) {
+	var builder strings.Builder
+	builder.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch char := pattern[i]; char {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					i++
+					builder.WriteString("(?:.*/)?")
+				} else {
+					builder.WriteString(".*")
+				}
+			} else {
+				builder.WriteString("[^/]*")
+			}
+		case '?':
+			builder.WriteString("[^/]")
+		default:
+			builder.WriteString(regexp.QuoteMeta(string(char)))
+		}
+	}
+	builder.WriteString("$")
+	compiled, err := regexp.Compile(builder.String())
+	if err != nil {
+		return nil, err
+	}
+	return &Glob{pattern: pattern, compiled: compiled}, nil
+}
+
+// Match reports whether the path matches the glob
+func (g *Glob) Match(filePath string) bool {
+	filePath = strings.TrimPrefix(filePath, "./")
+	if !strings.Contains(g.pattern, "/") {
+		filePath = path.Base(filePath)
+	}
+	return g.compiled.MatchString(filePath)
+}
@@ -0,0 +1,14 @@
+// internal/utils/hash_helpers.go
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ContentHash returns the hex-encoded SHA-256 of the content
+func ContentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
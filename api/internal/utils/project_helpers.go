@@ -4,6 +4,7 @@ package utils
 
 import (
 	"evraz_api/internal/model"
+	"path/filepath"
 	"strings"
 )
 
@@ -22,3 +23,29 @@ func CheckIfProjectContainsDatetime(project *model.Project) bool {
 	// Implement logic to check if the project handles date and time operations
 	return strings.Contains(project.Tree, "datetime") || strings.Contains(project.Tree, "time")
 }
+
+// DetectProgrammingLanguage returns the language with the most source files among the paths
+func DetectProgrammingLanguage(paths []string) string {
+	languagesByExtension := map[string]string{
+		".py": "Python",
+		".cs": "C#",
+		".ts": "Typescript",
+	}
+
+	counts := make(map[string]int)
+	for _, path := range paths {
+		if language, ok := languagesByExtension[filepath.Ext(path)]; ok {
+			counts[language]++
+		}
+	}
+
+	detected := "Unknown"
+	best := 0
+	for _, language := range []string{"Python", "C#", "Typescript"} {
+		if counts[language] > best {
+			detected = language
+			best = counts[language]
+		}
+	}
+	return detected
+}
@@ -0,0 +1,20 @@
+// internal/utils/string_helpers.go
+
+package utils
+
+import "unicode/utf8"
+
+// TruncateBytes returns the longest prefix of s of at most n bytes that does not split a
+// UTF-8 encoded rune
+func TruncateBytes(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}